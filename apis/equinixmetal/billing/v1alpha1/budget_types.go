@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+// TypeWarning resources describe a condition of their external resource that
+// calls for attention, without preventing them from being ready.
+const TypeWarning xpv1.ConditionType = "Warning"
+
+// Reasons a Budget does or does not warn.
+const (
+	ReasonSpendThresholdExceeded xpv1.ConditionReason = "SpendThresholdExceeded"
+	ReasonWithinSpendThreshold   xpv1.ConditionReason = "WithinSpendThreshold"
+)
+
+// SpendThresholdExceeded returns a condition indicating the month-to-date
+// spend of a project exceeds the monthly threshold of its Budget.
+func SpendThresholdExceeded(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeWarning,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSpendThresholdExceeded,
+		Message:            msg,
+	}
+}
+
+// WithinSpendThreshold returns a condition indicating the month-to-date spend
+// of a project does not exceed the monthly threshold of its Budget.
+func WithinSpendThreshold() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeWarning,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWithinSpendThreshold,
+	}
+}
+
+// BudgetParameters define the monthly spend threshold of an Equinix Metal
+// project.
+type BudgetParameters struct {
+	// ProjectID is the ID of the project whose spend is observed. It
+	// defaults to the project of the ProviderConfig.
+	// +optional
+	// +immutable
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+	ProjectID *string `json:"projectID,omitempty"`
+
+	// ProjectIDRef references a Device of the equinixmetal.crossplane.io
+	// API groups whose project is observed.
+	// +optional
+	// +immutable
+	ProjectIDRef *xpv1.Reference `json:"projectIDRef,omitempty"`
+
+	// ProjectIDSelector selects a Device whose project is observed.
+	// +optional
+	ProjectIDSelector *xpv1.Selector `json:"projectIDSelector,omitempty"`
+
+	// MonthlyThreshold is the spend of the project in a calendar month, in
+	// US dollars, above which the Budget warns.
+	// +kubebuilder:validation:Minimum=1
+	MonthlyThreshold int64 `json:"monthlyThreshold"`
+}
+
+// BudgetObservation is the observed spend of an Equinix Metal project.
+type BudgetObservation struct {
+	// Month the spend was observed in, such as 2021-06. Months start at
+	// midnight UTC.
+	Month string `json:"month,omitempty"`
+
+	// MonthToDateSpend of the project in US dollars, such as 123.45, as
+	// reported by the usage API of Equinix Metal.
+	MonthToDateSpend string `json:"monthToDateSpend,omitempty"`
+}
+
+// BudgetSpec defines the desired state of a Budget.
+type BudgetSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       BudgetParameters `json:"forProvider"`
+}
+
+// BudgetStatus defines the observed state of a Budget.
+type BudgetStatus struct {
+	xpv1.ResourceStatus      `json:",inline"`
+	packetv1beta1.SyncStatus `json:",inline"`
+	AtProvider               BudgetObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Budget is an observe-only managed resource that compares the spend of an
+// Equinix Metal project in the current month with a monthly threshold. Its
+// Warning condition is true, and a Warning event is recorded, once the spend
+// exceeds the threshold.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="WARNING",type="string",JSONPath=".status.conditions[?(@.type=='Warning')].status"
+// +kubebuilder:printcolumn:name="SPEND",type="string",JSONPath=".status.atProvider.monthToDateSpend"
+// +kubebuilder:printcolumn:name="THRESHOLD",type="integer",JSONPath=".spec.forProvider.monthlyThreshold"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix,metal},shortName=metalbudget
+type Budget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BudgetSpec   `json:"spec"`
+	Status BudgetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BudgetList contains a list of Budgets
+type BudgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Budget `json:"items"`
+}
+
+// GetSyncStatus of this Budget.
+func (mg *Budget) GetSyncStatus() *packetv1beta1.SyncStatus {
+	return &mg.Status.SyncStatus
+}
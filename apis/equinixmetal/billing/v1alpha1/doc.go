@@ -0,0 +1,23 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains the Equinix Metal billing resources served in the
+// billing.equinixmetal.crossplane.io API group. They are only observed; the
+// provider never changes the billing of a project.
+// +kubebuilder:object:generate=true
+// +groupName=billing.equinixmetal.crossplane.io
+// +versionName=v1alpha1
+package v1alpha1
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	serverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+)
+
+func TestBudgetResolveReferences(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			d, ok := obj.(*serverv1beta1.Device)
+			if !ok {
+				return errors.Errorf("unexpected %T", obj)
+			}
+			d.Status.AtProvider.ProjectID = "my-project"
+			return nil
+		},
+	}
+
+	b := &Budget{}
+	b.Spec.ForProvider.ProjectIDRef = &xpv1.Reference{Name: "device"}
+	if err := b.ResolveReferences(context.Background(), kube); err != nil {
+		t.Fatalf("ResolveReferences(...): %s", err)
+	}
+	if diff := cmp.Diff(reference.ToPtrValue("my-project"), b.Spec.ForProvider.ProjectID); diff != "" {
+		t.Errorf("ResolveReferences(...): -want projectID, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+// Roles of the members of an organization.
+const (
+	// RoleMember is held by every member of an organization.
+	RoleMember = "member"
+
+	// RoleOwner is held by the owners of an organization.
+	RoleOwner = "owner"
+)
+
+// MemberParameters identify the member of an Equinix Metal organization to
+// observe.
+type MemberParameters struct {
+	// OrganizationID is the ID of the organization the member belongs to.
+	// It is required unless it is resolved from OrganizationIDRef or
+	// OrganizationIDSelector.
+	// +optional
+	// +immutable
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+	OrganizationID string `json:"organizationID,omitempty"`
+
+	// OrganizationIDRef references another Member whose organization the
+	// member belongs to.
+	// +optional
+	// +immutable
+	OrganizationIDRef *xpv1.Reference `json:"organizationIDRef,omitempty"`
+
+	// OrganizationIDSelector selects another Member whose organization the
+	// member belongs to.
+	// +optional
+	OrganizationIDSelector *xpv1.Selector `json:"organizationIDSelector,omitempty"`
+
+	// Email is an email address of the user of the member.
+	// +immutable
+	Email string `json:"email"`
+}
+
+// MemberObservation is the observed state of the member of an Equinix Metal
+// organization.
+type MemberObservation struct {
+	// UserID is the ID of the user of the member.
+	UserID string `json:"userID,omitempty"`
+
+	// FullName of the user of the member.
+	FullName string `json:"fullName,omitempty"`
+
+	// Roles of the member in the organization. Every member is a member, and
+	// its owners are owners too; finer roles are not reported by the
+	// Equinix Metal API.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// MemberSpec defines the desired state of a Member.
+type MemberSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       MemberParameters `json:"forProvider"`
+}
+
+// MemberStatus defines the observed state of a Member.
+type MemberStatus struct {
+	xpv1.ResourceStatus      `json:",inline"`
+	packetv1beta1.SyncStatus `json:",inline"`
+	AtProvider               MemberObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Member is an observe-only managed resource that represents the member of
+// an Equinix Metal organization. It is ready while the organization has a
+// member with the email address, and never invites, changes or removes
+// members.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EMAIL",type="string",JSONPath=".spec.forProvider.email"
+// +kubebuilder:printcolumn:name="ROLES",type="string",JSONPath=".status.atProvider.roles"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name",priority=1
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix,metal},shortName=metalmember
+type Member struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MemberSpec   `json:"spec"`
+	Status MemberStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MemberList contains a list of Members
+type MemberList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Member `json:"items"`
+}
+
+// GetSyncStatus of this Member.
+func (mg *Member) GetSyncStatus() *packetv1beta1.SyncStatus {
+	return &mg.Status.SyncStatus
+}
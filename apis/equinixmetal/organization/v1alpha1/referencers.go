@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// OrganizationID extracts the ID of the organization of a Member.
+func OrganizationID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		m, ok := mg.(*Member)
+		if !ok {
+			return ""
+		}
+		return m.Spec.ForProvider.OrganizationID
+	}
+}
+
+// ResolveReferences of this Member. Its organization resolves to the
+// organization of another Member.
+func (mg *Member) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.forProvider.organizationID
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.OrganizationID,
+		Reference:    mg.Spec.ForProvider.OrganizationIDRef,
+		Selector:     mg.Spec.ForProvider.OrganizationIDSelector,
+		To:           reference.To{Managed: &Member{}, List: &MemberList{}},
+		Extract:      OrganizationID(),
+	})
+	if err != nil {
+		return err
+	}
+	mg.Spec.ForProvider.OrganizationID = rsp.ResolvedValue
+	mg.Spec.ForProvider.OrganizationIDRef = rsp.ResolvedReference
+
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+)
+
+var _ conversion.Convertible = &Assignment{}
+
+// ConvertTo converts this Assignment to the Assignment of the ports.metal.equinix.com
+// API group, with which it shares its schema.
+func (a *Assignment) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*portsv1alpha1.Assignment)
+	a.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	a.Spec.DeepCopyInto(&dst.Spec)
+	a.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// ConvertFrom converts the supplied Assignment of the ports.metal.equinix.com
+// API group to this Assignment.
+func (a *Assignment) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*portsv1alpha1.Assignment)
+	src.ObjectMeta.DeepCopyInto(&a.ObjectMeta)
+	src.Spec.DeepCopyInto(&a.Spec)
+	src.Status.DeepCopyInto(&a.Status)
+	return nil
+}
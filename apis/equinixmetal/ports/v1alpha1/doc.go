@@ -0,0 +1,23 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 contains the Equinix Metal Assignment served in the
+// ports.equinixmetal.crossplane.io API group. It is an alias of the Assignment
+// of the ports.metal.equinix.com API group, reconciled by the same controller.
+// +kubebuilder:object:generate=true
+// +groupName=ports.equinixmetal.crossplane.io
+// +versionName=v1alpha1
+package v1alpha1
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	serverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/vlan/v1alpha1"
+)
+
+func TestAssignmentResolveReferences(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *serverv1beta1.Device:
+				o.Status.AtProvider.ID = "my-device"
+			case *vlanv1alpha1.VirtualNetwork:
+				o.Status.AtProvider.ID = "my-vlan"
+			default:
+				return errors.Errorf("unexpected %T", obj)
+			}
+			return nil
+		},
+	}
+
+	a := &Assignment{}
+	a.Spec.ForProvider.DeviceIDRef = &xpv1.Reference{Name: "device"}
+	a.Spec.ForProvider.VirtualNetworkIDRef = &xpv1.Reference{Name: "vlan"}
+	if err := a.ResolveReferences(context.Background(), kube); err != nil {
+		t.Fatalf("ResolveReferences(...): %s", err)
+	}
+	if diff := cmp.Diff("my-device", a.Spec.ForProvider.DeviceID); diff != "" {
+		t.Errorf("ResolveReferences(...): -want deviceId, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("my-vlan", a.Spec.ForProvider.VirtualNetworkID); diff != "" {
+		t.Errorf("ResolveReferences(...): -want virtualNetworkId, +got:\n%s", diff)
+	}
+}
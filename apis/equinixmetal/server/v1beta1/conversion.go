@@ -0,0 +1,45 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+var _ conversion.Convertible = &Device{}
+
+// ConvertTo converts this Device to the hub version of the Devices of the
+// server.metal.equinix.com API group, with which it shares its schema.
+func (d *Device) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1alpha2.Device)
+	d.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	d.Spec.DeepCopyInto(&dst.Spec)
+	d.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// ConvertFrom converts the supplied hub version of the Devices of the
+// server.metal.equinix.com API group to this Device.
+func (d *Device) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1alpha2.Device)
+	src.ObjectMeta.DeepCopyInto(&d.ObjectMeta)
+	src.Spec.DeepCopyInto(&d.Spec)
+	src.Status.DeepCopyInto(&d.Status)
+	return nil
+}
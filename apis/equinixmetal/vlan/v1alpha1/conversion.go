@@ -0,0 +1,45 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+var _ conversion.Convertible = &VirtualNetwork{}
+
+// ConvertTo converts this VirtualNetwork to the VirtualNetwork of the vlan.metal.equinix.com
+// API group, with which it shares its schema.
+func (v *VirtualNetwork) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*vlanv1alpha1.VirtualNetwork)
+	v.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	v.Spec.DeepCopyInto(&dst.Spec)
+	v.Status.DeepCopyInto(&dst.Status)
+	return nil
+}
+
+// ConvertFrom converts the supplied VirtualNetwork of the vlan.metal.equinix.com
+// API group to this VirtualNetwork.
+func (v *VirtualNetwork) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*vlanv1alpha1.VirtualNetwork)
+	src.ObjectMeta.DeepCopyInto(&v.ObjectMeta)
+	src.Spec.DeepCopyInto(&v.Spec)
+	src.Status.DeepCopyInto(&v.Status)
+	return nil
+}
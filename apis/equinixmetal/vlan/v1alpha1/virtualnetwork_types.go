@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+// +kubebuilder:object:root=true
+
+// A VirtualNetwork is a managed resource that represents an Equinix Metal
+// VirtualNetwork. It shares its schema with the VirtualNetwork of the
+// vlan.metal.equinix.com API group.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
+// +kubebuilder:printcolumn:name="VXLAN",type="string",JSONPath=".status.atProvider.vxlan"
+// +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".status.atProvider.metro"
+// +kubebuilder:printcolumn:name="FACILITY",type="string",JSONPath=".status.atProvider.facilityCode",priority=1
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.deletionPolicy"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix,metal}
+type VirtualNetwork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   vlanv1alpha1.VirtualNetworkSpec   `json:"spec"`
+	Status vlanv1alpha1.VirtualNetworkStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VirtualNetworkList contains a list of VirtualNetworks
+type VirtualNetworkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualNetwork `json:"items"`
+}
+
+// GetSyncStatus of this VirtualNetwork.
+func (mg *VirtualNetwork) GetSyncStatus() *packetv1beta1.SyncStatus {
+	return &mg.Status.SyncStatus
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// A VLANPoolSpec defines the VXLAN IDs a VLANPool allocates.
+type VLANPoolSpec struct {
+	// Start is the first VXLAN ID of the pool.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=3999
+	Start int `json:"start"`
+
+	// End is the last VXLAN ID of the pool.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=3999
+	End int `json:"end"`
+
+	// Metro of the VirtualNetworks the pool allocates VXLAN IDs to. IDs are
+	// not allocated to VirtualNetworks of any other metro.
+	// +optional
+	Metro string `json:"metro,omitempty"`
+}
+
+// A VXLANAllocation is a VXLAN ID allocated to a VirtualNetwork.
+type VXLANAllocation struct {
+	// VXLAN ID allocated to the VirtualNetwork.
+	VXLAN int `json:"vxlan"`
+
+	// Name of the VirtualNetwork.
+	Name string `json:"name"`
+
+	// UID of the VirtualNetwork, which tells apart VirtualNetworks of the same
+	// name in different API groups.
+	UID types.UID `json:"uid"`
+}
+
+// A VLANPoolStatus reflects the VXLAN IDs a VLANPool allocated.
+type VLANPoolStatus struct {
+	// Allocations of the pool, sorted by VXLAN ID.
+	// +optional
+	Allocations []VXLANAllocation `json:"allocations,omitempty"`
+
+	// Allocated is the number of allocated VXLAN IDs.
+	// +optional
+	Allocated int `json:"allocated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A VLANPool owns a range of VXLAN IDs and allocates them to the
+// VirtualNetworks that reference it and specify no VXLAN ID, so that teams
+// creating VirtualNetworks in the same metro do not choose the same ID.
+// +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".spec.metro"
+// +kubebuilder:printcolumn:name="START",type="integer",JSONPath=".spec.start"
+// +kubebuilder:printcolumn:name="END",type="integer",JSONPath=".spec.end"
+// +kubebuilder:printcolumn:name="ALLOCATED",type="integer",JSONPath=".status.allocated"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,equinix,metal}
+type VLANPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VLANPoolSpec   `json:"spec"`
+	Status VLANPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VLANPoolList contains a list of VLANPools
+type VLANPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VLANPool `json:"items"`
+}
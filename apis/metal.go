@@ -20,6 +20,11 @@ package apis
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 
+	embillingv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/billing/v1alpha1"
+	emorganizationv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/organization/v1alpha1"
+	emportsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/ports/v1alpha1"
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	emvlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/vlan/v1alpha1"
 	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
 	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
 	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
@@ -33,6 +38,11 @@ func init() {
 		portsv1alpha1.SchemeBuilder.AddToScheme,
 		serverv1alpha2.SchemeBuilder.AddToScheme,
 		vlanv1alpha1.SchemeBuilder.AddToScheme,
+		embillingv1alpha1.SchemeBuilder.AddToScheme,
+		emorganizationv1alpha1.SchemeBuilder.AddToScheme,
+		emportsv1alpha1.SchemeBuilder.AddToScheme,
+		emserverv1beta1.SchemeBuilder.AddToScheme,
+		emvlanv1alpha1.SchemeBuilder.AddToScheme,
 	)
 }
 
@@ -19,6 +19,8 @@ package v1alpha1
 import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 )
 
 // AssignmentSpec defines the desired state of Assignment
@@ -29,7 +31,18 @@ type AssignmentSpec struct {
 
 // AssignmentStatus defines the observed state of Assignment
 type AssignmentStatus struct {
-	xpv1.ResourceStatus `json:",inline"`
+	xpv1.ResourceStatus      `json:",inline"`
+	packetv1beta1.SyncStatus `json:",inline"`
+	AtProvider               AssignmentObservation `json:"atProvider,omitempty"`
+}
+
+// AssignmentObservation is used to reflect in the Kubernetes API, the observed
+// state of the Assignment from the Equinix Metal API.
+type AssignmentObservation struct {
+	// LastRequestID is the ID of the most recent Equinix Metal API request
+	// for the assignment that failed. Reference it in support tickets.
+	// +optional
+	LastRequestID string `json:"lastRequestID,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -38,10 +51,10 @@ type AssignmentStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
-// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.deletionPolicy"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix,metal},shortName=vnetassign
 type Assignment struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -66,6 +79,7 @@ type AssignmentList struct {
 // LateInitialization should update the parameter after creation.
 type AssignmentParameters struct {
 	// +immutable
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
 	DeviceID string `json:"deviceId,omitempty"`
 
 	// +optional
@@ -76,15 +90,30 @@ type AssignmentParameters struct {
 	DeviceIDSelector *xpv1.Selector `json:"deviceIdSelector,omitempty"`
 
 	// +immutable
+	// +kubebuilder:validation:Pattern=`^(eth|bond)[0-9]+$`
 	Name string `json:"name"`
 
+	// VirtualNetworkID is the UUID of the VirtualNetwork assigned to the port.
+	// It need not be known in advance; it is resolved from a VirtualNetwork
+	// managed resource when VirtualNetworkIDRef or VirtualNetworkIDSelector is
+	// set.
 	// +immutable
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
 	VirtualNetworkID string `json:"virtualNetworkId,omitempty"`
 
+	// VirtualNetworkIDRef references the VirtualNetwork managed resource to
+	// resolve VirtualNetworkID from.
 	// +optional
 	// +immutable
 	VirtualNetworkIDRef *xpv1.Reference `json:"virtualNetworkIdRef,omitempty"`
 
+	// VirtualNetworkIDSelector selects the VirtualNetwork managed resource to
+	// resolve VirtualNetworkID from.
 	// +optional
 	VirtualNetworkIDSelector *xpv1.Selector `json:"virtualNetworkIdSelector,omitempty"`
 }
+
+// GetSyncStatus of this Assignment.
+func (mg *Assignment) GetSyncStatus() *packetv1beta1.SyncStatus {
+	return &mg.Status.SyncStatus
+}
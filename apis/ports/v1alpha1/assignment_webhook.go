@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var _ webhook.Validator = &Assignment{}
+
+// ValidateCreate enforces the cross-field rules of a new Assignment.
+func (a *Assignment) ValidateCreate() error {
+	return a.invalid(a.Spec.ForProvider.validate(field.NewPath("spec", "forProvider")))
+}
+
+// ValidateUpdate enforces the cross-field rules of an updated Assignment and
+// rejects changes to immutable fields.
+func (a *Assignment) ValidateUpdate(old runtime.Object) error {
+	p := field.NewPath("spec", "forProvider")
+	errs := a.Spec.ForProvider.validate(p)
+	if o, ok := old.(*Assignment); ok {
+		in, prev := a.Spec.ForProvider, o.Spec.ForProvider
+		if prev.Name != "" && in.Name != prev.Name {
+			errs = append(errs, field.Invalid(p.Child("name"), in.Name, "field is immutable once set"))
+		}
+		if prev.DeviceID != "" && in.DeviceID != prev.DeviceID {
+			errs = append(errs, field.Invalid(p.Child("deviceId"), in.DeviceID, "field is immutable once set"))
+		}
+		if prev.VirtualNetworkID != "" && in.VirtualNetworkID != prev.VirtualNetworkID {
+			errs = append(errs, field.Invalid(p.Child("virtualNetworkId"), in.VirtualNetworkID, "field is immutable once set"))
+		}
+	}
+	return a.invalid(errs)
+}
+
+// ValidateDelete allows all Assignment deletions.
+func (a *Assignment) ValidateDelete() error {
+	return nil
+}
+
+func (a *Assignment) invalid(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: AssignmentKind}, a.GetName(), errs)
+}
+
+func (p AssignmentParameters) validate(path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if p.DeviceID == "" && p.DeviceIDRef == nil && p.DeviceIDSelector == nil {
+		errs = append(errs, field.Required(path.Child("deviceId"), "one of deviceId, deviceIdRef or deviceIdSelector is required"))
+	}
+	if p.VirtualNetworkID == "" && p.VirtualNetworkIDRef == nil && p.VirtualNetworkIDSelector == nil {
+		errs = append(errs, field.Required(path.Child("virtualNetworkId"), "one of virtualNetworkId, virtualNetworkIdRef or virtualNetworkIdSelector is required"))
+	}
+	return errs
+}
@@ -84,6 +84,21 @@ func (in *AssignmentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssignmentObservation) DeepCopyInto(out *AssignmentObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssignmentObservation.
+func (in *AssignmentObservation) DeepCopy() *AssignmentObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(AssignmentObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AssignmentParameters) DeepCopyInto(out *AssignmentParameters) {
 	*out = *in
@@ -140,6 +155,8 @@ func (in *AssignmentSpec) DeepCopy() *AssignmentSpec {
 func (in *AssignmentStatus) DeepCopyInto(out *AssignmentStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
+	out.AtProvider = in.AtProvider
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssignmentStatus.
@@ -20,6 +20,8 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 )
 
 const (
@@ -41,6 +43,9 @@ const (
 	// StateDeprovisioning indicates device is in deprovisioning state
 	StateDeprovisioning = "deprovisioning"
 
+	// StateDeleted indicates device was deleted but can still be read
+	StateDeleted = "deleted"
+
 	// StateReinstalling indicates device is in reinstalling state
 	StateReinstalling = "reinstalling"
 
@@ -51,18 +56,64 @@ const (
 	StateQueued = "queued"
 )
 
+const (
+	// FeatureRequired features must be present on a Device.
+	FeatureRequired = "required"
+
+	// FeaturePreferred features are present on a Device if possible.
+	FeaturePreferred = "preferred"
+)
+
+// Actions that can be requested with the metal.equinix.com/action annotation.
+const (
+	// ActionReboot reboots a device.
+	ActionReboot = "reboot"
+
+	// ActionPowerOff powers a device off.
+	ActionPowerOff = "power-off"
+
+	// ActionPowerOn powers a device on.
+	ActionPowerOn = "power-on"
+
+	// ActionReinstall reinstalls the operating system of a device.
+	ActionReinstall = "reinstall"
+
+	// ActionRescue boots a device into the rescue operating system.
+	ActionRescue = "rescue"
+)
+
+// Phases of an action performed on a device.
+const (
+	// ActionInProgress actions were requested, but the device has not
+	// reached the state they lead to yet.
+	ActionInProgress = "InProgress"
+
+	// ActionSucceeded actions were requested, and the device reached the
+	// state they lead to.
+	ActionSucceeded = "Succeeded"
+
+	// ActionFailed actions could not be requested.
+	ActionFailed = "Failed"
+)
+
 // TODO: make optional parameters pointers and add +optional
 
 // DeviceSpec defines the desired state of Device
 type DeviceSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       DeviceParameters `json:"forProvider"`
+
+	// TTL is how long after its creation the Device is deleted, e.g. 8h.
+	// The earlier of ttl and forProvider.terminateAt applies.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
 }
 
 // DeviceStatus defines the observed state of Device
 type DeviceStatus struct {
-	xpv1.ResourceStatus `json:",inline"`
-	AtProvider          DeviceObservation `json:"atProvider,omitempty"`
+	xpv1.ResourceStatus      `json:",inline"`
+	packetv1beta1.SyncStatus `json:",inline"`
+	AtProvider               DeviceObservation `json:"atProvider,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -73,13 +124,14 @@ type DeviceStatus struct {
 // +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.state"
 // +kubebuilder:printcolumn:name="ID",type="string",JSONPath=".status.atProvider.id"
 // +kubebuilder:printcolumn:name="HOSTNAME",type="string",JSONPath=".spec.forProvider.hostname"
+// +kubebuilder:printcolumn:name="PLAN",type="string",JSONPath=".spec.forProvider.plan"
 // +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".status.atProvider.metro"
 // +kubebuilder:printcolumn:name="FACILITY",type="string",JSONPath=".status.atProvider.facility",priority=1
 // +kubebuilder:printcolumn:name="IPV4",type="string",JSONPath=".status.atProvider.ipv4"
-// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.deletionPolicy"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix,metal},shortName=dev
 type Device struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -128,7 +180,7 @@ type DataKeySelector struct {
 // LateInitialization should update the parameter after creation.
 type DeviceParameters struct {
 	// +immutable
-	// +required
+	// +optional
 	Plan string `json:"plan"`
 
 	// +immutable
@@ -138,16 +190,28 @@ type DeviceParameters struct {
 	Metro string `json:"metro,omitempty"`
 
 	// +immutable
-	// +required
+	// +optional
 	OS string `json:"operatingSystem"`
 
+	// VersionConstraint makes operatingSystem the distribution of the
+	// operating system of the device, e.g. ubuntu, rather than its slug. The
+	// latest version of the distribution that satisfies the constraint, e.g.
+	// ">=22.04" or ">=20.04, <22.04", and is provisionable on the plan is
+	// resolved from the operating system catalog when the device is created.
+	// +immutable
+	// +optional
+	OSVersionConstraint *string `json:"versionConstraint,omitempty"`
+
 	// +optional
+	// +kubebuilder:validation:MaxLength=253
 	Hostname *string `json:"hostname,omitempty"`
 
 	// +optional
+	// +kubebuilder:validation:MaxLength=1024
 	Description *string `json:"description,omitempty"`
 
 	// +optional
+	// +kubebuilder:validation:Enum=hourly;daily;monthly;yearly
 	BillingCycle *string `json:"billingCycle,omitempty"`
 
 	// +optional
@@ -174,6 +238,7 @@ type DeviceParameters struct {
 
 	// +immutable
 	// +optional
+	// +kubebuilder:validation:Pattern=`^(next-available|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`
 	HardwareReservationID *string `json:"hardwareReservationID,omitempty"`
 
 	// +optional
@@ -191,15 +256,38 @@ type DeviceParameters struct {
 	// +kubebuilder:validation:Enum="hybrid";"layer2-individual";"layer2-bonded";"layer3"
 	NetworkType *string `json:"networkType,omitempty"`
 
-	// Features can be used to require or prefer devices with optional features:
+	// Features the Device requires or prefers, such as a TPM, keyed by
+	// feature with a value of required or preferred:
 	//
 	// features:
-	// - tpm: required
-	// - tpm: preferred
+	//   tpm: required
+	//   raid: preferred
+	//
+	// Required features that the catalog of the plan lists as unsupported
+	// are rejected before the Device is created.
 	// +immutable
 	// +optional
 	Features map[string]string `json:"features,omitempty"`
 
+	// TerminateAt is when the Device is deleted. The provider deletes the
+	// Device once the time has passed, like deleting it by hand.
+	// +optional
+	TerminateAt *metav1.Time `json:"terminateAt,omitempty"`
+
+	// CloneFrom is the ID of an existing device whose plan, operating system,
+	// metro, billing cycle, user data, tags and network type seed those of
+	// this Device that are not set. They are recorded in the spec of this
+	// Device before it is created.
+	// +immutable
+	// +optional
+	CloneFrom *string `json:"cloneFrom,omitempty"`
+
+	// CloneFromRef references the Device managed resource to clone. It is
+	// mutually exclusive with cloneFrom.
+	// +immutable
+	// +optional
+	CloneFromRef *xpv1.Reference `json:"cloneFromRef,omitempty"`
+
 	// IPAddresses will be attached to the device. These addresses can be drawn
 	// from existing reservations.
 	//
@@ -209,9 +297,14 @@ type DeviceParameters struct {
 }
 
 // DeviceObservation is used to reflect in the Kubernetes API, the observed
-// state of the Device resource from the Equinix Metal API.
+// state of the Device resource from the Equinix Metal API. It holds only the
+// fields consumers of the status need, keeping Devices small in etcd.
 type DeviceObservation struct {
-	ID   string `json:"id"`
+	ID string `json:"id"`
+
+	// Href of the device in the Equinix Metal API.
+	// Deprecated: Href is derived from ID and will be removed in a future
+	// version.
 	Href string `json:"href,omitempty"`
 
 	// Facility is where the device is deployed. This field may differ from
@@ -223,12 +316,53 @@ type DeviceObservation struct {
 	IPv4                string            `json:"ipv4,omitempty"`
 	Locked              bool              `json:"locked"`
 
+	// IPv6 is the public IPv6 address of the device, if it has one.
+	// +optional
+	IPv6 string `json:"ipv6,omitempty"`
+
+	// ProjectID is the ID of the project of the device. Budgets can
+	// reference a Device to observe the spend of its project.
+	// +optional
+	ProjectID string `json:"projectID,omitempty"`
+
+	// OS is the slug of the operating system of the device. The slug an
+	// operatingSystem with a versionConstraint was resolved to is pinned
+	// here, and used if the device is created again.
+	// +optional
+	OS string `json:"operatingSystem,omitempty"`
+
 	// +optional
 	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
 
 	// +optional
 	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
 
+	// RecentEvents are the most recent events of the device, newest first.
+	// They are reported while the device is not active, to help debug
+	// provisioning failures.
+	// +optional
+	RecentEvents []DeviceEvent `json:"recentEvents,omitempty"`
+
+	// LastRequestID is the ID of the most recent Equinix Metal API request
+	// for the device that failed. Reference it in support tickets.
+	// +optional
+	LastRequestID string `json:"lastRequestID,omitempty"`
+
+	// Action is the status of the most recent action requested with the
+	// metal.equinix.com/action annotation.
+	// +optional
+	Action *DeviceActionStatus `json:"action,omitempty"`
+
+	// Hardware of the device, for network automation and inventory systems.
+	// +optional
+	Hardware *DeviceHardware `json:"hardware,omitempty"`
+
+	// PhonedHomeAt is when the device phoned home after its operating
+	// system completed its first boot. It is only observed for devices
+	// annotated with metal.equinix.com/wait-for-phone-home.
+	// +optional
+	PhonedHomeAt *metav1.Time `json:"phonedHomeAt,omitempty"`
+
 	// IQN string is omitted
 	// ImageURL *string is omitted
 	// Hostname string is omitted (represented in ForProvider)
@@ -239,7 +373,7 @@ type DeviceObservation struct {
 	// NetworkPorts []map is omitted
 	// OperatingSystem map is omitted
 	// Plan map is omitted (represented in ForProvider by Plan)
-	// Project map is omitted (represented through ProviderReference)
+	// Project map is omitted (represented by ProjectID)
 	// ShortID string is omitted
 	// SSHKeys []map is omitted
 	// Volumes []map is omitted
@@ -247,3 +381,87 @@ type DeviceObservation struct {
 	// User string is omitted (written to Credentials)
 	// RootPassword string is omitted (written to Credentials)
 }
+
+// A DeviceActionStatus is the status of an action performed on a device,
+// such as a reboot.
+type DeviceActionStatus struct {
+	// Name of the action, e.g. "reboot".
+	Name string `json:"name"`
+
+	// Phase of the action: InProgress, Succeeded or Failed.
+	Phase string `json:"phase"`
+
+	// Result describes the outcome of the action.
+	// +optional
+	Result string `json:"result,omitempty"`
+
+	// LastTransitionTime is when the action last changed phase.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// DeviceHardware describes the hardware of a device. Its network ports are
+// those of the device, the rest is described by the catalog of its plan.
+type DeviceHardware struct {
+	// Ports are the network ports of the device.
+	// +optional
+	Ports []DevicePort `json:"ports,omitempty"`
+
+	// Plan whose catalog described the CPUs, memory and drives.
+	// +optional
+	Plan string `json:"plan,omitempty"`
+
+	// CPUs of the device, e.g. 1 of type "Intel Xeon E-2278G".
+	// +optional
+	CPUs []DeviceComponent `json:"cpus,omitempty"`
+
+	// Memory of the device, e.g. "32GB".
+	// +optional
+	Memory string `json:"memory,omitempty"`
+
+	// Drives of the device, including its root disk, e.g. 2 of type "SSD"
+	// and size "480GB".
+	// +optional
+	Drives []DeviceComponent `json:"drives,omitempty"`
+}
+
+// A DevicePort is a network port of a device.
+type DevicePort struct {
+	// Name of the port, e.g. "eth0".
+	Name string `json:"name"`
+
+	// MAC address of the port.
+	// +optional
+	MAC string `json:"mac,omitempty"`
+}
+
+// A DeviceComponent is a group of identical hardware components of a device.
+type DeviceComponent struct {
+	// Count of the components.
+	Count int `json:"count"`
+
+	// Type of the components.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Size of each of the components, if they have one.
+	// +optional
+	Size string `json:"size,omitempty"`
+}
+
+// A DeviceEvent is an event of a device, such as a step of its provisioning.
+type DeviceEvent struct {
+	// Type of the event, e.g. "provisioning.104".
+	Type string `json:"type,omitempty"`
+
+	// Body describes the event.
+	Body string `json:"body,omitempty"`
+
+	// +optional
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+}
+
+// GetSyncStatus of this Device.
+func (mg *Device) GetSyncStatus() *packetv1beta1.SyncStatus {
+	return &mg.Status.SyncStatus
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// OSCustomIPXE is the operating system slug that boots a Device from an iPXE
+// script.
+const OSCustomIPXE = "custom_ipxe"
+
+var _ webhook.Validator = &Device{}
+
+// ValidateCreate enforces the cross-field rules of a new Device.
+func (d *Device) ValidateCreate() error {
+	return d.invalid(d.Spec.validate(field.NewPath("spec")))
+}
+
+// ValidateUpdate enforces the cross-field rules of an updated Device and
+// rejects changes to immutable fields.
+func (d *Device) ValidateUpdate(old runtime.Object) error {
+	errs := d.Spec.validate(field.NewPath("spec"))
+	p := field.NewPath("spec", "forProvider")
+	if o, ok := old.(*Device); ok {
+		in, prev := d.Spec.ForProvider, o.Spec.ForProvider
+		errs = append(errs, immutableString(p.Child("plan"), in.Plan, prev.Plan)...)
+		errs = append(errs, immutableString(p.Child("operatingSystem"), in.OS, prev.OS)...)
+		errs = append(errs, immutableString(p.Child("facility"), in.Facility, prev.Facility)...)
+		errs = append(errs, immutableString(p.Child("metro"), in.Metro, prev.Metro)...)
+	}
+	return d.invalid(errs)
+}
+
+// ValidateDelete allows all Device deletions.
+func (d *Device) ValidateDelete() error {
+	return nil
+}
+
+func (d *Device) invalid(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: DeviceKind}, d.GetName(), errs)
+}
+
+func (s DeviceSpec) validate(path *field.Path) field.ErrorList {
+	errs := s.ForProvider.validate(path.Child("forProvider"))
+	if s.TTL != nil && s.TTL.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("ttl"), s.TTL.Duration.String(), "ttl must be positive"))
+	}
+	return errs
+}
+
+func (p DeviceParameters) validate(path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if p.CloneFrom != nil && p.CloneFromRef != nil {
+		errs = append(errs, field.Forbidden(path.Child("cloneFromRef"), "cloneFrom and cloneFromRef are mutually exclusive"))
+	}
+	// A cloned Device takes the parameters it does not set from the device it
+	// clones.
+	if !p.Clones() {
+		if p.Plan == "" {
+			errs = append(errs, field.Required(path.Child("plan"), "plan is required unless cloneFrom or cloneFromRef is set"))
+		}
+		if p.OS == "" {
+			errs = append(errs, field.Required(path.Child("operatingSystem"), "operatingSystem is required unless cloneFrom or cloneFromRef is set"))
+		}
+		if p.Facility == "" && p.Metro == "" {
+			errs = append(errs, field.Required(path.Child("metro"), "one of metro or facility is required"))
+		}
+	}
+	if p.OSVersionConstraint != nil && p.OS == "" {
+		errs = append(errs, field.Required(path.Child("operatingSystem"), "versionConstraint requires the operatingSystem distribution it constrains"))
+	}
+	if p.UserData != nil && p.UserDataRef != nil {
+		errs = append(errs, field.Forbidden(path.Child("userdataRef"), "userdata and userdataRef are mutually exclusive"))
+	}
+	if p.IPXEScriptURL != nil && *p.IPXEScriptURL != "" && p.OS != OSCustomIPXE {
+		errs = append(errs, field.Forbidden(path.Child("ipxeScriptUrl"), "ipxeScriptUrl requires operatingSystem "+OSCustomIPXE))
+	}
+	if p.AlwaysPXE != nil && *p.AlwaysPXE && p.OS != OSCustomIPXE {
+		errs = append(errs, field.Forbidden(path.Child("alwaysPXE"), "alwaysPXE requires operatingSystem "+OSCustomIPXE))
+	}
+	features := make([]string, 0, len(p.Features))
+	for f := range p.Features {
+		features = append(features, f)
+	}
+	sort.Strings(features)
+	for _, f := range features {
+		if v := p.Features[f]; v != FeatureRequired && v != FeaturePreferred {
+			errs = append(errs, field.NotSupported(path.Child("features").Key(f), v, []string{FeatureRequired, FeaturePreferred}))
+		}
+	}
+	return errs
+}
+
+// Clones returns true if the Device is cloned from another device.
+func (p DeviceParameters) Clones() bool {
+	return p.CloneFrom != nil || p.CloneFromRef != nil
+}
+
+// immutableString rejects changing a field that was already set.
+func immutableString(path *field.Path, in, old string) field.ErrorList {
+	if old != "" && in != old {
+		return field.ErrorList{field.Invalid(path, in, "field is immutable once set")}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha2
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+func TestDeviceValidate(t *testing.T) {
+	url := "https://example.org/boot.ipxe"
+	id := "7c1e3a5b-9d2f-4b6a-8e0c-1f3a5b7d9e2c"
+	constraint := ">=20.04"
+	valid := DeviceParameters{Plan: "c3.small.x86", Metro: "sv", OS: "ubuntu_20_04"}
+
+	cases := map[string]struct {
+		in      DeviceParameters
+		ttl     *metav1.Duration
+		old     *DeviceParameters
+		wantErr bool
+	}{
+		"Valid": {
+			in: valid,
+		},
+		"MissingLocation": {
+			in:      DeviceParameters{Plan: "c3.small.x86", OS: "ubuntu_20_04"},
+			wantErr: true,
+		},
+		"MissingPlan": {
+			in:      DeviceParameters{Metro: "sv", OS: "ubuntu_20_04"},
+			wantErr: true,
+		},
+		"Cloned": {
+			in: DeviceParameters{CloneFrom: &id},
+		},
+		"CloneFromAndRef": {
+			in:      DeviceParameters{CloneFrom: &id, CloneFromRef: &xpv1.Reference{Name: "template"}},
+			wantErr: true,
+		},
+		"VersionConstraintWithoutOS": {
+			in: func() DeviceParameters {
+				p := valid
+				p.OS = ""
+				p.OSVersionConstraint = &constraint
+				return p
+			}(),
+			wantErr: true,
+		},
+		"UserDataAndRef": {
+			in: func() DeviceParameters {
+				p := valid
+				p.UserData = &url
+				p.UserDataRef = &DataKeySelector{Kind: "Secret"}
+				return p
+			}(),
+			wantErr: true,
+		},
+		"IPXEWithoutCustomOS": {
+			in: func() DeviceParameters {
+				p := valid
+				p.IPXEScriptURL = &url
+				return p
+			}(),
+			wantErr: true,
+		},
+		"IPXEWithCustomOS": {
+			in: func() DeviceParameters {
+				p := valid
+				p.OS = OSCustomIPXE
+				p.IPXEScriptURL = &url
+				return p
+			}(),
+		},
+		"Features": {
+			in: func() DeviceParameters {
+				p := valid
+				p.Features = map[string]string{"tpm": FeatureRequired, "raid": FeaturePreferred}
+				return p
+			}(),
+		},
+		"UnsupportedFeatureValue": {
+			in: func() DeviceParameters {
+				p := valid
+				p.Features = map[string]string{"tpm": "yes"}
+				return p
+			}(),
+			wantErr: true,
+		},
+		"TTL": {
+			in:  valid,
+			ttl: &metav1.Duration{Duration: 8 * time.Hour},
+		},
+		"NonPositiveTTL": {
+			in:      valid,
+			ttl:     &metav1.Duration{},
+			wantErr: true,
+		},
+		"ChangedPlan": {
+			in: func() DeviceParameters {
+				p := valid
+				p.Plan = "m3.large.x86"
+				return p
+			}(),
+			old:     &valid,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &Device{ObjectMeta: metav1.ObjectMeta{Name: "test"}, Spec: DeviceSpec{ForProvider: tc.in, TTL: tc.ttl}}
+			var err error
+			if tc.old == nil {
+				err = d.ValidateCreate()
+			} else {
+				err = d.ValidateUpdate(&Device{Spec: DeviceSpec{ForProvider: *tc.old}})
+			}
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Validate(...): want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
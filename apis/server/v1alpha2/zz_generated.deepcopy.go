@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha2
 
 import (
+	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -67,6 +69,89 @@ func (in *Device) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceActionStatus) DeepCopyInto(out *DeviceActionStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceActionStatus.
+func (in *DeviceActionStatus) DeepCopy() *DeviceActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceComponent) DeepCopyInto(out *DeviceComponent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceComponent.
+func (in *DeviceComponent) DeepCopy() *DeviceComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceEvent) DeepCopyInto(out *DeviceEvent) {
+	*out = *in
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceEvent.
+func (in *DeviceEvent) DeepCopy() *DeviceEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceHardware) DeepCopyInto(out *DeviceHardware) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]DevicePort, len(*in))
+		copy(*out, *in)
+	}
+	if in.CPUs != nil {
+		in, out := &in.CPUs, &out.CPUs
+		*out = make([]DeviceComponent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drives != nil {
+		in, out := &in.Drives, &out.Drives
+		*out = make([]DeviceComponent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceHardware.
+func (in *DeviceHardware) DeepCopy() *DeviceHardware {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceHardware)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceList) DeepCopyInto(out *DeviceList) {
 	*out = *in
@@ -111,6 +196,27 @@ func (in *DeviceObservation) DeepCopyInto(out *DeviceObservation) {
 		in, out := &in.UpdatedAt, &out.UpdatedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.RecentEvents != nil {
+		in, out := &in.RecentEvents, &out.RecentEvents
+		*out = make([]DeviceEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(DeviceActionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(DeviceHardware)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PhonedHomeAt != nil {
+		in, out := &in.PhonedHomeAt, &out.PhonedHomeAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceObservation.
@@ -126,6 +232,11 @@ func (in *DeviceObservation) DeepCopy() *DeviceObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 	*out = *in
+	if in.OSVersionConstraint != nil {
+		in, out := &in.OSVersionConstraint, &out.OSVersionConstraint
+		*out = new(string)
+		**out = **in
+	}
 	if in.Hostname != nil {
 		in, out := &in.Hostname, &out.Hostname
 		*out = new(string)
@@ -208,6 +319,20 @@ func (in *DeviceParameters) DeepCopyInto(out *DeviceParameters) {
 			(*out)[key] = val
 		}
 	}
+	if in.TerminateAt != nil {
+		in, out := &in.TerminateAt, &out.TerminateAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CloneFrom != nil {
+		in, out := &in.CloneFrom, &out.CloneFrom
+		*out = new(string)
+		**out = **in
+	}
+	if in.CloneFromRef != nil {
+		in, out := &in.CloneFromRef, &out.CloneFromRef
+		*out = new(commonv1.Reference)
+		**out = **in
+	}
 	if in.IPAddresses != nil {
 		in, out := &in.IPAddresses, &out.IPAddresses
 		*out = make([]IPAddress, len(*in))
@@ -227,11 +352,31 @@ func (in *DeviceParameters) DeepCopy() *DeviceParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePort) DeepCopyInto(out *DevicePort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePort.
+func (in *DevicePort) DeepCopy() *DevicePort {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeviceSpec) DeepCopyInto(out *DeviceSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSpec.
@@ -248,6 +393,7 @@ func (in *DeviceSpec) DeepCopy() *DeviceSpec {
 func (in *DeviceStatus) DeepCopyInto(out *DeviceStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,7 +31,55 @@ type ProviderConfigSpec struct {
 	// not specified it must be included in the Provider secret (JSON field
 	// providerID).
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})?$`
 	ProjectID string `json:"projectID"`
+
+	// Metro is the default metro of resources that specify neither a metro
+	// nor a facility. It is applied by the defaulting webhook.
+	// +optional
+	Metro string `json:"metro,omitempty"`
+
+	// OperatingSystem is the default operating system of Devices that do not
+	// specify one. It is applied by the defaulting webhook.
+	// +optional
+	OperatingSystem string `json:"operatingSystem,omitempty"`
+
+	// DefaultTags are added to the tags of every Device, e.g. to allocate
+	// costs by team. They are merged into the tags of the Device by the
+	// defaulting webhook.
+	// +optional
+	DefaultTags []string `json:"defaultTags,omitempty"`
+
+	// ReadOnly asserts that the credentials only grant read access to the
+	// Equinix Metal API. Managed resources using this ProviderConfig are only
+	// observed: their external resources are never created, updated or
+	// deleted.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// AutoImport the Devices of the project that carry a tag, by creating a
+	// Device managed resource for each of them.
+	// +optional
+	AutoImport *AutoImport `json:"autoImport,omitempty"`
+}
+
+// AutoImport configures the import of Devices created outside of the
+// provider, e.g. by other tools.
+type AutoImport struct {
+	// Tag carried by the Devices to import.
+	Tag string `json:"tag"`
+
+	// ManagementPolicies of the imported Devices, a comma separated list as
+	// accepted by the metal.equinix.com/management-policies annotation.
+	// Defaults to Observe, so that the imported Devices are only observed.
+	// +optional
+	ManagementPolicies string `json:"managementPolicies,omitempty"`
+
+	// DeletionPolicy of the imported Devices. Defaults to Orphan, so that
+	// deleting an imported Device does not delete its device.
+	// +optional
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	DeletionPolicy xpv1.DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -52,8 +101,8 @@ type ProviderConfigStatus struct {
 // A ProviderConfig configures a Template provider.
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
-// +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentialsSecretRef.name",priority=1
-// +kubebuilder:resource:scope=Cluster,categories={crossplane,equinix}
+// +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,equinix,metal},shortName=metalpc
 type ProviderConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -78,7 +127,7 @@ type ProviderConfigList struct {
 // +kubebuilder:printcolumn:name="CONFIG-NAME",type="string",JSONPath=".providerConfigRef.name"
 // +kubebuilder:printcolumn:name="RESOURCE-KIND",type="string",JSONPath=".resourceRef.kind"
 // +kubebuilder:printcolumn:name="RESOURCE-NAME",type="string",JSONPath=".resourceRef.name"
-// +kubebuilder:resource:scope=Cluster,categories={crossplane,equinix}
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,equinix,metal},shortName=metalpcu
 type ProviderConfigUsage struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -94,3 +143,61 @@ type ProviderConfigUsageList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []ProviderConfigUsage `json:"items"`
 }
+
+// A SyncStatus records which generation of the spec of a managed resource its
+// status reflects, and when its external resource was last observed.
+type SyncStatus struct {
+	// ObservedGeneration is the most recent generation of the spec that was
+	// observed to be in sync with the external resource.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSync is when the external resource was last observed.
+	// +optional
+	LastSync *metav1.Time `json:"lastSync,omitempty"`
+}
+
+// TypePlanned managed resources report the change a provider started in plan
+// mode would make to their external resource, were it not in plan mode.
+const TypePlanned xpv1.ConditionType = "Planned"
+
+// Reasons a managed resource has or has no planned change.
+const (
+	ReasonPendingCreate xpv1.ConditionReason = "PendingCreate"
+	ReasonPendingUpdate xpv1.ConditionReason = "PendingUpdate"
+	ReasonPendingDelete xpv1.ConditionReason = "PendingDelete"
+	ReasonNoChanges     xpv1.ConditionReason = "NoChanges"
+)
+
+// PendingCreate returns a condition indicating the external resource of a
+// managed resource would be created.
+func PendingCreate() xpv1.Condition {
+	return planned(corev1.ConditionTrue, ReasonPendingCreate)
+}
+
+// PendingUpdate returns a condition indicating the external resource of a
+// managed resource would be updated.
+func PendingUpdate() xpv1.Condition {
+	return planned(corev1.ConditionTrue, ReasonPendingUpdate)
+}
+
+// PendingDelete returns a condition indicating the external resource of a
+// managed resource would be deleted.
+func PendingDelete() xpv1.Condition {
+	return planned(corev1.ConditionTrue, ReasonPendingDelete)
+}
+
+// NoChanges returns a condition indicating the external resource of a
+// managed resource would not be changed.
+func NoChanges() xpv1.Condition {
+	return planned(corev1.ConditionFalse, ReasonNoChanges)
+}
+
+func planned(s corev1.ConditionStatus, r xpv1.ConditionReason) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePlanned,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+	}
+}
@@ -19,6 +19,8 @@ package v1alpha1
 import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 )
 
 // VirtualNetworkSpec defines the desired state of VirtualNetwork
@@ -29,8 +31,9 @@ type VirtualNetworkSpec struct {
 
 // VirtualNetworkStatus defines the observed state of VirtualNetwork
 type VirtualNetworkStatus struct {
-	xpv1.ResourceStatus `json:",inline"`
-	AtProvider          VirtualNetworkObservation `json:"atProvider,omitempty"`
+	xpv1.ResourceStatus      `json:",inline"`
+	packetv1beta1.SyncStatus `json:",inline"`
+	AtProvider               VirtualNetworkObservation `json:"atProvider,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -42,10 +45,10 @@ type VirtualNetworkStatus struct {
 // +kubebuilder:printcolumn:name="VXLAN",type="string",JSONPath=".status.atProvider.vxlan"
 // +kubebuilder:printcolumn:name="METRO",type="string",JSONPath=".status.atProvider.metro"
 // +kubebuilder:printcolumn:name="FACILITY",type="string",JSONPath=".status.atProvider.facilityCode",priority=1
-// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.reclaimPolicy"
+// +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".spec.deletionPolicy"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix}
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,equinix,metal},shortName=vnet
 type VirtualNetwork struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -79,9 +82,20 @@ type VirtualNetworkParameters struct {
 
 	// +immutable
 	// +optional
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=3999
 	VXLAN int `json:"vxlan,omitempty"`
 
+	// VXLANPoolRef references the VLANPool of the
+	// vlan.equinixmetal.crossplane.io API group that allocates the VXLAN ID
+	// of a VirtualNetwork that specifies none. The allocated ID is written to
+	// vxlan before the VirtualNetwork is created.
+	// +immutable
+	// +optional
+	VXLANPoolRef *xpv1.Reference `json:"vxlanPoolRef,omitempty"`
+
 	// +optional
+	// +kubebuilder:validation:MaxLength=1024
 	Description *string `json:"description,omitempty"`
 }
 
@@ -93,4 +107,14 @@ type VirtualNetworkObservation struct {
 	VXLAN        int          `json:"vxlan,omitempty"`
 	FacilityCode string       `json:"facilityCode,omitempty"`
 	CreatedAt    *metav1.Time `json:"createdAt,omitempty"`
+
+	// LastRequestID is the ID of the most recent Equinix Metal API request
+	// for the virtual network that failed. Reference it in support tickets.
+	// +optional
+	LastRequestID string `json:"lastRequestID,omitempty"`
+}
+
+// GetSyncStatus of this VirtualNetwork.
+func (mg *VirtualNetwork) GetSyncStatus() *packetv1beta1.SyncStatus {
+	return &mg.Status.SyncStatus
 }
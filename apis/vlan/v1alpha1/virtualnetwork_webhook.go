@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var _ webhook.Validator = &VirtualNetwork{}
+
+// ValidateCreate enforces the cross-field rules of a new VirtualNetwork.
+func (v *VirtualNetwork) ValidateCreate() error {
+	return v.invalid(v.Spec.ForProvider.validate(field.NewPath("spec", "forProvider")))
+}
+
+// ValidateUpdate enforces the cross-field rules of an updated VirtualNetwork
+// and rejects changes to immutable fields.
+func (v *VirtualNetwork) ValidateUpdate(old runtime.Object) error {
+	p := field.NewPath("spec", "forProvider")
+	errs := v.Spec.ForProvider.validate(p)
+	if o, ok := old.(*VirtualNetwork); ok {
+		in, prev := v.Spec.ForProvider, o.Spec.ForProvider
+		if prev.Facility != "" && in.Facility != prev.Facility {
+			errs = append(errs, field.Invalid(p.Child("facility"), in.Facility, "field is immutable once set"))
+		}
+		if prev.Metro != "" && in.Metro != prev.Metro {
+			errs = append(errs, field.Invalid(p.Child("metro"), in.Metro, "field is immutable once set"))
+		}
+		if prev.VXLAN != 0 && in.VXLAN != prev.VXLAN {
+			errs = append(errs, field.Invalid(p.Child("vxlan"), in.VXLAN, "field is immutable once set"))
+		}
+	}
+	return v.invalid(errs)
+}
+
+// ValidateDelete allows all VirtualNetwork deletions.
+func (v *VirtualNetwork) ValidateDelete() error {
+	return nil
+}
+
+func (v *VirtualNetwork) invalid(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: Group, Kind: VirtualNetworkKind}, v.GetName(), errs)
+}
+
+func (p VirtualNetworkParameters) validate(path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if p.Facility == "" && p.Metro == "" {
+		errs = append(errs, field.Required(path.Child("metro"), "one of metro or facility is required"))
+	}
+	if p.Facility != "" && p.Metro != "" {
+		errs = append(errs, field.Forbidden(path.Child("facility"), "metro and facility are mutually exclusive"))
+	}
+	if p.VXLAN != 0 && p.Metro == "" {
+		errs = append(errs, field.Forbidden(path.Child("vxlan"), "vxlan can only be chosen for metro virtual networks"))
+	}
+	return errs
+}
@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -105,6 +106,11 @@ func (in *VirtualNetworkObservation) DeepCopy() *VirtualNetworkObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualNetworkParameters) DeepCopyInto(out *VirtualNetworkParameters) {
 	*out = *in
+	if in.VXLANPoolRef != nil {
+		in, out := &in.VXLANPoolRef, &out.VXLANPoolRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
 	if in.Description != nil {
 		in, out := &in.Description, &out.Description
 		*out = new(string)
@@ -143,6 +149,7 @@ func (in *VirtualNetworkSpec) DeepCopy() *VirtualNetworkSpec {
 func (in *VirtualNetworkStatus) DeepCopyInto(out *VirtualNetworkStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.SyncStatus.DeepCopyInto(&out.SyncStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
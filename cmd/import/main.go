@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command import writes managed resources for existing Equinix Metal
+// resources, with their external names set so that applying them brings the
+// resources under management. Resources are read from an Equinix Metal project
+// or from a Terraform state.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/importer"
+)
+
+func main() {
+	var (
+		app            = kingpin.New(filepath.Base(os.Args[0]), "Generate Equinix Metal managed resources for existing resources.").DefaultEnvars()
+		providerConfig = app.Flag("provider-config", "Name of the ProviderConfig the managed resources reference.").Default("default").String()
+		deletionPolicy = app.Flag("deletion-policy", "Deletion policy of the managed resources, one of Orphan or Delete. Orphan leaves the resources in place when the managed resources are deleted.").Default(string(xpv1.DeletionOrphan)).Enum(string(xpv1.DeletionOrphan), string(xpv1.DeletionDelete))
+
+		project   = app.Command("project", "Import the Devices and VirtualNetworks of a project.").Default()
+		apiKey    = project.Flag("auth-token", "Equinix Metal API key.").Envar("METAL_AUTH_TOKEN").Required().String()
+		projectID = project.Flag("project-id", "Equinix Metal project to import.").Envar("METAL_PROJECT_ID").Required().String()
+
+		terraform = app.Command("terraform", "Import the Devices and VLANs managed by a Terraform state.")
+		stateFile = terraform.Arg("state", "Path of the Terraform state, as written by terraform state pull.").Required().ExistingFile()
+	)
+	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	i := importer.New(importer.Options{ProviderConfig: *providerConfig, DeletionPolicy: xpv1.DeletionPolicy(*deletionPolicy)})
+	var mgs []resource.Managed
+	switch cmd {
+	case project.FullCommand():
+		c, err := clients.NewClient(context.Background(), &clients.Credentials{APIKey: *apiKey, ProjectID: *projectID})
+		kingpin.FatalIfError(err, "Cannot create Equinix Metal client")
+		mgs, err = i.Project(c, *projectID)
+		kingpin.FatalIfError(err, "Cannot import project %s", *projectID)
+	case terraform.FullCommand():
+		f, err := os.Open(*stateFile)
+		kingpin.FatalIfError(err, "Cannot open Terraform state")
+		var skipped []string
+		mgs, skipped, err = i.TerraformState(f)
+		_ = f.Close()
+		kingpin.FatalIfError(err, "Cannot import Terraform state %s", *stateFile)
+		for _, a := range skipped {
+			fmt.Fprintf(os.Stderr, "Skipped %s: no managed resource kind\n", a)
+		}
+	}
+	kingpin.FatalIfError(importer.WriteYAML(os.Stdout, mgs), "Cannot write managed resources")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command migrate converts the resources of the legacy packet.crossplane.io API
+// groups, served by provider-packet, to their metal.equinix.com equivalents in
+// place, so that existing installations can upgrade without importing their
+// devices again. It can also migrate managed resources to the
+// equinixmetal.crossplane.io API groups.
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/migration"
+)
+
+func main() {
+	var (
+		app          = kingpin.New(filepath.Base(os.Args[0]), "Migrate provider-packet resources to the Equinix Metal provider.").DefaultEnvars()
+		debug        = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		dryRun       = app.Flag("dry-run", "Log the resources that would be migrated without migrating them.").Bool()
+		deleteLegacy = app.Flag("delete-legacy", "Delete the legacy resources once migrated. They are orphaned first, so that the Equinix Metal resources they manage are kept.").Bool()
+		alias        = app.Flag("alias-groups", "Migrate managed resources of the metal.equinix.com API groups to the equinixmetal.crossplane.io API groups, instead of migrating legacy resources. The metal.equinix.com resources are always orphaned and deleted once migrated.").Bool()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	log := logging.NewLogrLogger(ctrlzap.New(ctrlzap.UseDevMode(*debug)).WithName("migrate"))
+
+	cfg, err := ctrl.GetConfig()
+	kingpin.FatalIfError(err, "Cannot get API server rest config")
+
+	s := runtime.NewScheme()
+	kingpin.FatalIfError(apis.AddToScheme(s), "Cannot add Equinix Metal APIs to scheme")
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	kingpin.FatalIfError(err, "Cannot create Kubernetes client")
+
+	m := migration.NewMigrator(kube, log)
+	m.DryRun = *dryRun
+	m.DeleteLegacy = *deleteLegacy
+	kinds := migration.Kinds
+	if *alias {
+		// Two managed resources with the Delete policy must not manage the
+		// same Equinix Metal resource, lest deleting either destroys it.
+		m.DeleteLegacy = true
+		kinds = migration.AliasKinds
+	}
+	kingpin.FatalIfError(m.Migrate(context.Background(), kinds), "Cannot migrate legacy resources")
+}
@@ -17,28 +17,96 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"net/http"
+	"net/http/pprof"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
 	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/packethost/crossplane-provider-equinix-metal/apis"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/backoff"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/connection"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/graceful"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/features"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/version"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/webhook"
 )
 
 func main() {
 	var (
-		app        = kingpin.New(filepath.Base(os.Args[0]), "Equinix Metal support for Crossplane.").DefaultEnvars()
-		debug      = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
-		syncPeriod = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
+		app          = kingpin.New(filepath.Base(os.Args[0]), "Equinix Metal support for Crossplane.").DefaultEnvars()
+		debug        = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		logLevel     = app.Flag("log-level", "Log level, one of trace, debug, info or error. Defaults to debug with --debug and info otherwise.").Enum("trace", "debug", "info", "error")
+		logEncoder   = app.Flag("log-encoder", "Log encoding, one of json or console. Defaults to console with --debug and json otherwise.").Enum("json", "console")
+		logAPI       = app.Flag("log-api-requests", "Log Equinix Metal API requests at debug level, and their redacted bodies at trace level.").Bool()
+		logSampling  = app.Flag("log-sampling", "Sample repeated log entries to bound log volume.").Default("true").Bool()
+		syncPeriod   = app.Flag("sync-period", "Controller manager sync period (full resync interval) such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
+		syncLegacy   = app.Flag("sync", "Deprecated: use --sync-period.").Hidden().Duration()
+		controllers  = app.Flag("enable-controllers", "Comma separated controllers to run (default all). One or more of: "+strings.Join(controller.ControllerNames(), ", ")+". CRDs of disabled controllers are still installed by the package.").Strings()
+		pollInterval = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("1m").Duration()
+		readOnly     = app.Flag("read-only", "Only observe external resources, as if every ProviderConfig were read-only. For audit and reporting installs.").Bool()
+		planOnly     = app.Flag("plan", "Only observe external resources, and report the changes that would be made to them with events, log entries and the Planned condition of their managed resources. For evaluating what adopting the provider would change.").Bool()
+		secretPolicy = app.Flag("connection-secret-policy", "Whether connection secrets are deleted or retained when their managed resource is deleted. One of "+strings.Join(connection.Policies(), ", ")+". RetainOrphaned retains them only when the deletion policy is Orphan.").Default(string(connection.PolicyDelete)).Enum(connection.Policies()...)
+		deviceCache  = app.Flag("device-cache-ttl", "How long Devices are observed from a list of all the Devices of their project before it is listed again. Reduces API requests when managing many Devices. Zero disables the cache.").Duration()
+		observeCache = app.Flag("observe-cache-ttl", "How long the observation of a managed resource that is available and up to date is reused instead of observing it again, unless the managed resource changes. Overridden by the metal.equinix.com/observe-cache-ttl annotation of a managed resource. Zero disables the cache.").Duration()
+		keyscan      = app.Flag("device-ssh-keyscan", "Scan the SSH host keys of active Devices from the provider, like ssh-keyscan, and add them to their connection secrets as known_hosts entries.").Bool()
+		batchObserve = app.Flag("device-batch-observe", "Observe Devices from one list of all the Devices of each project per poll interval, instead of reading each Device. For large fleets.").Bool()
+		inventory    = app.Flag("device-inventory", "namespace/name of a ConfigMap maintained as an inventory of all managed Devices, for systems without Equinix Metal API access. Disabled when empty.").String()
+		backoffBase  = app.Flag("backoff-base-delay", "How long a resource whose reconcile failed is retried after. Doubles with each consecutive failure.").Default(backoff.DefaultBaseDelay.String()).Duration()
+		backoffMax   = app.Flag("backoff-max-delay", "Longest a resource whose reconcile failed is retried after, before jitter. Raise to tolerate long Equinix Metal API outages.").Default(backoff.DefaultMaxDelay.String()).Duration()
+		backoffJit   = app.Flag("backoff-jitter", "Each retry delay is lengthened by a random fraction of up to this much of it, so that resources that failed together are not retried at once.").Default("0.1").Float64()
+		shutdownWait = app.Flag("shutdown-grace-period", "How long reconciles in flight when the provider is stopped may take to finish, so that resources being created are not abandoned. Must be shorter than the termination grace period of the provider's pod. Zero abandons them at once.").Default("20s").Duration()
+		maxReconcile = app.Flag("max-reconciles", "Resources each controller may reconcile at once.").Default("1").Int()
+		maxPerCtrl   = app.Flag("controller-max-reconciles", "Comma separated controller=n overriding --max-reconciles for the named controllers, e.g. device=2,virtualnetwork=10.").Strings()
+		alpha        = app.Flag("enable-alpha-features", "Comma separated alpha features to enable. One or more of: "+strings.Join(alphaFeatures(), ", ")+".").Strings()
+		apiRate      = app.Flag("api-rate", "Equinix Metal API requests per second shared by all controllers. Zero disables client side rate limiting.").Default(strconv.Itoa(clients.DefaultAPIRate)).Float64()
+		apiBurst     = app.Flag("api-burst", "Equinix Metal API requests that may be sent at once.").Default(strconv.Itoa(clients.DefaultAPIBurst)).Int()
+		apiShare     = app.Flag("api-controller-share", "Fraction of --api-rate any single controller may use.").Default(strconv.FormatFloat(clients.DefaultControllerShare, 'f', -1, 64)).Float64()
+		apiProxy     = app.Flag("api-proxy", "URL of the proxy Equinix Metal API requests are sent through. Defaults to the HTTPS_PROXY environment variable.").String()
+		apiCABundle  = app.Flag("api-ca-bundle", "Path of a PEM bundle of CA certificates to trust for the Equinix Metal API, in addition to the system's.").String()
+		apiTimeout   = app.Flag("api-timeout", "Time limit of an Equinix Metal API call, including retries. Zero means no limit.").Default(clients.DefaultTimeout.String()).Duration()
+		webhookDir   = app.Flag("webhook-tls-cert-dir", "Directory holding the tls.crt and tls.key of the webhook server. Webhooks are served only when set.").String()
+		webhookCert  = app.Flag("webhook-tls-cert-name", "Name of the certificate file in --webhook-tls-cert-dir.").Default("tls.crt").String()
+		webhookKey   = app.Flag("webhook-tls-key-name", "Name of the key file in --webhook-tls-cert-dir.").Default("tls.key").String()
+		webhookPoll  = app.Flag("webhook-tls-poll-interval", "How often the webhook certificate is checked for rotation. A rotated certificate is served without restarting. Zero serves the certificate read at startup until exit.").Default("10s").Duration()
+		webhookCap   = app.Flag("webhook-device-capacity", "How new Devices whose plan has no capacity in their metro or facility are admitted, one of "+strings.Join(webhook.CapacityPolicies(), ", ")+". Warn and Deny check the capacity API at admission.").Default(string(webhook.CapacityPolicyOff)).Enum(webhook.CapacityPolicies()...)
+		webhookPort  = app.Flag("webhook-port", "Port the webhook server listens on.").Default("9443").Int()
+		enablePprof  = app.Flag("enable-pprof", "Serve pprof profiles under /debug/pprof/ on the metrics endpoint.").Bool()
+		otlpEndpoint = app.Flag("otlp-endpoint", "host:port of the OTLP gRPC collector traces of reconciles and Equinix Metal API requests are exported to. Tracing is disabled when empty.").Envar("OTEL_EXPORTER_OTLP_ENDPOINT").String()
+		otlpInsecure = app.Flag("otlp-insecure", "Export traces to the OTLP collector without TLS.").Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
-	zl := zap.New(zap.UseDevMode(*debug))
+	if *syncLegacy != 0 {
+		syncPeriod = syncLegacy
+	}
+
+	zl := newLogger(*debug, *logLevel, *logEncoder, *logSampling)
 	log := logging.NewLogrLogger(zl.WithName("provider-equinix-metal"))
 	if *debug {
 		// The controller-runtime runs with a no-op logger by default. It is
@@ -47,15 +115,215 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
+	if *logAPI {
+		api := zl.WithName("provider-equinix-metal").WithName("api")
+		var trace logging.Logger
+		if api.V(2).Enabled() {
+			// Debug messages of this logger are logged at V(2).
+			trace = logging.NewLogrLogger(api.V(1))
+		}
+		clients.SetAPILogger(logging.NewLogrLogger(api), trace)
+	}
+
+	enabled := splitList(*controllers)
+
+	maxReconciles, err := controller.ParseMaxReconciles(splitList(*maxPerCtrl)...)
+	kingpin.FatalIfError(err, "Cannot parse controller max reconciles")
+
+	feats, err := features.Parse(splitList(*alpha)...)
+	kingpin.FatalIfError(err, "Cannot parse alpha features")
+
+	if *backoffJit < 0 {
+		kingpin.Fatalf("--backoff-jitter must not be negative")
+	}
+
+	deviceInventory, err := parseNamespacedName(*inventory)
+	kingpin.FatalIfError(err, "Cannot parse device inventory")
+
+	log.Debug("Starting", "sync-period", syncPeriod.String(), "poll-interval", pollInterval.String(), "controllers", enabled, "alpha-features", splitList(*alpha))
+
+	transport, err := clients.NewBaseTransport(*apiProxy, *apiCABundle)
+	kingpin.FatalIfError(err, "Cannot configure Equinix Metal API transport")
+	clients.SetBaseTransport(transport)
+	clients.SetTimeout(*apiTimeout)
+	clients.SetRateLimiter(clients.NewRateLimiter(rate.Limit(*apiRate), *apiBurst, *apiShare))
+
+	if *otlpEndpoint != "" {
+		shutdown, err := setupTracing(context.Background(), *otlpEndpoint, *otlpInsecure)
+		kingpin.FatalIfError(err, "Cannot setup tracing")
+		defer shutdown(context.Background()) //nolint:errcheck // Nothing can be done about unexported spans when exiting.
+	}
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
-	mgr, err := ctrl.NewManager(cfg, ctrl.Options{SyncPeriod: syncPeriod})
+	// The manager waits a little longer than the reconciles in flight for
+	// its other runnables to stop.
+	shutdownTimeout := *shutdownWait + 5*time.Second
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		SyncPeriod:              syncPeriod,
+		GracefulShutdownTimeout: &shutdownTimeout,
+	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
+	var drainer *graceful.Drainer
+	if *shutdownWait > 0 {
+		drainer = graceful.NewDrainer(*shutdownWait, log)
+		kingpin.FatalIfError(mgr.Add(drainer), "Cannot add reconcile drainer")
+	}
+
+	if *enablePprof {
+		kingpin.FatalIfError(addPprofHandlers(mgr), "Cannot add pprof handlers")
+	}
+
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add GCP APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log), "Cannot setup GCP controllers")
+	kingpin.FatalIfError(controller.Setup(mgr, options.Options{
+		Logger:                            log,
+		PollInterval:                      *pollInterval,
+		Controllers:                       enabled,
+		Features:                          feats,
+		ReadOnly:                          *readOnly,
+		Plan:                              *planOnly,
+		ConnectionSecretPolicy:            connection.Policy(*secretPolicy),
+		DeviceCacheTTL:                    *deviceCache,
+		ObserveCacheTTL:                   *observeCache,
+		DeviceKeyscan:                     *keyscan,
+		BatchObserve:                      *batchObserve,
+		DeviceInventory:                   deviceInventory,
+		Drainer:                           drainer,
+		MaxConcurrentReconciles:           *maxReconcile,
+		BackoffBaseDelay:                  *backoffBase,
+		BackoffMaxDelay:                   *backoffMax,
+		BackoffJitter:                     *backoffJit,
+		ControllerMaxConcurrentReconciles: maxReconciles,
+	}), "Cannot setup GCP controllers")
+	if *webhookDir != "" {
+		certs, err := webhook.NewCertWatcher(*webhookDir, *webhookCert, *webhookKey, *webhookPoll, log.WithValues("component", "webhook"))
+		kingpin.FatalIfError(err, "Cannot read webhook certificate")
+		kingpin.FatalIfError(webhook.Setup(mgr, webhook.Options{
+			DeviceCapacity: webhook.CapacityPolicy(*webhookCap),
+			Port:           *webhookPort,
+			Certs:          certs,
+		}), "Cannot setup webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
+
+// addPprofHandlers serves the net/http/pprof handlers on the metrics endpoint
+// of the supplied manager.
+func addPprofHandlers(mgr ctrl.Manager) error {
+	for path, h := range map[string]http.HandlerFunc{
+		"/debug/pprof/":        pprof.Index,
+		"/debug/pprof/cmdline": pprof.Cmdline,
+		"/debug/pprof/profile": pprof.Profile,
+		"/debug/pprof/symbol":  pprof.Symbol,
+		"/debug/pprof/trace":   pprof.Trace,
+	} {
+		if err := mgr.AddMetricsExtraHandler(path, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupTracing sets the global TracerProvider to one exporting spans in
+// batches to the OTLP gRPC collector at the supplied endpoint. It returns a
+// function that flushes the remaining spans and stops the exporter.
+func setupTracing(ctx context.Context, endpoint string, insecure bool) (func(context.Context) error, error) {
+	opts := []otlpgrpc.Option{otlpgrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlpgrpc.WithInsecure())
+	}
+	exp, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(opts...))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String("crossplane-provider-equinix-metal"),
+			semconv.ServiceVersionKey.String(version.Version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// newLogger returns a zap backed logger. Debug mode defaults to console
+// encoding at debug level, otherwise JSON encoding at info level is used.
+// traceLevel is the zap level of logr V(2), one below debug.
+const traceLevel = zapcore.DebugLevel - 1
+
+func newLogger(debug bool, level, encoding string, sampling bool) logr.Logger {
+	lvl := zapcore.InfoLevel
+	cfg := zap.NewProductionEncoderConfig()
+	stacktrace := zapcore.ErrorLevel
+	opts := []zap.Option{}
+	if debug {
+		lvl = zapcore.DebugLevel
+		cfg = zap.NewDevelopmentEncoderConfig()
+		stacktrace = zapcore.WarnLevel
+		opts = append(opts, zap.Development())
+	}
+	switch level {
+	case "":
+	case "trace":
+		lvl = traceLevel
+	default:
+		// The level was validated by kingpin.
+		_ = lvl.UnmarshalText([]byte(level))
+	}
+
+	if encoding == "" {
+		encoding = "json"
+		if debug {
+			encoding = "console"
+		}
+	}
+	enc := zapcore.NewJSONEncoder(cfg)
+	if encoding == "console" {
+		enc = zapcore.NewConsoleEncoder(cfg)
+	}
+
+	sink := zapcore.AddSync(os.Stderr)
+	core := zapcore.NewCore(&ctrlzap.KubeAwareEncoder{Encoder: enc, Verbose: debug}, sink, lvl)
+	if sampling {
+		core = zapcore.NewSampler(core, time.Second, 100, 100)
+	}
+	opts = append(opts, zap.AddStacktrace(stacktrace), zap.AddCaller(), zap.AddCallerSkip(1), zap.ErrorOutput(sink))
+	return zapr.NewLogger(zap.New(core, opts...))
+}
+
+// splitList flattens repeated and comma separated flag values.
+func splitList(values []string) []string {
+	list := []string{}
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
+
+// parseNamespacedName parses a namespace/name reference. An empty reference
+// parses to an empty name.
+func parseNamespacedName(s string) (types.NamespacedName, error) {
+	if s == "" {
+		return types.NamespacedName{}, nil
+	}
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, errors.Errorf("%q is not of the form namespace/name", s)
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+func alphaFeatures() []string {
+	names := []string{}
+	for _, f := range features.Alpha() {
+		names = append(names, string(f))
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// clientCacheTTL is how long an unused cached API client is kept, bounding
+// the clients retained for rotated credentials.
+const clientCacheTTL = time.Hour
+
+type cachedClient struct {
+	client *http.Client
+	used   time.Time
+}
+
+// A clientCache shares the HTTP clients of Equinix Metal API clients, and thus
+// their connection pools, throttling and rate limiting, between the
+// connections made with the same credentials. Clients are keyed by a hash of
+// those credentials and the connecting controller. The credentials are those
+// of a ProviderConfig and its secret, so a ProviderConfig whose secret is
+// rotated gets a new client.
+type clientCache struct {
+	mu      sync.Mutex
+	clients map[string]*cachedClient
+	now     func() time.Time
+}
+
+var apiClients = &clientCache{clients: map[string]*cachedClient{}, now: time.Now}
+
+// Get returns the client cached under the supplied key, calling newFn to
+// create one if none is cached.
+func (c *clientCache) Get(key string, newFn func() *http.Client) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, cc := range c.clients {
+		if now.Sub(cc.used) > clientCacheTTL {
+			delete(c.clients, k)
+		}
+	}
+	cc, ok := c.clients[key]
+	if !ok {
+		cc = &cachedClient{client: newFn()}
+		c.clients[key] = cc
+	}
+	cc.used = now
+	return cc.client
+}
+
+// Reset drops all cached clients.
+func (c *clientCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clients = map[string]*cachedClient{}
+}
+
+// CredentialsKey returns a key identifying the supplied credentials, derived
+// from but not revealing them.
+func CredentialsKey(c *Credentials) string {
+	sum := sha256.Sum256([]byte(c.APIKey + "\x00" + c.ProjectID + "\x00" + c.FacilityID))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// contextTransport binds requests to a context.
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+// NewContextTransport returns an http.RoundTripper that sends every request
+// with the supplied context, so that in flight requests are aborted when it
+// is cancelled. packngo does not accept a context, so the context of each
+// reconcile is bound to the client connected for it.
+func NewContextTransport(ctx context.Context, next http.RoundTripper) http.RoundTripper {
+	return &contextTransport{ctx: ctx, next: next}
+}
+
+// RoundTrip sends the supplied request with a context that is done when
+// either the request's own context, which carries the deadline of the
+// http.Client's Timeout, or the bound context is done.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		select {
+		case <-t.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	// The response body is read after RoundTrip returns, so the context is
+	// only released once it is closed.
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody cancels a context when the body it wraps is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
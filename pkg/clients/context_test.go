@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContextTransportKeepsClientTimeout(t *testing.T) {
+	// Every request is rejected as throttled, so the retry transport waits
+	// out a backoff of at least 2s, far longer than the client's Timeout.
+	next := roundTripFn(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+	rt := NewRetryTransport(next, 1).(*retryTransport)
+	rt.baseDelay = 4 * time.Second
+
+	c := &http.Client{Transport: NewContextTransport(context.Background(), rt), Timeout: 200 * time.Millisecond}
+	start := time.Now()
+	resp, err := c.Get("https://api.equinix.com/metal/v1/devices")
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Get(...): want error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get(...): want the client Timeout to interrupt the backoff, waited %s", elapsed)
+	}
+}
+
+func TestContextTransportCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	next := roundTripFn(func(req *http.Request) (*http.Response, error) {
+		cancel()
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	c := &http.Client{Transport: NewContextTransport(ctx, next)}
+	resp, err := c.Get("https://api.equinix.com/metal/v1/devices")
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("Get(...): want error, got nil")
+	}
+}
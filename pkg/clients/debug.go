@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// HeaderAuthToken is the request header carrying the Equinix Metal API key.
+const HeaderAuthToken = "X-Auth-Token"
+
+const redacted = "REDACTED"
+
+// redactedFields are the JSON fields of request and response bodies whose
+// values are never logged.
+var redactedFields = map[string]bool{
+	"userdata":      true,
+	"customdata":    true,
+	"root_password": true,
+	"token":         true,
+}
+
+var (
+	apiLoggerMu sync.RWMutex
+	apiLogger   logging.Logger
+	apiTracer   logging.Logger
+)
+
+// SetAPILogger logs the requests of all API clients created afterwards to the
+// supplied logger, and their redacted bodies to the supplied trace logger if
+// it is not nil. Requests are not logged by default.
+func SetAPILogger(log, trace logging.Logger) {
+	apiLoggerMu.Lock()
+	defer apiLoggerMu.Unlock()
+	apiLogger, apiTracer = log, trace
+}
+
+func getAPILogger() (logging.Logger, logging.Logger) {
+	apiLoggerMu.RLock()
+	defer apiLoggerMu.RUnlock()
+	return apiLogger, apiTracer
+}
+
+// loggingTransport logs Equinix Metal API requests and responses.
+type loggingTransport struct {
+	next  http.RoundTripper
+	log   logging.Logger
+	trace logging.Logger
+}
+
+// NewLoggingTransport returns an http.RoundTripper that logs the metadata of
+// every request and response at debug level, and their bodies to the supplied
+// trace logger if it is not nil. The API key and sensitive body fields such
+// as userdata are redacted.
+func NewLoggingTransport(next http.RoundTripper, log, trace logging.Logger) http.RoundTripper {
+	return &loggingTransport{next: next, log: log, trace: trace}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The API may echo the sensitive values of a request, such as invalid
+	// userdata, in the error it responds with.
+	var values []string
+	if t.trace != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := ioutil.ReadAll(body)
+			values = sensitiveValues(b)
+			t.trace.Debug("Equinix Metal API request body", "method", req.Method, "url", req.URL.String(), "body", redactBody(b))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	log := t.log.WithValues(
+		"method", req.Method,
+		"url", req.URL.String(),
+		"auth", redactHeader(req.Header.Get(HeaderAuthToken)),
+		"duration", time.Since(start).String(),
+	)
+	if err != nil {
+		log.Debug("Equinix Metal API request failed", "error", err)
+		return resp, err
+	}
+	log.Debug("Equinix Metal API request",
+		"status", resp.StatusCode,
+		"request-id", resp.Header.Get(HeaderRequestID),
+		"rate-remaining", resp.Header.Get(HeaderRateRemaining),
+	)
+
+	if t.trace != nil && resp.Body != nil {
+		b, rerr := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		if rerr == nil {
+			t.trace.Debug("Equinix Metal API response body", "method", req.Method, "url", req.URL.String(), "body", Redact(redactBody(b), values...))
+		}
+	}
+	return resp, nil
+}
+
+func redactHeader(v string) string {
+	if v == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactBody returns the supplied JSON body with the values of sensitive
+// fields replaced. Bodies that are not JSON are not logged.
+func redactBody(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return redacted
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return redacted
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range t {
+			if redactedFields[k] {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactValue(fv)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redactValue(t[i])
+		}
+	}
+	return v
+}
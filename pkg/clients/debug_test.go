@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRedactBody(t *testing.T) {
+	cases := map[string]struct {
+		body string
+		want string
+	}{
+		"Empty": {
+			body: "",
+			want: "",
+		},
+		"NotJSON": {
+			body: "#cloud-config",
+			want: redacted,
+		},
+		"Nested": {
+			body: `{"hostname":"a","userdata":"#!/bin/sh","devices":[{"root_password":"hunter2"}]}`,
+			want: `{"devices":[{"root_password":"REDACTED"}],"hostname":"a","userdata":"REDACTED"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, redactBody([]byte(tc.body))); diff != "" {
+				t.Errorf("redactBody(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"path"
+
+	"github.com/packethost/packngo"
+)
+
+// actions performs device actions with a packngo Client. The Device service
+// of packngo offers only some of the actions of the API, e.g. no reinstall.
+type actions struct {
+	client *packngo.Client
+}
+
+func (a actions) PerformAction(deviceID, actionType string) (*packngo.Response, error) {
+	return a.client.DoRequest("POST", path.Join("/devices", deviceID, "actions"), &packngo.DeviceActionRequest{Type: actionType}, nil)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// A Cache serves Devices from lists of all the Devices of a project, so that
+// observing many Devices takes one API request per project rather than one per
+// Device. The Devices of a project are listed again once their list is older
+// than the TTL of the Cache.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu    sync.Mutex
+	lists map[string]*deviceList
+}
+
+// A deviceList holds the Devices listed with one set of credentials. Its
+// mutex is held while listing, so that concurrent observations of the same
+// project wait for one list instead of each making their own.
+type deviceList struct {
+	mu      sync.Mutex
+	listed  time.Time
+	devices map[string]packngo.Device
+}
+
+// NewCache returns a Cache listing the Devices of a project at most once per
+// supplied TTL.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, now: time.Now, lists: map[string]*deviceList{}}
+}
+
+// ListOptions returns the options of the List calls made to fill a Cache.
+func ListOptions() *packngo.ListOptions {
+	o := ObserveOptions()
+	o.PerPage = clients.DefaultPerPage
+	return o
+}
+
+// ImportListOptions returns the options of the List calls made to import
+// Devices. Unlike ListOptions they keep the plan, which the imported Devices
+// specify.
+func ImportListOptions() *packngo.ListOptions {
+	return &packngo.ListOptions{Excludes: []string{"project", "ssh_keys", "volumes"}, PerPage: clients.DefaultPerPage}
+}
+
+// Get returns the Device with the supplied ID from the most recent list of the
+// Devices of the supplied project made with the credentials identified by the
+// supplied key, listing them with the supplied client if that list is older
+// than the TTL. It returns false if the Device is not in the list, for example
+// because it was created after the list was made.
+func (c *Cache) Get(cl Client, key, projectID, id string) (*packngo.Device, bool, error) {
+	l := c.list(key)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.devices == nil || c.now().Sub(l.listed) > c.ttl {
+		if _, err := c.fill(l, cl, projectID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	d, ok := l.devices[id]
+	if !ok {
+		return nil, false, nil
+	}
+	return &d, true, nil
+}
+
+// Refresh lists the Devices of the supplied project with the supplied client
+// into the list of the credentials identified by the supplied key, regardless
+// of its age, and returns them.
+func (c *Cache) Refresh(cl Client, key, projectID string) ([]packngo.Device, error) {
+	l := c.list(key)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return c.fill(l, cl, projectID)
+}
+
+// Forget removes the Device with the supplied ID from the list of the
+// credentials identified by the supplied key, so that a Device that was just
+// changed is read again instead of being served as it was listed.
+func (c *Cache) Forget(key, id string) {
+	l := c.list(key)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.devices, id)
+}
+
+// fill replaces the Devices of the supplied list. Its mutex must be held.
+func (c *Cache) fill(l *deviceList, cl Client, projectID string) ([]packngo.Device, error) {
+	devices, _, err := cl.List(projectID, ListOptions())
+	if err != nil {
+		return nil, err
+	}
+	l.listed = c.now()
+	l.devices = make(map[string]packngo.Device, len(devices))
+	for _, d := range devices {
+		l.devices[d.ID] = d
+	}
+	return devices, nil
+}
+
+func (c *Cache) list(key string) *deviceList {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.lists[key]
+	if !ok {
+		l = &deviceList{}
+		c.lists[key] = l
+	}
+	return l
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+)
+
+// listClient is a Client whose List calls are counted, and that panics on
+// any other call.
+type listClient struct {
+	Client
+	devices []packngo.Device
+	err     error
+	lists   int
+}
+
+func (c *listClient) List(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+	c.lists++
+	return c.devices, nil, c.err
+}
+
+func TestCache(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	c := NewCache(time.Minute)
+	c.now = func() time.Time { return now }
+	cl := &listClient{devices: []packngo.Device{{ID: "a", Hostname: "a"}, {ID: "b", Hostname: "b"}}}
+
+	get := func(id string, wantOK bool, wantLists int) {
+		t.Helper()
+		d, ok, err := c.Get(cl, "key", "project", id)
+		if err != nil {
+			t.Fatalf("Get(%q): unexpected error: %s", id, err)
+		}
+		if ok != wantOK {
+			t.Errorf("Get(%q): want ok %t, got %t", id, wantOK, ok)
+		}
+		if ok && d.ID != id {
+			t.Errorf("Get(%q): got Device %q", id, d.ID)
+		}
+		if diff := cmp.Diff(wantLists, cl.lists); diff != "" {
+			t.Errorf("Get(%q): lists: -want, +got:\n%s", id, diff)
+		}
+	}
+
+	get("a", true, 1)
+	get("b", true, 1)
+	get("c", false, 1)
+
+	c.Forget("key", "a")
+	get("a", false, 1)
+
+	now = now.Add(2 * time.Minute)
+	get("a", true, 2)
+
+	now = now.Add(2 * time.Minute)
+	cl.err = errors.New("boom")
+	if _, _, err := c.Get(cl, "key", "project", "a"); err == nil {
+		t.Errorf("Get(...): expected an error when the Devices cannot be listed")
+	}
+}
+
+// BenchmarkCacheGet gets Devices of a project of 1000 Devices from a Cache
+// whose list is fresh, as observing each Device of a large fleet does.
+func BenchmarkCacheGet(b *testing.B) {
+	cl := &listClient{}
+	for i := 0; i < 1000; i++ {
+		cl.devices = append(cl.devices, packngo.Device{ID: strconv.Itoa(i), Hostname: strconv.Itoa(i)})
+	}
+	c := NewCache(time.Hour)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, ok, err := c.Get(cl, "key", "project", strconv.Itoa(i%1000)); !ok || err != nil {
+				b.Fatalf("Get(...): want Device, got %t, %v", ok, err)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkCacheRefresh lists a project of 1000 Devices into a Cache.
+func BenchmarkCacheRefresh(b *testing.B) {
+	cl := &listClient{}
+	for i := 0; i < 1000; i++ {
+		cl.devices = append(cl.devices, packngo.Device{ID: strconv.Itoa(i), Hostname: strconv.Itoa(i)})
+	}
+	c := NewCache(time.Hour)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Refresh(cl, "key", "project"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -19,7 +19,11 @@ package device
 import (
 	"context"
 	"fmt"
+	"path"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
@@ -27,6 +31,8 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
@@ -40,28 +46,51 @@ const (
 // Devices for the Equinix Metal Crossplane Provider
 type Client interface {
 	Get(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error)
+	List(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error)
 	Create(*packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error)
 	Delete(deviceID string, force bool) (*packngo.Response, error)
 	Update(string, *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error)
+	ListEvents(deviceID string, opts *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error)
 }
 
 // PortsClient implements the Equinix Metal API methods needed to interact with
 // Ports for the Equinix Metal Crossplane Provider
+//
+// The network type of a Device is computed from the ports and addresses
+// returned when reading it, see packngo.Device.GetNetworkType, so only the
+// conversion of a read Device is needed.
 type PortsClient interface {
-	DeviceToNetworkType(string, string) (*packngo.Device, error)
-	DeviceNetworkType(string) (string, error)
 	ConvertDevice(*packngo.Device, string) error
 }
 
+// PlansClient implements the Equinix Metal API methods needed to check the
+// features of a Device against the catalog of its plan.
+type PlansClient interface {
+	ProjectList(projectID string, listOpt *packngo.ListOptions) ([]packngo.Plan, *packngo.Response, error)
+}
+
+// ActionsClient implements the Equinix Metal API methods needed to perform
+// actions, such as a reboot, on a Device.
+type ActionsClient interface {
+	PerformAction(deviceID, actionType string) (*packngo.Response, error)
+}
+
 // build-time test that the interface is implemented
 var _ Client = (&packngo.Client{}).Devices
 var _ PortsClient = (&packngo.Client{}).DevicePorts //nolint:staticcheck
+var _ PlansClient = (&packngo.Client{}).Plans
+
+// Generate the fake used by controller tests.
+//go:generate go run -tags generate github.com/matryer/moq -out fake/zz_generated.mock.go -pkg fake . ClientWithDefaults:MockClient
 
 // ClientWithDefaults is an interface that provides Device services and
 // provides default values for common properties
 type ClientWithDefaults interface {
 	Client
 	PortsClient
+	PlansClient
+	OSClient
+	ActionsClient
 	clients.DefaultGetter
 }
 
@@ -69,11 +98,103 @@ type ClientWithDefaults interface {
 type CredentialedClient struct {
 	Client
 	PortsClient
+	PlansClient
+	OSClient
+	ActionsClient
 	*clients.Credentials
 }
 
 var _ ClientWithDefaults = &CredentialedClient{}
 
+// ObserveOptions returns the options of the Get calls made to observe a
+// Device. They exclude the large nested objects the provider does not read,
+// while the network ports and IP addresses the network type is computed from
+// are returned by default.
+func ObserveOptions() *packngo.GetOptions {
+	return &packngo.GetOptions{Excludes: []string{"plan", "project", "ssh_keys", "volumes"}}
+}
+
+// MaxRecentEvents is the number of events reported in the status of a Device.
+const MaxRecentEvents = 10
+
+// RecentEventsOptions returns the options of the ListEvents calls made to
+// observe the most recent events of a Device. Only the first page is read.
+func RecentEventsOptions() *packngo.ListOptions {
+	return &packngo.ListOptions{Page: 1, PerPage: MaxRecentEvents}
+}
+
+// TagUIDPrefix prefixes the tag identifying the managed resource a Device was
+// created for. The provider tags the Devices it creates, and ignores its tags
+// when comparing the tags of a Device to the desired tags.
+const TagUIDPrefix = "crossplane-uid:"
+
+// UIDTag returns the tag of the Devices created for the managed resource with
+// the supplied UID.
+func UIDTag(uid types.UID) string {
+	return TagUIDPrefix + string(uid)
+}
+
+// UserTags returns the supplied tags without the tags added by the provider.
+func UserTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	user := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if !strings.HasPrefix(t, TagUIDPrefix) {
+			user = append(user, t)
+		}
+	}
+	return user
+}
+
+// withUIDTag returns the desired tags of the supplied Device, including the
+// tag identifying it if it has a UID.
+func withUIDTag(d *v1alpha2.Device) []string {
+	tags := d.Spec.ForProvider.Tags
+	if d.GetUID() == "" {
+		return tags
+	}
+	return append(append(make([]string, 0, len(tags)+1), tags...), UIDTag(d.GetUID()))
+}
+
+// CreatedForOptions returns the options of the List calls made to find the
+// Device created for the managed resource with the supplied UID.
+func CreatedForOptions(uid types.UID) *packngo.ListOptions {
+	return &packngo.ListOptions{Search: UIDTag(uid), Excludes: []string{"plan", "project", "ssh_keys", "volumes"}}
+}
+
+// CreatedFor returns the Device of the supplied Devices that was created for
+// the managed resource with the supplied UID, if any.
+func CreatedFor(devices []packngo.Device, uid types.UID) *packngo.Device {
+	if uid == "" {
+		return nil
+	}
+	tag := UIDTag(uid)
+	for i := range devices {
+		for _, t := range devices[i].Tags {
+			if t == tag {
+				return &devices[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Deadline returns when the supplied Device is to be deleted: the earlier of
+// its terminateAt and its ttl after its creation. It returns nil if the
+// Device has neither.
+func Deadline(d *v1alpha2.Device) *metav1.Time {
+	deadline := d.Spec.ForProvider.TerminateAt
+	if ttl := d.Spec.TTL; ttl != nil {
+		t := metav1.NewTime(d.GetCreationTimestamp().Add(ttl.Duration))
+		if deadline == nil || t.Before(deadline) {
+			deadline = &t
+		}
+	}
+	return deadline
+}
+
 // NewClient returns a Client implementing the Equinix Metal API methods needed
 // to interact with Devices for the Equinix Metal Crossplane Provider
 func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
@@ -82,9 +203,12 @@ func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefa
 		return nil, err
 	}
 	deviceClient := CredentialedClient{
-		Client:      client.Client.Devices,
-		PortsClient: client.Client.DevicePorts, //nolint:staticcheck
-		Credentials: client.Credentials,
+		Client:        client.Client.Devices,
+		PortsClient:   client.Client.DevicePorts, //nolint:staticcheck
+		PlansClient:   client.Client.Plans,
+		OSClient:      catalog{client.Client.OperatingSystems},
+		ActionsClient: actions{client.Client},
+		Credentials:   client.Credentials,
 	}
 	deviceClient.SetProjectID(config.ProjectID)
 	return deviceClient, nil
@@ -105,13 +229,12 @@ func CreateFromDevice(d *v1alpha2.Device, projectID string) *packngo.DeviceCreat
 	r := &packngo.DeviceCreateRequest{
 		Hostname:              emptyIfNil(d.Spec.ForProvider.Hostname),
 		Plan:                  d.Spec.ForProvider.Plan,
-		Facility:              []string{d.Spec.ForProvider.Facility},
 		Metro:                 d.Spec.ForProvider.Metro,
 		OS:                    d.Spec.ForProvider.OS,
 		BillingCycle:          emptyIfNil(d.Spec.ForProvider.BillingCycle),
 		ProjectID:             projectID,
 		UserData:              emptyIfNil(d.Spec.ForProvider.UserData),
-		Tags:                  d.Spec.ForProvider.Tags,
+		Tags:                  withUIDTag(d),
 		IPAddresses:           ips,
 		CustomData:            emptyIfNil(d.Spec.ForProvider.CustomData),
 		IPXEScriptURL:         emptyIfNil(d.Spec.ForProvider.IPXEScriptURL),
@@ -128,6 +251,11 @@ func CreateFromDevice(d *v1alpha2.Device, projectID string) *packngo.DeviceCreat
 		// SpotPriceMax
 		// TerminationTime
 	}
+	// A Device placed by metro has no facility, which the API rejects
+	// when sent empty.
+	if d.Spec.ForProvider.Facility != "" {
+		r.Facility = []string{d.Spec.ForProvider.Facility}
+	}
 
 	return r
 }
@@ -139,6 +267,13 @@ func emptyIfNil(in *string) string {
 	return *in
 }
 
+func nilIfEmpty(in string) *string {
+	if in == "" {
+		return nil
+	}
+	return &in
+}
+
 func zeroIfNil(in *int) int {
 	if in == nil {
 		return 0
@@ -183,11 +318,27 @@ func GenerateObservation(device *packngo.Device) (v1alpha2.DeviceObservation, er
 		State:  device.State,
 		Locked: device.Locked,
 		IPv4:   device.GetNetworkInfo().PublicIPv4,
+		IPv6:   device.GetNetworkInfo().PublicIPv6,
 	}
 
 	if device.Facility != nil {
 		observation.Facility = device.Facility.Code
 	}
+	if device.Metro != nil {
+		observation.Metro = device.Metro.Code
+	}
+	if device.OS != nil {
+		observation.OS = device.OS.Slug
+	}
+	if device.Project != nil {
+		// NOTE: Devices are fetched without their project, which is then
+		// only represented by its href.
+		observation.ProjectID = device.Project.ID
+		if observation.ProjectID == "" && device.Project.URL != "" {
+			observation.ProjectID = path.Base(device.Project.URL)
+		}
+	}
+	observation.Hardware = GenerateHardware(device)
 
 	// TODO: investigate better way to do this
 	observation.ProvisionPercentage = apiresource.MustParse(fmt.Sprintf("%.6f", device.ProvisionPer))
@@ -206,6 +357,51 @@ func GenerateObservation(device *packngo.Device) (v1alpha2.DeviceObservation, er
 	return observation, nil
 }
 
+// GenerateEvents returns the status representation of the supplied events of
+// a Device, at most MaxRecentEvents of them.
+func GenerateEvents(events []packngo.Event) []v1alpha2.DeviceEvent {
+	if len(events) > MaxRecentEvents {
+		events = events[:MaxRecentEvents]
+	}
+	out := make([]v1alpha2.DeviceEvent, 0, len(events))
+	for _, e := range events {
+		de := v1alpha2.DeviceEvent{Type: e.Type, Body: e.Interpolated}
+		if de.Body == "" {
+			de.Body = e.Body
+		}
+		if e.CreatedAt != nil {
+			t := metav1.NewTime(e.CreatedAt.Time)
+			de.CreatedAt = &t
+		}
+		out = append(out, de)
+	}
+	return out
+}
+
+// EventTypeProvisionStarted is the type of the event that starts the
+// provisioning of a device.
+const EventTypeProvisionStarted = "provisioning.101"
+
+// phoneHome matches the description of the event reporting that a device
+// phoned home once its operating system completed its first boot.
+var phoneHome = regexp.MustCompile(`(?i)phoned?[ -]home`)
+
+// PhoneHome returns the event reporting that the device phoned home since its
+// provisioning last started, or nil if there is none among the supplied
+// events, newest first.
+func PhoneHome(events []packngo.Event) *packngo.Event {
+	for i := range events {
+		e := &events[i]
+		if e.Type == EventTypeProvisionStarted {
+			return nil
+		}
+		if phoneHome.MatchString(e.Interpolated) || phoneHome.MatchString(e.Body) {
+			return e
+		}
+	}
+	return nil
+}
+
 // LateInitialize fills the empty fields in *v1alpha2.DeviceParameters with the
 // values seen in packngo.Device
 func LateInitialize(in *v1alpha2.DeviceParameters, device *packngo.Device) {
@@ -251,7 +447,38 @@ func LateInitialize(in *v1alpha2.DeviceParameters, device *packngo.Device) {
 	// in.Description = device.Description
 
 	if in.Tags == nil {
-		in.Tags = device.Tags
+		in.Tags = UserTags(device.Tags)
+	}
+}
+
+// Clone fills the empty fields in *v1alpha2.DeviceParameters of a Device that
+// clones the supplied packngo.Device with its plan, operating system, billing
+// cycle, user data, tags and network type. Its metro is cloned only if neither
+// a metro nor a facility is set, and its user data only if no userdataRef is.
+func Clone(in *v1alpha2.DeviceParameters, device *packngo.Device) {
+	if device.Plan != nil {
+		in.Plan = clients.LateInitializeString(in.Plan, &device.Plan.Slug)
+	}
+	if device.OS != nil {
+		in.OS = clients.LateInitializeString(in.OS, &device.OS.Slug)
+	}
+	if device.Metro != nil && in.Metro == "" && in.Facility == "" {
+		in.Metro = device.Metro.Code
+	}
+	in.BillingCycle = clients.LateInitializeStringPtr(in.BillingCycle, nilIfEmpty(device.BillingCycle))
+	if in.UserDataRef == nil {
+		in.UserData = clients.LateInitializeStringPtr(in.UserData, nilIfEmpty(device.UserData))
+	}
+	if in.OS == v1alpha2.OSCustomIPXE {
+		in.IPXEScriptURL = clients.LateInitializeStringPtr(in.IPXEScriptURL, nilIfEmpty(device.IPXEScriptURL))
+		in.AlwaysPXE = clients.LateInitializeBoolPtr(in.AlwaysPXE, &device.AlwaysPXE)
+	}
+	if device.NetworkPorts != nil {
+		networkType := device.GetNetworkType()
+		in.NetworkType = clients.LateInitializeStringPtr(in.NetworkType, &networkType)
+	}
+	if in.Tags == nil {
+		in.Tags = UserTags(device.Tags)
 	}
 }
 
@@ -260,27 +487,60 @@ func LateInitialize(in *v1alpha2.DeviceParameters, device *packngo.Device) {
 // modified in place without deleting and recreating the instance, which are
 // immutable.
 func IsUpToDate(d *v1alpha2.Device, p *packngo.Device) (upToDate bool, networkTypeUpToDate bool) {
-	networkType := p.GetNetworkType()
-	networkIsUpToDate := nilOrEqualStr(d.Spec.ForProvider.NetworkType, networkType)
+	networkType, other := SplitNetworkType(Differences(d, p))
+	return len(other) == 0, networkType == nil
+}
 
-	if !nilOrEqualStr(d.Spec.ForProvider.Hostname, p.Hostname) {
-		return false, networkIsUpToDate
-	}
-	if !nilOrEqualStr(d.Spec.ForProvider.UserData, p.UserData) {
-		return false, networkIsUpToDate
-	}
-	if !nilOrEqualStr(d.Spec.ForProvider.IPXEScriptURL, p.IPXEScriptURL) {
-		return false, networkIsUpToDate
-	}
+// FieldNetworkType is the field of the network type in Differences. The
+// network type is updated separately from the other fields.
+const FieldNetworkType = "networkType"
 
-	if !nilOrEqualBool(d.Spec.ForProvider.Locked, p.Locked) {
-		return false, networkIsUpToDate
-	}
+// redacted replaces the values of sensitive fields in Differences.
+const redacted = "<redacted>"
+
+// A Difference is a field of a Device whose desired value differs from the
+// Equinix Metal device.
+type Difference struct {
+	// Field is the name of the field in spec.forProvider.
+	Field string
 
-	if !nilOrEqualBool(d.Spec.ForProvider.AlwaysPXE, p.AlwaysPXE) {
-		return false, networkIsUpToDate
+	// Desired and Actual are the formatted desired and actual values.
+	Desired string
+	Actual  string
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: desired %s, actual %s", d.Field, d.Desired, d.Actual)
+}
+
+// Differences returns the fields of the supplied Kubernetes resource that
+// differ from the supplied Equinix Metal resource, considering the same fields
+// as IsUpToDate. The values of sensitive fields, such as the userdata, are
+// redacted.
+func Differences(d *v1alpha2.Device, p *packngo.Device) []Difference {
+	fp := d.Spec.ForProvider
+	diffs := []Difference{}
+	str := func(field string, desired *string, actual string, sensitive bool) {
+		switch {
+		case nilOrEqualStr(desired, actual):
+		case sensitive:
+			diffs = append(diffs, Difference{Field: field, Desired: redacted, Actual: redacted})
+		default:
+			diffs = append(diffs, Difference{Field: field, Desired: strconv.Quote(*desired), Actual: strconv.Quote(actual)})
+		}
+	}
+	boolean := func(field string, desired *bool, actual bool) {
+		if !nilOrEqualBool(desired, actual) {
+			diffs = append(diffs, Difference{Field: field, Desired: strconv.FormatBool(*desired), Actual: strconv.FormatBool(actual)})
+		}
 	}
 
+	str("hostname", fp.Hostname, p.Hostname, false)
+	str("userdata", fp.UserData, p.UserData, true)
+	str("ipxeScriptUrl", fp.IPXEScriptURL, p.IPXEScriptURL, false)
+	boolean("locked", fp.Locked, p.Locked)
+	boolean("alwaysPXE", fp.AlwaysPXE, p.AlwaysPXE)
+
 	// TODO(displague) CustomData is string vs map[string]interface{}
 	/* TODO(displague) missing: https://github.com/packethost/packngo/pull/182
 	if d.Spec.ForProvider.Description != p.Description {
@@ -288,11 +548,47 @@ func IsUpToDate(d *v1alpha2.Device, p *packngo.Device) (upToDate bool, networkTy
 	}
 	*/
 
-	if !reflect.DeepEqual(d.Spec.ForProvider.Tags, p.Tags) {
-		return false, networkIsUpToDate
+	// A Device without tags may be returned with empty or no tags, so nil
+	// and empty tags are alike.
+	if tags := UserTags(p.Tags); (len(fp.Tags) > 0 || len(tags) > 0) && !reflect.DeepEqual(fp.Tags, tags) {
+		diffs = append(diffs, Difference{Field: "tags", Desired: fmt.Sprintf("%q", fp.Tags), Actual: fmt.Sprintf("%q", tags)})
 	}
+	str(FieldNetworkType, fp.NetworkType, p.GetNetworkType(), false)
+	return diffs
+}
 
-	return true, networkIsUpToDate
+// SplitNetworkType returns the difference of the network type, if any, and
+// the other differences.
+func SplitNetworkType(diffs []Difference) (*Difference, []Difference) {
+	var networkType *Difference
+	other := make([]Difference, 0, len(diffs))
+	for i := range diffs {
+		if diffs[i].Field == FieldNetworkType {
+			networkType = &diffs[i]
+			continue
+		}
+		other = append(other, diffs[i])
+	}
+	return networkType, other
+}
+
+// LogValues returns the supplied differences as structured logging key value
+// pairs, the field mapped to its old and new value.
+func LogValues(diffs []Difference) []interface{} {
+	kv := make([]interface{}, 0, 2*len(diffs))
+	for _, d := range diffs {
+		kv = append(kv, d.Field, map[string]string{"old": d.Actual, "new": d.Desired})
+	}
+	return kv
+}
+
+// SummarizeDifferences returns the supplied differences as one line.
+func SummarizeDifferences(diffs []Difference) string {
+	s := make([]string, len(diffs))
+	for i, d := range diffs {
+		s[i] = d.String()
+	}
+	return strings.Join(s, "; ")
 }
 
 // nilOrEqualStr is true if a (aPtr) is non-nil and equal to b
@@ -306,15 +602,17 @@ func nilOrEqualBool(aPtr *bool, b bool) bool {
 }
 
 // NewUpdateDeviceRequest creates a request to update an instance suitable for
-// use with the Equinix Metal API.
+// use with the Equinix Metal API. The tag identifying the managed resource is
+// kept.
 func NewUpdateDeviceRequest(d *v1alpha2.Device) *packngo.DeviceUpdateRequest {
+	tags := withUIDTag(d)
 	return &packngo.DeviceUpdateRequest{
 		Hostname:      d.Spec.ForProvider.Hostname,
 		Locked:        d.Spec.ForProvider.Locked,
 		UserData:      d.Spec.ForProvider.UserData,
 		IPXEScriptURL: d.Spec.ForProvider.IPXEScriptURL,
 		AlwaysPXE:     d.Spec.ForProvider.AlwaysPXE,
-		Tags:          &d.Spec.ForProvider.Tags,
+		Tags:          &tags,
 		Description:   d.Spec.ForProvider.Description,
 		CustomData:    d.Spec.ForProvider.CustomData,
 	}
@@ -0,0 +1,185 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/packethost/packngo"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+// The fields of DeviceParameters set by fuzzDevice, one bit each.
+const (
+	setHostname = 1 << iota
+	setUserData
+	setIPXEScriptURL
+	setBillingCycle
+	setLocked
+	setAlwaysPXE
+	setTags
+	setSubnetSize
+	setIPAddresses
+)
+
+// fuzzDevice returns a Device whose parameters are set from the supplied
+// values, leaving those whose bit is not set nil.
+func fuzzDevice(set uint16, uid, hostname, userdata, ipxe, billing string, locked, alwaysPXE bool, tags string, size int) *v1alpha2.Device {
+	d := &v1alpha2.Device{}
+	d.SetUID(types.UID(uid))
+	fp := &d.Spec.ForProvider
+	if set&setHostname != 0 {
+		fp.Hostname = &hostname
+	}
+	if set&setUserData != 0 {
+		fp.UserData = &userdata
+	}
+	if set&setIPXEScriptURL != 0 {
+		fp.IPXEScriptURL = &ipxe
+	}
+	if set&setBillingCycle != 0 {
+		fp.BillingCycle = &billing
+	}
+	if set&setLocked != 0 {
+		fp.Locked = &locked
+	}
+	if set&setAlwaysPXE != 0 {
+		fp.AlwaysPXE = &alwaysPXE
+	}
+	if set&setTags != 0 {
+		fp.Tags = []string{}
+		if tags != "" {
+			fp.Tags = strings.Split(tags, ",")
+		}
+	}
+	if set&setSubnetSize != 0 {
+		fp.PublicIPv4SubnetSize = &size
+	}
+	if set&setIPAddresses != 0 {
+		fp.IPAddresses = []v1alpha2.IPAddress{{AddressFamily: 4, CIDR: size}}
+	}
+	return d
+}
+
+func validUTF8(s ...string) bool {
+	for _, v := range s {
+		if !utf8.ValidString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// roundTrip decodes the JSON encoding of in into out, as the API reads
+// requests and the provider reads responses.
+func roundTrip(t *testing.T, in, out interface{}) {
+	t.Helper()
+	j, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal(...): %s", err)
+	}
+	if err := json.Unmarshal(j, out); err != nil {
+		t.Fatalf("json.Unmarshal(...): %s", err)
+	}
+}
+
+// FuzzRequests checks that a Device created from, or updated to, the
+// parameters of a Device is up to date with them, save for the fields that
+// cannot be set when creating a Device.
+func FuzzRequests(f *testing.F) {
+	f.Add(uint16(0), "", "", "", "", "", false, false, "", 0)
+	f.Add(uint16(setTags), "", "", "", "", "", false, false, "", 0)
+	f.Add(uint16(setTags), "uid", "", "", "", "", false, false, "", 0)
+	f.Add(uint16(0xffff), "uid", "host", "#!/bin/sh", "https://example.org/boot.ipxe", "hourly", true, true, "a,b", 29)
+	f.Add(uint16(0xffff), "uid", "", "", "", "", false, false, ",", -1)
+
+	f.Fuzz(func(t *testing.T, set uint16, uid, hostname, userdata, ipxe, billing string, locked, alwaysPXE bool, tags string, size int) {
+		if !validUTF8(uid, hostname, userdata, ipxe, billing, tags) {
+			t.Skip("Kubernetes objects hold valid UTF-8 only")
+		}
+		d := fuzzDevice(set, uid, hostname, userdata, ipxe, billing, locked, alwaysPXE, tags, size)
+		c := CreateFromDevice(d, "project")
+		p := &packngo.Device{}
+		roundTrip(t, packngo.Device{
+			Hostname:      c.Hostname,
+			UserData:      c.UserData,
+			IPXEScriptURL: c.IPXEScriptURL,
+			AlwaysPXE:     c.AlwaysPXE,
+			BillingCycle:  c.BillingCycle,
+			Tags:          c.Tags,
+		}, p)
+
+		// Observe late initializes the parameters before comparing them.
+		LateInitialize(&d.Spec.ForProvider, p)
+		for _, diff := range Differences(d, p) {
+			switch diff.Field {
+			case "locked", FieldNetworkType:
+			default:
+				t.Errorf("Differences(...): created Device differs: %s", diff)
+			}
+		}
+
+		roundTrip(t, NewUpdateDeviceRequest(d), p)
+		for _, diff := range Differences(d, p) {
+			if diff.Field != FieldNetworkType {
+				t.Errorf("Differences(...): updated Device differs: %s", diff)
+			}
+		}
+	})
+}
+
+// FuzzIsUpToDate checks that IsUpToDate agrees with Differences, and that
+// the comparison of parameters that are all set is symmetric.
+func FuzzIsUpToDate(f *testing.F) {
+	f.Add(uint16(0), "", "", "", false, false, "", "", "", "", false, false, "")
+	f.Add(uint16(0xffff), "host", "#!/bin/sh", "", true, false, "a,b", "host", "#!/bin/sh", "", true, false, "a,b")
+	f.Add(uint16(setTags), "", "", "", false, false, "", "", "", "", false, false, "crossplane-uid:x")
+
+	f.Fuzz(func(t *testing.T, set uint16, h1, u1, i1 string, l1, a1 bool, t1, h2, u2, i2 string, l2, a2 bool, t2 string) {
+		if !validUTF8(h1, u1, i1, t1, h2, u2, i2, t2) {
+			t.Skip("Kubernetes objects hold valid UTF-8 only")
+		}
+		d1 := fuzzDevice(set, "", h1, u1, i1, "", l1, a1, t1, 0)
+		d2 := fuzzDevice(set, "", h2, u2, i2, "", l2, a2, t2, 0)
+		p1, p2 := &packngo.Device{}, &packngo.Device{}
+		roundTrip(t, NewUpdateDeviceRequest(d1), p1)
+		roundTrip(t, NewUpdateDeviceRequest(d2), p2)
+
+		upToDate, _ := IsUpToDate(d1, p2)
+		_, other := SplitNetworkType(Differences(d1, p2))
+		if upToDate != (len(other) == 0) {
+			t.Errorf("IsUpToDate(...): %t, but Differences(...): %v", upToDate, other)
+		}
+
+		// Only parameters that are set are compared, so parameters that
+		// are all set compare alike in both directions.
+		if set&(setHostname|setUserData|setIPXEScriptURL|setLocked|setAlwaysPXE|setTags) != setHostname|setUserData|setIPXEScriptURL|setLocked|setAlwaysPXE|setTags {
+			return
+		}
+		if reverse, _ := IsUpToDate(d2, p1); upToDate != reverse {
+			t.Errorf("IsUpToDate(...): %t comparing one way, %t the other", upToDate, reverse)
+		}
+	})
+}
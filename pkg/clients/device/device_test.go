@@ -0,0 +1,347 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+func TestDifferences(t *testing.T) {
+	hostname, userdata, locked := "desired", "#cloud-config\npassword: hunter2", true
+	d := &v1alpha2.Device{Spec: v1alpha2.DeviceSpec{ForProvider: v1alpha2.DeviceParameters{
+		Hostname: &hostname,
+		UserData: &userdata,
+		Locked:   &locked,
+		Tags:     []string{"a", "b"},
+	}}}
+
+	cases := map[string]struct {
+		device *packngo.Device
+		want   []Difference
+	}{
+		"UpToDate": {
+			device: &packngo.Device{Hostname: hostname, UserData: userdata, Locked: locked, Tags: []string{"a", "b"}},
+			want:   []Difference{},
+		},
+		"UpToDateWithUIDTag": {
+			device: &packngo.Device{Hostname: hostname, UserData: userdata, Locked: locked, Tags: []string{"a", UIDTag("uid"), "b"}},
+			want:   []Difference{},
+		},
+		"Drifted": {
+			device: &packngo.Device{Hostname: "actual", UserData: "#cloud-config", Tags: []string{"a"}},
+			want: []Difference{
+				{Field: "hostname", Desired: `"desired"`, Actual: `"actual"`},
+				{Field: "userdata", Desired: redacted, Actual: redacted},
+				{Field: "locked", Desired: "true", Actual: "false"},
+				{Field: "tags", Desired: `["a" "b"]`, Actual: `["a"]`},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Differences(d, tc.device)); diff != "" {
+				t.Errorf("Differences(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+
+	// Empty desired tags are up to date with a Device returned without tags.
+	empty := d.DeepCopy()
+	empty.Spec.ForProvider.Tags = []string{}
+	if diff := cmp.Diff([]Difference{}, Differences(empty, &packngo.Device{Hostname: hostname, UserData: userdata, Locked: locked})); diff != "" {
+		t.Errorf("Differences(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGenerateEvents(t *testing.T) {
+	events := []packngo.Event{
+		{Type: "provisioning.104", Interpolated: "Connected to the magic install system", Body: "ignored"},
+		{Type: "provisioning.101", Body: "Provision started"},
+	}
+	want := []v1alpha2.DeviceEvent{
+		{Type: "provisioning.104", Body: "Connected to the magic install system"},
+		{Type: "provisioning.101", Body: "Provision started"},
+	}
+	if diff := cmp.Diff(want, GenerateEvents(events)); diff != "" {
+		t.Errorf("GenerateEvents(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGenerateObservationProjectID(t *testing.T) {
+	cases := map[string]struct {
+		project *packngo.Project
+		want    string
+	}{
+		"ID":      {project: &packngo.Project{ID: "my-project"}, want: "my-project"},
+		"Href":    {project: &packngo.Project{URL: "/metal/v1/projects/my-project"}, want: "my-project"},
+		"Missing": {},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := GenerateObservation(&packngo.Device{Project: tc.project})
+			if err != nil {
+				t.Fatalf("GenerateObservation(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, o.ProjectID); diff != "" {
+				t.Errorf("GenerateObservation(...): -want projectID, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPhoneHome(t *testing.T) {
+	phoned := packngo.Event{Type: "provisioning.110", Interpolated: "Device phoned home"}
+
+	cases := map[string]struct {
+		events []packngo.Event
+		want   *packngo.Event
+	}{
+		"PhonedHome": {
+			events: []packngo.Event{{Type: "power.on"}, phoned, {Type: EventTypeProvisionStarted}},
+			want:   &phoned,
+		},
+		"NotYet": {
+			events: []packngo.Event{{Type: "provisioning.104", Body: "Connected to the magic install system"}, {Type: EventTypeProvisionStarted}},
+		},
+		"BeforeReprovisioning": {
+			events: []packngo.Event{{Type: EventTypeProvisionStarted}, phoned},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, PhoneHome(tc.events)); diff != "" {
+				t.Errorf("PhoneHome(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	created := metav1.NewTime(time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC))
+	early := metav1.NewTime(created.Add(time.Hour))
+	late := metav1.NewTime(created.Add(24 * time.Hour))
+	ttl := &metav1.Duration{Duration: 8 * time.Hour}
+	afterTTL := metav1.NewTime(created.Add(ttl.Duration))
+
+	cases := map[string]struct {
+		terminateAt *metav1.Time
+		ttl         *metav1.Duration
+		want        *metav1.Time
+	}{
+		"None":                 {},
+		"TerminateAt":          {terminateAt: &late, want: &late},
+		"TTL":                  {ttl: ttl, want: &afterTTL},
+		"TerminateAtBeforeTTL": {terminateAt: &early, ttl: ttl, want: &early},
+		"TTLBeforeTerminateAt": {terminateAt: &late, ttl: ttl, want: &afterTTL},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &v1alpha2.Device{Spec: v1alpha2.DeviceSpec{TTL: tc.ttl, ForProvider: v1alpha2.DeviceParameters{TerminateAt: tc.terminateAt}}}
+			d.SetCreationTimestamp(created)
+			if diff := cmp.Diff(tc.want, Deadline(d)); diff != "" {
+				t.Errorf("Deadline(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	userdata := "#cloud-config"
+	hourly, monthly := "hourly", "monthly"
+	source := &packngo.Device{
+		Plan:         &packngo.Plan{Slug: "c3.small.x86"},
+		OS:           &packngo.OS{Slug: "ubuntu_20_04"},
+		Metro:        &packngo.Metro{Code: "sv"},
+		BillingCycle: hourly,
+		UserData:     userdata,
+		Tags:         []string{"worker", UIDTag("source-uid")},
+	}
+
+	cases := map[string]struct {
+		in   v1alpha2.DeviceParameters
+		want v1alpha2.DeviceParameters
+	}{
+		"Empty": {
+			want: v1alpha2.DeviceParameters{
+				Plan:         "c3.small.x86",
+				OS:           "ubuntu_20_04",
+				Metro:        "sv",
+				BillingCycle: &hourly,
+				UserData:     &userdata,
+				Tags:         []string{"worker"},
+			},
+		},
+		"Overridden": {
+			in: v1alpha2.DeviceParameters{
+				Plan:         "m3.large.x86",
+				Facility:     "sv15",
+				BillingCycle: &monthly,
+				UserDataRef:  &v1alpha2.DataKeySelector{Kind: "Secret"},
+				Tags:         []string{},
+			},
+			want: v1alpha2.DeviceParameters{
+				Plan:         "m3.large.x86",
+				OS:           "ubuntu_20_04",
+				Facility:     "sv15",
+				BillingCycle: &monthly,
+				UserDataRef:  &v1alpha2.DataKeySelector{Kind: "Secret"},
+				Tags:         []string{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			Clone(&tc.in, source)
+			if diff := cmp.Diff(tc.want, tc.in); diff != "" {
+				t.Errorf("Clone(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRequestsGolden(t *testing.T) {
+	str := func(s string) *string { return &s }
+	yes, no, size := true, false, 30
+
+	cases := map[string]v1alpha2.DeviceParameters{
+		"minimal": {
+			Plan:  "c3.small.x86",
+			Metro: "sv",
+			OS:    "ubuntu_20_04",
+		},
+		"reserved_hardware": {
+			Plan:                  "c3.small.x86",
+			Facility:              "sv15",
+			OS:                    "ubuntu_20_04",
+			Hostname:              str("reserved"),
+			BillingCycle:          str("hourly"),
+			HardwareReservationID: str("next-available"),
+			Locked:                &yes,
+		},
+		"custom_ipxe": {
+			Plan:          "c3.small.x86",
+			Metro:         "sv",
+			OS:            "custom_ipxe",
+			Hostname:      str("ipxe"),
+			IPXEScriptURL: str("https://boot.example.org/boot.ipxe"),
+			AlwaysPXE:     &yes,
+		},
+		"full": {
+			Plan:                 "m3.large.x86",
+			Metro:                "da",
+			OS:                   "ubuntu_20_04",
+			Hostname:             str("full"),
+			Description:          str("A device with every parameter set"),
+			BillingCycle:         str("hourly"),
+			UserData:             str("#cloud-config\npackages: [htop]\n"),
+			CustomData:           str(`{"role":"worker"}`),
+			Tags:                 []string{"crossplane", "golden"},
+			Locked:               &no,
+			AlwaysPXE:            &no,
+			PublicIPv4SubnetSize: &size,
+			UserSSHKeys:          []string{"c3c4e5a2-6a8b-4f8e-9d2b-1e0f3a4b5c6d"},
+			ProjectSSHKeys:       []string{"9f8e7d6c-5b4a-4c3d-8e2f-1a0b9c8d7e6f"},
+			Features:             map[string]string{"tpm": "required"},
+			IPAddresses: []v1alpha2.IPAddress{
+				{AddressFamily: 4, Public: true, CIDR: 30},
+				{AddressFamily: 4, Public: false},
+				{AddressFamily: 6, Public: true, CIDR: 127},
+			},
+		},
+	}
+
+	for name, p := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &v1alpha2.Device{Spec: v1alpha2.DeviceSpec{ForProvider: p}}
+			d.SetUID("golden-uid")
+			test.Golden(t, "create_"+name, CreateFromDevice(d, "golden-project"))
+			test.Golden(t, "update_"+name, NewUpdateDeviceRequest(d))
+		})
+	}
+}
+
+// benchmarkDevice returns an active Device as the API returns it when it is
+// observed, with the network ports and addresses of a typical Device.
+func benchmarkDevice() *packngo.Device {
+	return &packngo.Device{
+		ID:           "2f8a6c1e-5b7d-4c3a-9e1f-0a6b8d4c2e7f",
+		Hostname:     "benchmark",
+		State:        "active",
+		BillingCycle: "hourly",
+		Tags:         []string{"crossplane", "benchmark", UIDTag("uid")},
+		UserData:     "#cloud-config\npackages: [htop]\n",
+		Created:      "2021-01-01T00:00:00Z",
+		Updated:      "2021-01-01T00:10:00Z",
+		Plan:         &packngo.Plan{Slug: "c3.small.x86"},
+		OS:           &packngo.OS{Slug: "ubuntu_20_04"},
+		Metro:        &packngo.Metro{Code: "sv"},
+		Facility:     &packngo.Facility{Code: "sv15"},
+		Network: []*packngo.IPAddressAssignment{
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "198.51.100.1", AddressFamily: 4, Public: true, Management: true, CIDR: 31}},
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "2001:db8::1", AddressFamily: 6, Public: true, Management: true, CIDR: 127}},
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "10.0.0.1", AddressFamily: 4, Public: false, Management: true, CIDR: 31}},
+		},
+		NetworkPorts: []packngo.Port{
+			{Type: "NetworkBondPort", Name: "bond0", Data: packngo.PortData{Bonded: true}, NetworkType: packngo.NetworkTypeL3},
+			{Type: "NetworkPort", Name: "eth0", Data: packngo.PortData{Bonded: true}, Bond: &packngo.BondData{Name: "bond0"}},
+			{Type: "NetworkPort", Name: "eth1", Data: packngo.PortData{Bonded: true}, Bond: &packngo.BondData{Name: "bond0"}},
+		},
+	}
+}
+
+func BenchmarkGenerateObservation(b *testing.B) {
+	p := benchmarkDevice()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateObservation(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLateInitialize(b *testing.B) {
+	p := benchmarkDevice()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fp := &v1alpha2.DeviceParameters{}
+		LateInitialize(fp, p)
+	}
+}
+
+func BenchmarkIsUpToDate(b *testing.B) {
+	p := benchmarkDevice()
+	d := &v1alpha2.Device{}
+	LateInitialize(&d.Spec.ForProvider, p)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if upToDate, networkTypeUpToDate := IsUpToDate(d, p); !upToDate || !networkTypeUpToDate {
+			b.Fatal("IsUpToDate(...): want up to date Device")
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+// A FaultyClient wraps a Device client, injecting the Faults of each of its
+// methods into their calls.
+type FaultyClient struct {
+	device.ClientWithDefaults
+	Faults *packettest.Faults
+}
+
+var _ device.ClientWithDefaults = &FaultyClient{}
+
+// NewFaultyClient returns a FaultyClient wrapping the supplied client.
+func NewFaultyClient(c device.ClientWithDefaults, f *packettest.Faults) *FaultyClient {
+	return &FaultyClient{ClientWithDefaults: c, Faults: f}
+}
+
+// Get calls Get of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Get(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+	f := c.Faults.Next("Get")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	d, resp, err := c.ClientWithDefaults.Get(deviceID, getOpt)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		d = partialDevice(d)
+	}
+	return d, resp, nil
+}
+
+// List calls List of the wrapped client, injecting its next fault.
+func (c *FaultyClient) List(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+	f := c.Faults.Next("List")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	ds, resp, err := c.ClientWithDefaults.List(projectID, listOpt)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial && len(ds) > 1 {
+		ds = ds[:1]
+	}
+	return ds, resp, nil
+}
+
+// Create calls Create of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Create(req *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+	f := c.Faults.Next("Create")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	d, resp, err := c.ClientWithDefaults.Create(req)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		d = partialDevice(d)
+	}
+	return d, resp, nil
+}
+
+// Update calls Update of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Update(deviceID string, req *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+	f := c.Faults.Next("Update")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	d, resp, err := c.ClientWithDefaults.Update(deviceID, req)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		d = partialDevice(d)
+	}
+	return d, resp, nil
+}
+
+// Delete calls Delete of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Delete(deviceID string, force bool) (*packngo.Response, error) {
+	f := c.Faults.Next("Delete")
+	if f.Err != nil && !f.Lost {
+		return nil, f.Err
+	}
+	resp, err := c.ClientWithDefaults.Delete(deviceID, force)
+	return resp, firstErr(err, f.Err)
+}
+
+// ListEvents calls ListEvents of the wrapped client, injecting its next fault.
+func (c *FaultyClient) ListEvents(deviceID string, opts *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error) {
+	f := c.Faults.Next("ListEvents")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	es, resp, err := c.ClientWithDefaults.ListEvents(deviceID, opts)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial && len(es) > 1 {
+		es = es[:1]
+	}
+	return es, resp, nil
+}
+
+// partialDevice returns a copy of the supplied device without its nested
+// objects, as returned when they are excluded or not yet populated.
+func partialDevice(d *packngo.Device) *packngo.Device {
+	if d == nil {
+		return nil
+	}
+	p := *d
+	p.Plan = nil
+	p.OS = nil
+	p.Facility = nil
+	p.Metro = nil
+	p.Network = nil
+	p.NetworkPorts = nil
+	return &p
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
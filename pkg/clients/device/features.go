@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"sort"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+// Features described by the catalog of a plan.
+const (
+	FeatureRAID = "raid"
+	FeatureTXT  = "txt"
+)
+
+// catalogFeatures returns whether the supplied plan supports each feature
+// its catalog describes. Plans whose catalog does not describe their
+// features return none.
+func catalogFeatures(p *packngo.Plan) map[string]bool {
+	if p.Specs == nil || p.Specs.Features == nil {
+		return nil
+	}
+	f := p.Specs.Features
+	return map[string]bool{FeatureRAID: f.Raid, FeatureTXT: f.Txt}
+}
+
+// RequiresCatalogFeatures returns whether the supplied features require any
+// feature described by the catalog of a plan, and so may be checked against
+// it.
+func RequiresCatalogFeatures(features map[string]string) bool {
+	for f, v := range features {
+		if v == v1alpha2.FeatureRequired && (f == FeatureRAID || f == FeatureTXT) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnsupportedFeatures returns the required features of the supplied features
+// that the catalog of the supplied plan lists as unsupported, sorted by name.
+// Features the catalog does not describe are assumed to be supported.
+func UnsupportedFeatures(p *packngo.Plan, features map[string]string) []string {
+	supported := catalogFeatures(p)
+	unsupported := []string{}
+	for f, v := range features {
+		if s, ok := supported[f]; ok && !s && v == v1alpha2.FeatureRequired {
+			unsupported = append(unsupported, f)
+		}
+	}
+	sort.Strings(unsupported)
+	return unsupported
+}
+
+// FindPlan returns the plan with the supplied slug, or nil if there is none.
+func FindPlan(plans []packngo.Plan, slug string) *packngo.Plan {
+	for i := range plans {
+		if plans[i].Slug == slug {
+			return &plans[i]
+		}
+	}
+	return nil
+}
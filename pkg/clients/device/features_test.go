@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+func TestUnsupportedFeatures(t *testing.T) {
+	cases := map[string]struct {
+		plan     packngo.Plan
+		features map[string]string
+		want     []string
+	}{
+		"Supported": {
+			plan:     packngo.Plan{Specs: &packngo.Specs{Features: &packngo.Features{Raid: true, Txt: true}}},
+			features: map[string]string{FeatureRAID: v1alpha2.FeatureRequired, FeatureTXT: v1alpha2.FeatureRequired},
+			want:     []string{},
+		},
+		"Unsupported": {
+			plan:     packngo.Plan{Specs: &packngo.Specs{Features: &packngo.Features{}}},
+			features: map[string]string{FeatureTXT: v1alpha2.FeatureRequired, FeatureRAID: v1alpha2.FeatureRequired},
+			want:     []string{FeatureRAID, FeatureTXT},
+		},
+		"UnsupportedButPreferred": {
+			plan:     packngo.Plan{Specs: &packngo.Specs{Features: &packngo.Features{}}},
+			features: map[string]string{FeatureRAID: v1alpha2.FeaturePreferred},
+			want:     []string{},
+		},
+		"NotInCatalog": {
+			plan:     packngo.Plan{Specs: &packngo.Specs{Features: &packngo.Features{}}},
+			features: map[string]string{"tpm": v1alpha2.FeatureRequired},
+			want:     []string{},
+		},
+		"CatalogWithoutFeatures": {
+			plan:     packngo.Plan{Specs: &packngo.Specs{}},
+			features: map[string]string{FeatureRAID: v1alpha2.FeatureRequired},
+			want:     []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := UnsupportedFeatures(&tc.plan, tc.features)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("UnsupportedFeatures(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
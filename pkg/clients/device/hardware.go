@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+// portTypeNetwork is the type of the bondable ethernet ports of a device, as
+// opposed to the bond ports formed from them.
+const portTypeNetwork = "NetworkPort"
+
+// GenerateHardware returns the hardware of the supplied device its API
+// representation describes, i.e. its network ports, or nil if it describes
+// none.
+func GenerateHardware(device *packngo.Device) *v1alpha2.DeviceHardware {
+	ports := []v1alpha2.DevicePort{}
+	for _, p := range device.NetworkPorts {
+		if p.Type != portTypeNetwork {
+			continue
+		}
+		ports = append(ports, v1alpha2.DevicePort{Name: p.Name, MAC: p.Data.MAC})
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	return &v1alpha2.DeviceHardware{Ports: ports}
+}
+
+// SetPlanHardware sets the CPUs, memory and drives of the supplied hardware
+// to those the catalog of the supplied plan describes.
+func SetPlanHardware(h *v1alpha2.DeviceHardware, p *packngo.Plan) {
+	h.Plan = p.Slug
+	h.CPUs, h.Memory, h.Drives = nil, "", nil
+	if p.Specs == nil {
+		return
+	}
+	for _, c := range p.Specs.Cpus {
+		if c != nil {
+			h.CPUs = append(h.CPUs, v1alpha2.DeviceComponent{Count: c.Count, Type: c.Type})
+		}
+	}
+	if p.Specs.Memory != nil {
+		h.Memory = p.Specs.Memory.Total
+	}
+	for _, d := range p.Specs.Drives {
+		if d != nil {
+			h.Drives = append(h.Drives, v1alpha2.DeviceComponent{Count: d.Count, Type: d.Type, Size: d.Size})
+		}
+	}
+}
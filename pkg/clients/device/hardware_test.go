@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+func TestGenerateHardware(t *testing.T) {
+	cases := map[string]struct {
+		device *packngo.Device
+		want   *v1alpha2.DeviceHardware
+	}{
+		"NetworkPorts": {
+			device: &packngo.Device{NetworkPorts: []packngo.Port{
+				{Type: "NetworkBondPort", Name: "bond0"},
+				{Type: portTypeNetwork, Name: "eth0", Data: packngo.PortData{MAC: "b8:59:9f:00:00:01"}},
+				{Type: portTypeNetwork, Name: "eth1", Data: packngo.PortData{MAC: "b8:59:9f:00:00:02"}},
+			}},
+			want: &v1alpha2.DeviceHardware{Ports: []v1alpha2.DevicePort{
+				{Name: "eth0", MAC: "b8:59:9f:00:00:01"},
+				{Name: "eth1", MAC: "b8:59:9f:00:00:02"},
+			}},
+		},
+		"NoNetworkPorts": {
+			device: &packngo.Device{},
+			want:   nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateHardware(tc.device)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("GenerateHardware(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetPlanHardware(t *testing.T) {
+	cases := map[string]struct {
+		hardware *v1alpha2.DeviceHardware
+		plan     *packngo.Plan
+		want     *v1alpha2.DeviceHardware
+	}{
+		"Specs": {
+			hardware: &v1alpha2.DeviceHardware{Ports: []v1alpha2.DevicePort{{Name: "eth0"}}},
+			plan: &packngo.Plan{Slug: "c3.small.x86", Specs: &packngo.Specs{
+				Cpus:   []*packngo.Cpus{{Count: 1, Type: "Intel Xeon E-2278G"}},
+				Memory: &packngo.Memory{Total: "32GB"},
+				Drives: []*packngo.Drives{{Count: 2, Size: "480GB", Type: "SSD"}},
+			}},
+			want: &v1alpha2.DeviceHardware{
+				Ports:  []v1alpha2.DevicePort{{Name: "eth0"}},
+				Plan:   "c3.small.x86",
+				CPUs:   []v1alpha2.DeviceComponent{{Count: 1, Type: "Intel Xeon E-2278G"}},
+				Memory: "32GB",
+				Drives: []v1alpha2.DeviceComponent{{Count: 2, Type: "SSD", Size: "480GB"}},
+			},
+		},
+		"NoSpecs": {
+			hardware: &v1alpha2.DeviceHardware{Plan: "c3.small.x86", Memory: "32GB"},
+			plan:     &packngo.Plan{Slug: "m3.large.x86"},
+			want:     &v1alpha2.DeviceHardware{Plan: "m3.large.x86"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SetPlanHardware(tc.hardware, tc.plan)
+			if diff := cmp.Diff(tc.want, tc.hardware); diff != "" {
+				t.Errorf("SetPlanHardware(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
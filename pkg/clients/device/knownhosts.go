@@ -0,0 +1,187 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+// ConnectionDetailKnownHosts is the connection detail holding the known_hosts
+// entries of a Device, i.e. the SSH host keys of its public IPv4 address.
+const ConnectionDetailKnownHosts = "known_hosts"
+
+// DefaultKeyscanTimeout is the time limit of scanning the SSH host keys of a
+// Device.
+const DefaultKeyscanTimeout = 10 * time.Second
+
+// KeyscanRetryInterval is how long a Device whose SSH host keys could not be
+// scanned is not scanned again, so that unreachable Devices do not delay each
+// observation by the scan timeout.
+const KeyscanRetryInterval = 5 * time.Minute
+
+// Error strings.
+const (
+	errKeyscanRetryFmt = "cannot scan SSH host keys of %s: retrying after %s"
+	errNoHostKeys      = "no SSH host key of a supported algorithm"
+)
+
+// errHostKeyScanned aborts an SSH handshake once the host key is received.
+var errHostKeyScanned = errors.New("host key scanned")
+
+// hostKeyAlgorithms are the algorithms of the host keys scanned, one SSH
+// handshake each.
+var hostKeyAlgorithms = []string{ssh.KeyAlgoED25519, ssh.KeyAlgoECDSA256, ssh.KeyAlgoRSA}
+
+// A Keyscanner collects the SSH host keys of Devices, like ssh-keyscan, once
+// they are active, and remembers them until the Devices are seen inactive,
+// e.g. while being reinstalled with new host keys.
+type Keyscanner struct {
+	timeout time.Duration
+	now     func() time.Time
+	scan    func(ctx context.Context, addr, algorithm string) (ssh.PublicKey, error)
+
+	mu    sync.Mutex
+	hosts map[string]*scannedHost
+}
+
+// A scannedHost holds the known_hosts entries of the address of a Device, or
+// when scanning it last failed.
+type scannedHost struct {
+	address    string
+	knownHosts string
+	failed     time.Time
+}
+
+// NewKeyscanner returns a Keyscanner whose scans of the host keys of a Device
+// take at most the supplied timeout.
+func NewKeyscanner(timeout time.Duration) *Keyscanner {
+	return &Keyscanner{timeout: timeout, now: time.Now, scan: scanHostKey, hosts: map[string]*scannedHost{}}
+}
+
+// KnownHosts returns the known_hosts entries of the supplied device, scanning
+// its host keys unless they were scanned before. It returns an empty string
+// for devices that are not active or have no public IPv4 address.
+func (k *Keyscanner) KnownHosts(ctx context.Context, device *packngo.Device) (string, error) {
+	address := device.GetNetworkInfo().PublicIPv4
+	if device.State != v1alpha2.StateActive || address == "" {
+		k.Forget(device.ID)
+		return "", nil
+	}
+
+	k.mu.Lock()
+	h, ok := k.hosts[device.ID]
+	k.mu.Unlock()
+	if ok && h.address == address {
+		if h.knownHosts != "" {
+			return h.knownHosts, nil
+		}
+		if retry := h.failed.Add(KeyscanRetryInterval); k.now().Before(retry) {
+			return "", errors.Errorf(errKeyscanRetryFmt, address, retry.UTC().Format(time.RFC3339))
+		}
+	}
+
+	h = &scannedHost{address: address}
+	kh, err := k.knownHosts(ctx, address)
+	if err != nil {
+		h.failed = k.now()
+	}
+	h.knownHosts = kh
+	k.mu.Lock()
+	k.hosts[device.ID] = h
+	k.mu.Unlock()
+	return kh, err
+}
+
+// Forget the host keys of the Device with the supplied ID, so that they are
+// scanned again should it become active.
+func (k *Keyscanner) Forget(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.hosts, id)
+}
+
+// knownHosts returns a known_hosts entry per host key of the supplied address
+// of a supported algorithm.
+func (k *Keyscanner) knownHosts(ctx context.Context, address string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(address, "22")
+	lines := []string{}
+	var err error
+	for _, algorithm := range hostKeyAlgorithms {
+		var key ssh.PublicKey
+		key, err = k.scan(ctx, addr, algorithm)
+		if err != nil {
+			// An unreachable address has no host key of any algorithm.
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		lines = append(lines, knownhosts.Line([]string{address}, key))
+	}
+	if len(lines) == 0 {
+		if err == nil {
+			err = errors.New(errNoHostKeys)
+		}
+		return "", err
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// scanHostKey returns the host key of the supplied algorithm of the SSH
+// server at the supplied address. The handshake is abandoned once the host
+// key is received, so no authentication is attempted.
+func scanHostKey(ctx context.Context, addr, algorithm string) (ssh.PublicKey, error) {
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() //nolint:errcheck
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	var key ssh.PublicKey
+	cfg := &ssh.ClientConfig{
+		User:              "root",
+		HostKeyAlgorithms: []string{algorithm},
+		HostKeyCallback: func(_ string, _ net.Addr, k ssh.PublicKey) error {
+			key = k
+			return errHostKeyScanned
+		},
+	}
+	if _, _, _, err := ssh.NewClientConn(conn, addr, cfg); key == nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+func newHostKey(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestScanHostKey(t *testing.T) {
+	hostKey := newHostKey(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close() //nolint:errcheck
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			cfg := &ssh.ServerConfig{NoClientAuth: true}
+			cfg.AddHostKey(hostKey)
+			go func() {
+				_, _, _, _ = ssh.NewServerConn(conn, cfg)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultKeyscanTimeout)
+	defer cancel()
+
+	got, err := scanHostKey(ctx, l.Addr().String(), ssh.KeyAlgoED25519)
+	if err != nil {
+		t.Fatalf("scanHostKey(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(hostKey.PublicKey().Marshal(), got.Marshal()); diff != "" {
+		t.Errorf("scanHostKey(...): -want, +got:\n%s", diff)
+	}
+
+	if _, err := scanHostKey(ctx, l.Addr().String(), ssh.KeyAlgoECDSA256); err == nil {
+		t.Errorf("scanHostKey(...): expected an error for an algorithm the server has no host key of")
+	}
+}
+
+func TestKeyscannerKnownHosts(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	key := newHostKey(t).PublicKey()
+	errBoom := errors.New("boom")
+
+	active := &packngo.Device{ID: "d", State: v1alpha2.StateActive, Network: []*packngo.IPAddressAssignment{{
+		IpAddressCommon: packngo.IpAddressCommon{Address: "192.0.2.1", Public: true, Management: true, AddressFamily: 4},
+	}}}
+	reinstalling := &packngo.Device{ID: "d", State: v1alpha2.StateReinstalling, Network: active.Network}
+	line := knownhosts.Line([]string{"192.0.2.1"}, key) + "\n"
+
+	type step struct {
+		device  *packngo.Device
+		scanErr error
+		after   time.Duration
+		want    string
+		wantErr bool
+		scans   int
+	}
+	cases := map[string][]step{
+		"ScannedOnce": {
+			{device: active, want: line, scans: 3},
+			{device: active, want: line, scans: 0},
+		},
+		"RescannedAfterReinstall": {
+			{device: active, want: line, scans: 3},
+			{device: reinstalling, want: "", scans: 0},
+			{device: active, want: line, scans: 3},
+		},
+		"RetriedAfterInterval": {
+			{device: active, scanErr: errBoom, wantErr: true, scans: 3},
+			{device: active, wantErr: true, scans: 0},
+			{device: active, after: KeyscanRetryInterval, want: line, scans: 3},
+		},
+	}
+
+	for name, steps := range cases {
+		t.Run(name, func(t *testing.T) {
+			k := NewKeyscanner(DefaultKeyscanTimeout)
+			clock := now
+			k.now = func() time.Time { return clock }
+			for i, s := range steps {
+				clock = clock.Add(s.after)
+				scans := 0
+				k.scan = func(_ context.Context, addr, algorithm string) (ssh.PublicKey, error) {
+					scans++
+					if addr != "192.0.2.1:22" {
+						t.Errorf("step %d: scan(...): unexpected address %q", i, addr)
+					}
+					if s.scanErr != nil {
+						return nil, s.scanErr
+					}
+					if algorithm != ssh.KeyAlgoED25519 {
+						return nil, errBoom
+					}
+					return key, nil
+				}
+				got, err := k.KnownHosts(context.Background(), s.device)
+				if (err != nil) != s.wantErr {
+					t.Errorf("step %d: KnownHosts(...): unexpected error: %v", i, err)
+				}
+				if diff := cmp.Diff(s.want, got); diff != "" {
+					t.Errorf("step %d: KnownHosts(...): -want, +got:\n%s", i, diff)
+				}
+				if scans != s.scans {
+					t.Errorf("step %d: KnownHosts(...): want %d scans, got %d", i, s.scans, scans)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+)
+
+// Error strings.
+const (
+	errInvalidConstraintFmt = "invalid version constraint %q"
+	errNoOSFmt              = "no version of operating system %q satisfies %q and is provisionable on plan %q"
+)
+
+// OSClient implements the Equinix Metal API methods needed to resolve the
+// operating system of a Device from the operating system catalog.
+type OSClient interface {
+	ListOS() ([]packngo.OS, *packngo.Response, error)
+}
+
+// catalog lists the operating system catalog with an OSService, whose List
+// method would otherwise collide with that of the Device service.
+type catalog struct {
+	packngo.OSService
+}
+
+func (c catalog) ListOS() ([]packngo.OS, *packngo.Response, error) {
+	return c.List()
+}
+
+// A versionConstraint is satisfied by the versions that satisfy all its
+// clauses.
+type versionConstraint []versionClause
+
+type versionClause struct {
+	op      string
+	version string
+}
+
+// Operators of version clauses, longest first so that they are parsed
+// greedily.
+var versionOps = []string{">=", "<=", "==", "!=", ">", "<", "="}
+
+// parseVersionConstraint parses a comma separated list of clauses, each an
+// optional operator followed by a version, e.g. ">=20.04, <22.04". A version
+// without an operator must be matched exactly.
+func parseVersionConstraint(s string) (versionConstraint, error) {
+	c := versionConstraint{}
+	for _, clause := range strings.Split(s, ",") {
+		clause = strings.TrimSpace(clause)
+		op := "="
+		for _, o := range versionOps {
+			if strings.HasPrefix(clause, o) {
+				op = o
+				clause = strings.TrimSpace(strings.TrimPrefix(clause, o))
+				break
+			}
+		}
+		if clause == "" {
+			return nil, errors.Errorf(errInvalidConstraintFmt, s)
+		}
+		c = append(c, versionClause{op: op, version: clause})
+	}
+	return c, nil
+}
+
+func (c versionConstraint) satisfiedBy(version string) bool {
+	for _, cl := range c {
+		cmp := compareVersions(version, cl.version)
+		ok := false
+		switch cl.op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "!=":
+			ok = cmp != 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares dot separated versions part by part, numerically
+// if both parts are numbers, and returns -1, 0 or 1 if a is older than, the
+// same as or newer than b. Missing parts are zero, so 22.04 is 22.04.0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		pa, pb := "0", "0"
+		if i < len(as) {
+			pa = as[i]
+		}
+		if i < len(bs) {
+			pb = bs[i]
+		}
+		na, errA := strconv.Atoi(pa)
+		nb, errB := strconv.Atoi(pb)
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			if na < nb {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && pa != pb:
+			if pa < pb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// ResolveOS returns the slug of the latest version of the supplied operating
+// system distribution, e.g. ubuntu, that satisfies the supplied version
+// constraint and is provisionable on the supplied plan, according to the
+// supplied operating system catalog.
+func ResolveOS(oses []packngo.OS, distro, constraint, plan string) (string, error) {
+	c, err := parseVersionConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	var latest *packngo.OS
+	for i := range oses {
+		os := &oses[i]
+		if !strings.EqualFold(os.Distro, distro) || os.Version == "" || !c.satisfiedBy(os.Version) || !provisionableOn(os, plan) {
+			continue
+		}
+		if latest == nil || compareVersions(os.Version, latest.Version) > 0 {
+			latest = os
+		}
+	}
+	if latest == nil {
+		return "", errors.Errorf(errNoOSFmt, distro, constraint, plan)
+	}
+	return latest.Slug, nil
+}
+
+// provisionableOn returns whether the supplied operating system can be
+// provisioned on the supplied plan. Operating systems that list no plans are
+// assumed to be provisionable on all of them.
+func provisionableOn(os *packngo.OS, plan string) bool {
+	if len(os.ProvisionableOn) == 0 {
+		return true
+	}
+	for _, p := range os.ProvisionableOn {
+		if p == plan {
+			return true
+		}
+	}
+	return false
+}
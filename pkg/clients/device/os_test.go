@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestResolveOS(t *testing.T) {
+	oses := []packngo.OS{
+		{Slug: "ubuntu_18_04", Distro: "ubuntu", Version: "18.04"},
+		{Slug: "ubuntu_20_04", Distro: "ubuntu", Version: "20.04"},
+		{Slug: "ubuntu_22_04", Distro: "ubuntu", Version: "22.04", ProvisionableOn: []string{"c3.small.x86"}},
+		{Slug: "ubuntu_22_10", Distro: "ubuntu", Version: "22.10", ProvisionableOn: []string{"m3.large.x86"}},
+		{Slug: "debian_11", Distro: "debian", Version: "11"},
+	}
+
+	type want struct {
+		slug string
+		err  error
+	}
+
+	cases := map[string]struct {
+		distro     string
+		constraint string
+		plan       string
+		want       want
+	}{
+		"Latest": {
+			distro:     "Ubuntu",
+			constraint: ">=20.04",
+			plan:       "c3.small.x86",
+			want:       want{slug: "ubuntu_22_04"},
+		},
+		"Range": {
+			distro:     "ubuntu",
+			constraint: ">=18.04, <22",
+			plan:       "c3.small.x86",
+			want:       want{slug: "ubuntu_20_04"},
+		},
+		"Exact": {
+			distro:     "debian",
+			constraint: "11.0",
+			plan:       "c3.small.x86",
+			want:       want{slug: "debian_11"},
+		},
+		"NotProvisionable": {
+			distro:     "ubuntu",
+			constraint: ">22.04",
+			plan:       "c3.small.x86",
+			want:       want{err: errors.Errorf(errNoOSFmt, "ubuntu", ">22.04", "c3.small.x86")},
+		},
+		"InvalidConstraint": {
+			distro:     "ubuntu",
+			constraint: ">=20.04,",
+			want:       want{err: errors.Errorf(errInvalidConstraintFmt, ">=20.04,")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			slug, err := ResolveOS(oses, tc.distro, tc.constraint, tc.plan)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ResolveOS(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.slug, slug); diff != "" {
+				t.Errorf("ResolveOS(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+)
+
+const (
+	errVirtualNetworkAlreadyContents = " already "
+	errVirtualNetworkAlreadyPrefix   = "Virtual network"
+	errCapacityContents              = "capacity"
+)
+
+// errQuotaContents are the contents of the messages of API errors caused by
+// exceeding a quota of the project or organization.
+var errQuotaContents = []string{"quota", "maximum number", "limit reached", "limit exceeded"}
+
+// APIError returns the Equinix Metal API error response wrapped by err, if
+// any.
+func APIError(err error) (*packngo.ErrorResponse, bool) {
+	e := &packngo.ErrorResponse{}
+	if !errors.As(err, &e) || e.Response == nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// StatusCode returns the HTTP status code of the Equinix Metal API error
+// response wrapped by err, or 0 if err does not wrap one.
+func StatusCode(err error) int {
+	if e, ok := APIError(err); ok {
+		return e.Response.StatusCode
+	}
+	return 0
+}
+
+// apiMessage returns the messages of the Equinix Metal API error response
+// wrapped by err joined into one.
+func apiMessage(err error) string {
+	e, ok := APIError(err)
+	if !ok {
+		return ""
+	}
+	return strings.Join(append(e.Errors, e.SingleError), "")
+}
+
+// HeaderRequestID is the response header identifying an Equinix Metal API
+// request server side.
+const HeaderRequestID = "X-Request-Id"
+
+// RequestID returns the ID of the request that failed with the Equinix Metal
+// API error response wrapped by err, or an empty string if err does not wrap
+// one.
+func RequestID(err error) string {
+	if e, ok := APIError(err); ok {
+		return e.Response.Header.Get(HeaderRequestID)
+	}
+	return ""
+}
+
+// IsNotFound returns true if error is not found, including because the
+// resource was removed and is gone. Deleting a resource that was removed
+// out-of-band, for example, succeeds.
+func IsNotFound(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusNotFound, http.StatusGone:
+		return true
+	}
+	return false
+}
+
+// IsRateLimited returns true if the request was rejected because the API key
+// exceeded its rate limit.
+func IsRateLimited(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
+
+// DefaultRetryAfter is how long to wait before retrying a rate limited request
+// when the API does not say.
+const DefaultRetryAfter = 10 * time.Second
+
+// RetryAfter returns how long to wait before retrying the request that failed
+// with err, if it failed because of the Equinix Metal API rate limit. It
+// returns false for other errors.
+func RetryAfter(err error) (time.Duration, bool) {
+	if IsRateLimited(err) {
+		e, _ := APIError(err)
+		if d, ok := ParseRetryAfter(e.Response.Header.Get(HeaderRetryAfter), time.Now()); ok && d > 0 {
+			return d, true
+		}
+		return DefaultRetryAfter, true
+	}
+	te := &ThrottledError{}
+	if errors.As(err, &te) {
+		return te.RetryAfter, true
+	}
+	return 0, false
+}
+
+// Deferred returns how long until the low priority request that failed with
+// err may be sent, if it was deferred because the rate limit is nearly
+// exhausted. It returns false for other errors, including requests refused by
+// the rate limit.
+func Deferred(err error) (time.Duration, bool) {
+	te := &ThrottledError{}
+	if errors.As(err, &te) && te.Deferred {
+		return te.RetryAfter, true
+	}
+	return 0, false
+}
+
+// IsConflict returns true if the request conflicts with the current state of
+// the resource, for example a concurrent modification.
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsCapacity returns true if the request was rejected because the requested
+// plan has no capacity in the requested metro or facility.
+func IsCapacity(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusUnprocessableEntity, http.StatusServiceUnavailable:
+		return strings.Contains(strings.ToLower(apiMessage(err)), errCapacityContents)
+	}
+	return false
+}
+
+// IsAlreadyDone returns true if, during VLAN assignment operations, the API
+// returns an error like "422 Virtual network 1182 already assigned" or "422
+// Virtual network 1182 already unassigned"
+func IsAlreadyDone(err error) bool {
+	if StatusCode(err) != http.StatusUnprocessableEntity {
+		return false
+	}
+	msg := apiMessage(err)
+	return strings.Contains(msg, errVirtualNetworkAlreadyContents) &&
+		strings.HasPrefix(msg, errVirtualNetworkAlreadyPrefix)
+}
+
+// IsQuotaExceeded returns true if the request was rejected because it would
+// exceed a quota of the project or organization, such as its maximum number of
+// Devices.
+func IsQuotaExceeded(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusForbidden, http.StatusUnprocessableEntity:
+		msg := strings.ToLower(apiMessage(err))
+		for _, c := range errQuotaContents {
+			if strings.Contains(msg, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsInvalidCredentials returns true if the request was rejected because the
+// API key is invalid, or is not allowed to access the project.
+func IsInvalidCredentials(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusForbidden:
+		return !IsQuotaExceeded(err)
+	}
+	return false
+}
+
+// An ErrorClass tells apart the causes of failed requests that call for
+// different reactions, such as fixing credentials or waiting for capacity.
+type ErrorClass string
+
+// Error classes.
+const (
+	ErrorClassNone                 ErrorClass = ""
+	ErrorClassInvalidCredentials   ErrorClass = "InvalidCredentials"
+	ErrorClassInsufficientCapacity ErrorClass = "InsufficientCapacity"
+	ErrorClassQuotaExceeded        ErrorClass = "QuotaExceeded"
+	ErrorClassRateLimited          ErrorClass = "RateLimited"
+	ErrorClassConflict             ErrorClass = "Conflict"
+)
+
+// Classify returns the class of the supplied error, or ErrorClassNone if it
+// is not an error of a known class. Deferred low priority requests are not
+// reported as errors, see Deferred.
+func Classify(err error) ErrorClass {
+	_, throttled := RetryAfter(err)
+	_, deferred := Deferred(err)
+	switch {
+	case err == nil, deferred:
+		return ErrorClassNone
+	case throttled:
+		return ErrorClassRateLimited
+	case IsCapacity(err):
+		return ErrorClassInsufficientCapacity
+	case IsQuotaExceeded(err):
+		return ErrorClassQuotaExceeded
+	case IsInvalidCredentials(err):
+		return ErrorClassInvalidCredentials
+	case IsConflict(err):
+		return ErrorClassConflict
+	}
+	return ErrorClassNone
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+)
+
+func TestErrorClassification(t *testing.T) {
+	apiErr := func(status int, msgs ...string) error {
+		return errors.Wrap(&packngo.ErrorResponse{
+			Response: &http.Response{StatusCode: status, Request: &http.Request{}},
+			Errors:   msgs,
+		}, "wrapped")
+	}
+	type want struct {
+		notFound, rateLimited, conflict, capacity, alreadyDone, quota, credentials bool
+		class                                                                      ErrorClass
+	}
+
+	cases := map[string]struct {
+		err  error
+		want want
+	}{
+		"NotAPIError":   {err: errors.New("boom")},
+		"NotFound":      {err: apiErr(http.StatusNotFound, "Not found"), want: want{notFound: true}},
+		"Gone":          {err: apiErr(http.StatusGone), want: want{notFound: true}},
+		"RateLimited":   {err: apiErr(http.StatusTooManyRequests), want: want{rateLimited: true, class: ErrorClassRateLimited}},
+		"Conflict":      {err: apiErr(http.StatusConflict), want: want{conflict: true, class: ErrorClassConflict}},
+		"Capacity":      {err: apiErr(http.StatusUnprocessableEntity, "Oh snap, the facility has no Capacity for c3.small.x86"), want: want{capacity: true, class: ErrorClassInsufficientCapacity}},
+		"Unprocessable": {err: apiErr(http.StatusUnprocessableEntity, "hostname is invalid")},
+		"AlreadyDone":   {err: apiErr(http.StatusUnprocessableEntity, "Virtual network 1182 already assigned"), want: want{alreadyDone: true}},
+		"Unauthorized":  {err: apiErr(http.StatusUnauthorized, "Invalid authentication token"), want: want{credentials: true, class: ErrorClassInvalidCredentials}},
+		"Forbidden":     {err: apiErr(http.StatusForbidden, "You are not authorized to view this project"), want: want{credentials: true, class: ErrorClassInvalidCredentials}},
+		"Quota":         {err: apiErr(http.StatusForbidden, "You have reached the maximum number of devices"), want: want{quota: true, class: ErrorClassQuotaExceeded}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{
+				notFound:    IsNotFound(tc.err),
+				rateLimited: IsRateLimited(tc.err),
+				conflict:    IsConflict(tc.err),
+				capacity:    IsCapacity(tc.err),
+				alreadyDone: IsAlreadyDone(tc.err),
+				quota:       IsQuotaExceeded(tc.err),
+				credentials: IsInvalidCredentials(tc.err),
+				class:       Classify(tc.err),
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("-want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	type want struct {
+		after time.Duration
+		ok    bool
+	}
+	rateLimited := func(h http.Header) error {
+		return errors.Wrap(&packngo.ErrorResponse{
+			Response: &http.Response{StatusCode: http.StatusTooManyRequests, Header: h, Request: &http.Request{}},
+		}, "wrapped")
+	}
+
+	cases := map[string]struct {
+		err  error
+		want want
+	}{
+		"NotRateLimited": {err: errors.New("boom")},
+		"RetryAfterHeader": {
+			err:  rateLimited(http.Header{HeaderRetryAfter: []string{"42"}}),
+			want: want{after: 42 * time.Second, ok: true},
+		},
+		"NoRetryAfterHeader": {
+			err:  rateLimited(nil),
+			want: want{after: DefaultRetryAfter, ok: true},
+		},
+		"Throttled": {
+			err:  errors.Wrap(&url.Error{Op: "Get", URL: "https://api.equinix.com", Err: &ThrottledError{RetryAfter: time.Minute}}, "wrapped"),
+			want: want{after: time.Minute, ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			after, ok := RetryAfter(tc.err)
+			if diff := cmp.Diff(tc.want, want{after: after, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("RetryAfter(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeferred(t *testing.T) {
+	type want struct {
+		after time.Duration
+		ok    bool
+	}
+
+	cases := map[string]struct {
+		err  error
+		want want
+	}{
+		"NotThrottled": {err: errors.New("boom")},
+		"Throttled": {
+			err: errors.Wrap(&url.Error{Op: "Get", URL: "https://api.equinix.com", Err: &ThrottledError{RetryAfter: time.Minute}}, "wrapped"),
+		},
+		"Deferred": {
+			err:  errors.Wrap(&url.Error{Op: "Get", URL: "https://api.equinix.com", Err: &ThrottledError{RetryAfter: time.Minute, Deferred: true}}, "wrapped"),
+			want: want{after: time.Minute, ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			after, ok := Deferred(tc.err)
+			if diff := cmp.Diff(tc.want, want{after: after, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("Deferred(...): -want, +got:\n%s", diff)
+			}
+			if ok && Classify(tc.err) != ErrorClassNone {
+				t.Errorf("Classify(...): want %q for a deferred request, got %q", ErrorClassNone, Classify(tc.err))
+			}
+		})
+	}
+}
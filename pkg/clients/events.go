@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+// ReasonAPIError is the reason of events describing a failed Equinix Metal API
+// request.
+const ReasonAPIError event.Reason = "EquinixMetalAPIError"
+
+// ReasonDrift is the reason of events describing how an external resource
+// differs from the desired state of its managed resource.
+const ReasonDrift event.Reason = "ExternalResourceDrifted"
+
+// ReasonDeadlinePassed is the reason of events describing a managed resource
+// that is deleted because its deadline passed.
+const ReasonDeadlinePassed event.Reason = "DeadlinePassed"
+
+// ReasonSpendThresholdExceeded is the reason of events describing a project
+// whose spend exceeds the threshold of its Budget.
+const ReasonSpendThresholdExceeded event.Reason = "SpendThresholdExceeded"
+
+// ReasonPlannedChange is the reason of events describing a change a provider
+// in plan mode would make to an external resource.
+const ReasonPlannedChange event.Reason = "PlannedChange"
+
+// ReasonAction is the reason of events describing an action, such as a
+// reboot, performed on an external resource.
+const ReasonAction event.Reason = "Action"
+
+// NewAPIErrorEvent returns a Warning event describing the supplied Equinix
+// Metal API error, including the HTTP status code and the messages returned by
+// the API. It returns false if err does not wrap an API error response.
+func NewAPIErrorEvent(op string, err error) (event.Event, bool) {
+	e, ok := APIError(err)
+	if !ok {
+		return event.Event{}, false
+	}
+	msgs := []string{}
+	for _, m := range append(e.Errors, e.SingleError) {
+		if m != "" {
+			msgs = append(msgs, m)
+		}
+	}
+	msg := fmt.Sprintf("%s failed with status %d %s", op, e.Response.StatusCode, strings.Join(msgs, ", "))
+	return event.Warning(ReasonAPIError, errors.New(strings.TrimSpace(msg)), "statusCode", strconv.Itoa(e.Response.StatusCode)), true
+}
+
+// RecordAPIError records a Warning event on obj if err wraps an Equinix Metal
+// API error response.
+func RecordAPIError(r event.Recorder, obj runtime.Object, op string, err error) {
+	if ev, ok := NewAPIErrorEvent(op, err); ok {
+		r.Event(obj, ev)
+	}
+}
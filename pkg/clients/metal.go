@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/packethost/packngo"
@@ -43,11 +42,6 @@ type Client struct {
 	Client *packngo.Client
 }
 
-const (
-	errVirtualNetworkAlreadyContents = " already "
-	errVirtualNetworkAlreadyPrefix   = "Virtual network"
-)
-
 // NewCredentialsFromJSON parses JSON bytes returning an Equinix Metal Credentials configuration
 func NewCredentialsFromJSON(j []byte) (*Credentials, error) {
 	config := &Credentials{}
@@ -57,18 +51,42 @@ func NewCredentialsFromJSON(j []byte) (*Credentials, error) {
 	return config, nil
 }
 
-// NewClient returns an Equinix Metal Client configured with credentials
+// NewClient returns an Equinix Metal Client configured with credentials. Its
+// requests are bound to the supplied context, so that they are cancelled with
+// it, and rate limited as requests of the controller set by WithController.
+// The underlying HTTP client is shared with other Clients configured with the
+// same credentials.
 func NewClient(ctx context.Context, config *Credentials) (*Client, error) {
 	apiKey := config.GetAPIKey(CredentialAPIKey)
 	if apiKey == "" {
 		return nil, fmt.Errorf("Invalid APIKey in credentials")
 	}
-	apiClient := packngo.NewClientWithAuth("crossplane", apiKey, nil)
-	apiClient.UserAgent = fmt.Sprintf("crossplane-provider-equinix-metal/%s %s", version.Version, apiClient.UserAgent)
-
+	controller := controllerFrom(ctx)
+	shared := apiClients.Get(CredentialsKey(config)+controller, func() *http.Client {
+		base, timeout := getBaseTransport()
+		throttled := NewThrottledTransport(base, ThrottlerFor(apiKey))
+		transport := throttled
+		if log, trace := getAPILogger(); log != nil {
+			transport = NewLoggingTransport(transport, log.WithValues("controller", controller), trace)
+		}
+		transport = NewUserAgentTransport(transport, version.UserAgent())
+		return &http.Client{
+			Transport: NewRetryTransport(NewRateLimitedTransport(transport, getRateLimiter(), controller), DefaultMaxRetries),
+			Timeout:   timeout,
+		}
+	})
+
+	// The HTTP client is shared by all connections using these credentials,
+	// while each connection gets its own Credentials and packngo client, whose
+	// requests are bound to the supplied context and traced as its children.
+	apiClient := packngo.NewClientWithAuth("crossplane", apiKey, &http.Client{
+		Transport: NewContextTransport(ctx, NewTracingTransport(shared.Transport)),
+		Timeout:   shared.Timeout,
+	})
+	creds := *config
 	client := &Client{
 		Client:      apiClient,
-		Credentials: config,
+		Credentials: &creds,
 	}
 
 	return client, nil
@@ -98,6 +116,12 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
 		return nil, err
 	}
+	return ProviderConfigCredentials(ctx, c, pc)
+}
+
+// ProviderConfigCredentials returns the credentials of the supplied
+// ProviderConfig, including its project.
+func ProviderConfigCredentials(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig) (*Credentials, error) {
 	data, err := resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, c, pc.Spec.Credentials.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot get credentials")
@@ -109,26 +133,6 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 	if pc.Spec.ProjectID != "" {
 		config.SetProjectID(pc.Spec.ProjectID)
 	}
+	trackProviderConfig(pc.GetName(), config.GetAPIKey(CredentialAPIKey))
 	return config, err
 }
-
-// IsNotFound returns true if error is not found
-func IsNotFound(err error) bool {
-	if e, ok := err.(*packngo.ErrorResponse); ok && e.Response != nil {
-		return e.Response.StatusCode == http.StatusNotFound
-	}
-	return false
-}
-
-// IsAlreadyDone returns true if, during VLAN assignment operations, the API
-// returns an error like "422 Virtual network 1182 already assigned" or "422
-// Virtual network 1182 already unassigned"
-func IsAlreadyDone(err error) bool {
-	if e, ok := err.(*packngo.ErrorResponse); ok && e.Response != nil {
-		errsInOne := strings.Join(append(e.Errors, e.SingleError), "")
-		return e.Response.StatusCode == http.StatusUnprocessableEntity &&
-			strings.Contains(errsInOne, errVirtualNetworkAlreadyContents) &&
-			strings.HasPrefix(errsInOne, errVirtualNetworkAlreadyPrefix)
-	}
-	return false
-}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package organization
+
+import (
+	"context"
+	"strings"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/organization/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Client implements the Equinix Metal API methods needed to interact with
+// Organizations for the Equinix Metal Crossplane Provider
+type Client interface {
+	Get(organizationID string, getOpt *packngo.GetOptions) (*packngo.Organization, *packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ Client = (&packngo.Client{}).Organizations
+
+// Generate the fake used by controller tests.
+//go:generate go run -tags generate github.com/matryer/moq -out fake/zz_generated.mock.go -pkg fake . ClientWithDefaults:MockClient
+
+// ClientWithDefaults is an interface that provides Organization services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal Organization
+// services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to interact with Organizations for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	orgClient := CredentialedClient{
+		Client:      client.Client.Organizations,
+		Credentials: client.Credentials,
+	}
+	orgClient.SetProjectID(config.ProjectID)
+	return orgClient, nil
+}
+
+// MembersOptions returns the options of the Get calls made to observe the
+// members of an Organization. They include the members and owners, which the
+// API otherwise returns as references.
+func MembersOptions() *packngo.GetOptions {
+	return &packngo.GetOptions{Includes: []string{"members", "owners"}}
+}
+
+// FindMember returns the member of the supplied Organization with the
+// supplied email address, and its roles, or nil if it has none. Email
+// addresses are compared case-insensitively, against every address of the
+// users.
+func FindMember(o *packngo.Organization, email string) (*packngo.User, []string) {
+	for i := range o.Users {
+		u := &o.Users[i]
+		if !hasEmail(u, email) {
+			continue
+		}
+		roles := []string{v1alpha1.RoleMember}
+		for _, owner := range o.Owners {
+			if owner.ID == u.ID {
+				roles = append(roles, v1alpha1.RoleOwner)
+				break
+			}
+		}
+		return u, roles
+	}
+	return nil, nil
+}
+
+func hasEmail(u *packngo.User, email string) bool {
+	if strings.EqualFold(u.Email, email) {
+		return true
+	}
+	for _, e := range u.Emails {
+		if strings.EqualFold(e.Address, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultPerPage is the page size requested by ListAll.
+	DefaultPerPage = 100
+
+	// maxPages bounds ListAll should the API ignore the page parameter.
+	maxPages = 1000
+
+	errTooManyPagesFmt = "list exceeds %d pages"
+)
+
+// A PageFn lists the page of results selected by the supplied options and
+// returns the number of results on that page.
+type PageFn func(opts *packngo.ListOptions) (int, error)
+
+// ListAll calls fn for every page of a list, following the page and per_page
+// query parameters from the first page until a page is not full. The supplied
+// options are copied; their Page and PerPage are ignored.
+func ListAll(opts *packngo.ListOptions, fn PageFn) error {
+	o := opts.CopyOrNew()
+	o.PerPage = DefaultPerPage
+	for o.Page = 1; o.Page <= maxPages; o.Page++ {
+		n, err := fn(o)
+		if err != nil {
+			return err
+		}
+		if n < o.PerPage {
+			return nil
+		}
+	}
+	return errors.Errorf(errTooManyPagesFmt, maxPages)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+)
+
+func TestListAll(t *testing.T) {
+	total := 2*DefaultPerPage + 1
+	pages := []int{}
+
+	err := ListAll(&packngo.ListOptions{Includes: []string{"facility"}}, func(opts *packngo.ListOptions) (int, error) {
+		pages = append(pages, opts.Page)
+		if diff := cmp.Diff([]string{"facility"}, opts.Includes); diff != "" {
+			t.Errorf("ListAll(...): -want includes, +got:\n%s", diff)
+		}
+		n := total - (opts.Page-1)*opts.PerPage
+		if n > opts.PerPage {
+			n = opts.PerPage
+		}
+		return n, nil
+	})
+	if err != nil {
+		t.Fatalf("ListAll(...): %s", err)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3}, pages); diff != "" {
+		t.Errorf("ListAll(...): -want pages, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ports"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+// A FaultyClient wraps a Port client, injecting the Faults of each of its
+// methods into their calls.
+type FaultyClient struct {
+	ports.ClientWithDefaults
+	Faults *packettest.Faults
+}
+
+var _ ports.ClientWithDefaults = &FaultyClient{}
+
+// NewFaultyClient returns a FaultyClient wrapping the supplied client.
+func NewFaultyClient(c ports.ClientWithDefaults, f *packettest.Faults) *FaultyClient {
+	return &FaultyClient{ClientWithDefaults: c, Faults: f}
+}
+
+// Assign calls Assign of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Assign(req *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+	f := c.Faults.Next("Assign")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	p, resp, err := c.ClientWithDefaults.Assign(req)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		p = partialPort(p)
+	}
+	return p, resp, nil
+}
+
+// Unassign calls Unassign of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Unassign(req *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+	f := c.Faults.Next("Unassign")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	p, resp, err := c.ClientWithDefaults.Unassign(req)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		p = partialPort(p)
+	}
+	return p, resp, nil
+}
+
+// GetPortByName calls GetPortByName of the wrapped client, injecting its next
+// fault.
+func (c *FaultyClient) GetPortByName(deviceID, name string) (*packngo.Port, error) {
+	f := c.Faults.Next("GetPortByName")
+	if f.Err != nil && !f.Lost {
+		return nil, f.Err
+	}
+	p, err := c.ClientWithDefaults.GetPortByName(deviceID, name)
+	if err != nil || f.Err != nil {
+		return nil, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		p = partialPort(p)
+	}
+	return p, nil
+}
+
+// partialPort returns a copy of the supplied port without its virtual
+// networks, as returned before an assignment is reflected.
+func partialPort(p *packngo.Port) *packngo.Port {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	c.AttachedVirtualNetworks = nil
+	return &c
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -35,6 +35,9 @@ type Client interface {
 // build-time test that the interface is implemented
 var _ Client = (&packngo.Client{}).DevicePorts //nolint:staticcheck
 
+// Generate the fake used by controller tests.
+//go:generate go run -tags generate github.com/matryer/moq -out fake/zz_generated.mock.go -pkg fake . ClientWithDefaults:MockClient
+
 // ClientWithDefaults is an interface that provides Port services and
 // provides default values for common properties
 type ClientWithDefaults interface {
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	rateLimitDesc = prometheus.NewDesc(
+		"equinix_metal_api_rate_limit",
+		"Equinix Metal API rate limit most recently reported for the API key of a ProviderConfig.",
+		[]string{"provider_config"}, nil,
+	)
+	rateRemainingDesc = prometheus.NewDesc(
+		"equinix_metal_api_rate_limit_remaining",
+		"Equinix Metal API requests remaining until the rate limit resets, as most recently reported for the API key of a ProviderConfig.",
+		[]string{"provider_config"}, nil,
+	)
+)
+
+// providerConfigThrottlers maps the names of the ProviderConfigs used by the
+// controllers to the Throttlers of their API keys.
+var providerConfigThrottlers sync.Map
+
+// trackProviderConfig records that the named ProviderConfig uses the supplied
+// API key, so that its rate limit is exported.
+func trackProviderConfig(name, apiKey string) {
+	if name == "" || apiKey == "" {
+		return
+	}
+	providerConfigThrottlers.Store(name, ThrottlerFor(apiKey))
+}
+
+// RegisterQuotaMetrics registers gauges of the most recently reported rate
+// limit and remaining requests of the API key of each ProviderConfig with the
+// supplied registry. Registering them more than once has no effect.
+func RegisterQuotaMetrics(reg prometheus.Registerer) error {
+	err := reg.Register(quotaCollector{})
+	if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return nil
+	}
+	return err
+}
+
+type quotaCollector struct{}
+
+func (quotaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- rateLimitDesc
+	ch <- rateRemainingDesc
+}
+
+func (quotaCollector) Collect(ch chan<- prometheus.Metric) {
+	providerConfigThrottlers.Range(func(k, v interface{}) bool {
+		name := k.(string)
+		remaining, limit := v.(*Throttler).Remaining()
+		if limit > 0 {
+			ch <- prometheus.MustNewConstMetric(rateLimitDesc, prometheus.GaugeValue, float64(limit), name)
+		}
+		if remaining >= 0 {
+			ch <- prometheus.MustNewConstMetric(rateRemainingDesc, prometheus.GaugeValue, float64(remaining), name)
+		}
+		return true
+	})
+}
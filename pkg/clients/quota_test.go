@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestQuotaCollector(t *testing.T) {
+	trackProviderConfig("observed", "quota-test-observed")
+	trackProviderConfig("unobserved", "quota-test-unobserved")
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set(HeaderRateLimit, "600")
+	resp.Header.Set(HeaderRateRemaining, "42")
+	ThrottlerFor("quota-test-observed").Observe(resp)
+
+	want := `
+# HELP equinix_metal_api_rate_limit Equinix Metal API rate limit most recently reported for the API key of a ProviderConfig.
+# TYPE equinix_metal_api_rate_limit gauge
+equinix_metal_api_rate_limit{provider_config="observed"} 600
+# HELP equinix_metal_api_rate_limit_remaining Equinix Metal API requests remaining until the rate limit resets, as most recently reported for the API key of a ProviderConfig.
+# TYPE equinix_metal_api_rate_limit_remaining gauge
+equinix_metal_api_rate_limit_remaining{provider_config="observed"} 42
+`
+	if err := testutil.CollectAndCompare(quotaCollector{}, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
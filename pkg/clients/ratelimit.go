@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultAPIRate is the default number of Equinix Metal API requests per
+	// second shared by all controllers.
+	DefaultAPIRate = 10
+
+	// DefaultAPIBurst is the default number of Equinix Metal API requests
+	// that may be sent at once.
+	DefaultAPIBurst = 20
+
+	// DefaultControllerShare is the default fraction of the API rate any
+	// single controller may use.
+	DefaultControllerShare = 0.5
+)
+
+// A RateLimiter limits the Equinix Metal API requests of all controllers to a
+// global rate. Each controller is also limited to a share of that rate so
+// that one busy controller cannot starve the others.
+type RateLimiter struct {
+	global *rate.Limiter
+	share  float64
+
+	mu          sync.Mutex
+	controllers map[string]*rate.Limiter
+}
+
+// NewRateLimiter returns a RateLimiter allowing r requests per second with
+// the supplied burst, of which any one controller may use the supplied share.
+// A rate of zero or less is unlimited.
+func NewRateLimiter(r rate.Limit, burst int, share float64) *RateLimiter {
+	if r <= 0 {
+		r = rate.Inf
+	}
+	if share <= 0 || share > 1 {
+		share = 1
+	}
+	return &RateLimiter{
+		global:      rate.NewLimiter(r, burst),
+		share:       share,
+		controllers: map[string]*rate.Limiter{},
+	}
+}
+
+var (
+	apiRateLimiterMu sync.RWMutex
+	apiRateLimiter   = NewRateLimiter(rate.Inf, 0, 1)
+)
+
+// SetRateLimiter sets the RateLimiter of all API clients created afterwards.
+// It is unlimited by default.
+func SetRateLimiter(l *RateLimiter) {
+	apiRateLimiterMu.Lock()
+	defer apiRateLimiterMu.Unlock()
+	apiRateLimiter = l
+}
+
+func getRateLimiter() *RateLimiter {
+	apiRateLimiterMu.RLock()
+	defer apiRateLimiterMu.RUnlock()
+	return apiRateLimiter
+}
+
+// Wait blocks until the named controller may send a request.
+func (l *RateLimiter) Wait(ctx context.Context, controller string) error {
+	if err := l.forController(controller).Wait(ctx); err != nil {
+		return err
+	}
+	return l.global.Wait(ctx)
+}
+
+func (l *RateLimiter) forController(name string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.controllers[name]
+	if !ok {
+		burst := int(float64(l.global.Burst()) * l.share)
+		if burst < 1 {
+			burst = 1
+		}
+		c = rate.NewLimiter(l.global.Limit()*rate.Limit(l.share), burst)
+		l.controllers[name] = c
+	}
+	return c
+}
+
+type controllerKey struct{}
+
+// WithController returns a context whose API clients are rate limited as
+// requests of the named controller.
+func WithController(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, controllerKey{}, name)
+}
+
+func controllerFrom(ctx context.Context) string {
+	name, _ := ctx.Value(controllerKey{}).(string)
+	return name
+}
+
+// rateLimitedTransport delays requests according to a RateLimiter.
+type rateLimitedTransport struct {
+	next       http.RoundTripper
+	limiter    *RateLimiter
+	controller string
+}
+
+// NewRateLimitedTransport returns an http.RoundTripper that delays requests
+// of the named controller according to the supplied RateLimiter.
+func NewRateLimitedTransport(next http.RoundTripper, l *RateLimiter, controller string) http.RoundTripper {
+	return &rateLimitedTransport{next: next, limiter: l, controller: controller}
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context(), t.controller); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+// minRedactedLineLength is the length of the shortest line of a sensitive
+// value that is redacted on its own. Shorter lines, such as "fi" or "}", are
+// too common to tell apart from the rest of a message.
+const minRedactedLineLength = 8
+
+// SensitiveValues returns the values of the sensitive fields of the supplied
+// object, such as the userdata of a Device, that must never appear in logs,
+// events or condition messages.
+func SensitiveValues(obj interface{}) []string {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil
+	}
+	return sensitiveValues(b)
+}
+
+// sensitiveValues returns the values of the sensitive fields of the supplied
+// JSON document.
+func sensitiveValues(b []byte) []string {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	values := []string{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, fv := range t {
+				if s, ok := fv.(string); ok && s != "" && redactedFields[strings.ToLower(k)] {
+					values = append(values, s)
+					continue
+				}
+				walk(fv)
+			}
+		case []interface{}:
+			for i := range t {
+				walk(t[i])
+			}
+		}
+	}
+	walk(v)
+	return values
+}
+
+// Redact returns the supplied message with the supplied sensitive values, and
+// each of their lines that is long enough to be told apart, replaced.
+func Redact(msg string, values ...string) string {
+	if len(values) == 0 {
+		return msg
+	}
+	parts := []string{}
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		parts = append(parts, v)
+		if !strings.Contains(v, "\n") {
+			continue
+		}
+		for _, l := range strings.Split(v, "\n") {
+			if l = strings.TrimSpace(l); len(l) >= minRedactedLineLength {
+				parts = append(parts, l)
+			}
+		}
+	}
+	// Longer parts go first, so that no part of them survives the
+	// replacement of a shorter part they contain.
+	sort.Slice(parts, func(i, j int) bool { return len(parts[i]) > len(parts[j]) })
+	for _, p := range parts {
+		msg = strings.ReplaceAll(msg, p, redacted)
+	}
+	return msg
+}
+
+// RedactError returns the supplied error with the supplied sensitive values
+// redacted from its message. The returned error still wraps the supplied
+// error, so it can be inspected as before.
+func RedactError(err error, values ...string) error {
+	if err == nil {
+		return nil
+	}
+	msg := Redact(err.Error(), values...)
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+func (e *redactedError) Cause() error  { return e.err }
+
+// NewRedactingRecorder returns an event.Recorder that redacts the sensitive
+// values of the object of each event, and the supplied values, from its
+// message before recording it with the supplied Recorder.
+func NewRedactingRecorder(r event.Recorder, values ...string) event.Recorder {
+	return &redactingRecorder{r: r, values: values}
+}
+
+type redactingRecorder struct {
+	r      event.Recorder
+	values []string
+}
+
+func (r *redactingRecorder) Event(obj runtime.Object, e event.Event) {
+	e.Message = Redact(e.Message, append(SensitiveValues(obj), r.values...)...)
+	r.r.Event(obj, e)
+}
+
+func (r *redactingRecorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	return &redactingRecorder{r: r.r.WithAnnotations(keysAndValues...), values: r.values}
+}
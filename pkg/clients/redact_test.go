@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestRedact(t *testing.T) {
+	userdata := "#cloud-config\npassword: hunter2hunter2\nfi"
+
+	cases := map[string]struct {
+		msg    string
+		values []string
+		want   string
+	}{
+		"NoValues": {
+			msg:  "cannot create Device",
+			want: "cannot create Device",
+		},
+		"WholeValue": {
+			msg:    "invalid userdata: " + userdata,
+			values: []string{userdata},
+			want:   "invalid userdata: REDACTED",
+		},
+		"Line": {
+			msg:    "invalid line password: hunter2hunter2",
+			values: []string{userdata},
+			want:   "invalid line REDACTED",
+		},
+		"ShortLine": {
+			msg:    "unexpected fi",
+			values: []string{userdata},
+			want:   "unexpected fi",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Redact(tc.msg, tc.values...)); diff != "" {
+				t.Errorf("Redact(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSensitiveValues(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"forProvider": map[string]interface{}{"hostname": "a", "userdata": "#!/bin/sh", "customData": "{}"},
+		},
+	}
+	want := map[string]bool{"#!/bin/sh": true, "{}": true}
+	got := map[string]bool{}
+	for _, v := range SensitiveValues(obj) {
+		got[v] = true
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SensitiveValues(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	cause := errors.New("userdata #!/bin/sh is invalid")
+	err := RedactError(errors.Wrap(cause, "cannot create Device"), "#!/bin/sh")
+	if diff := cmp.Diff("cannot create Device: userdata REDACTED is invalid", err.Error()); diff != "" {
+		t.Errorf("RedactError(...): -want, +got:\n%s", diff)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("RedactError(...): want error wrapping its cause")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a failed request is retried.
+	DefaultMaxRetries = 3
+
+	// DefaultRetryBaseDelay is the delay before the first retry. It doubles
+	// with every further retry.
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+
+	// DefaultRetryMaxDelay is the longest delay before a retry. Requests
+	// that would need to wait longer fail so that the reconcile is requeued.
+	DefaultRetryMaxDelay = 10 * time.Second
+)
+
+// retryTransport retries requests that failed with a transient error.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+// NewRetryTransport returns an http.RoundTripper that retries requests
+// rejected with 429 Too Many Requests, and idempotent requests that failed
+// with a 502, 503 or 504, using jittered exponential backoff. A Retry-After
+// response header overrides the backoff when it is longer.
+func NewRetryTransport(next http.RoundTripper, maxRetries int) http.RoundTripper {
+	return &retryTransport{
+		next:       next,
+		maxRetries: maxRetries,
+		baseDelay:  DefaultRetryBaseDelay,
+		maxDelay:   DefaultRetryMaxDelay,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || attempt >= t.maxRetries || !retryable(req, resp) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		delay := t.backoff(attempt)
+		if after, ok := ParseRetryAfter(resp.Header.Get(HeaderRetryAfter), time.Now()); ok && after > delay {
+			delay = after
+		}
+		if delay > t.maxDelay {
+			return resp, nil
+		}
+
+		// The response is discarded, so drain it to reuse the connection.
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// backoff returns a random delay between half and all of the exponential
+// backoff of the supplied attempt.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.baseDelay << uint(attempt)
+	if d < 0 || d > t.maxDelay {
+		d = t.maxDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec // Jitter need not be cryptographically secure.
+}
+
+func retryable(req *http.Request, resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(req.Method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
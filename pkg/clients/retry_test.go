@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type roundTripFn func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFn) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		method   string
+		statuses []int
+		want     int
+		wantSent int
+	}{
+		"Success": {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusOK},
+			want:     http.StatusOK,
+			wantSent: 1,
+		},
+		"RetryUnavailable": {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			want:     http.StatusOK,
+			wantSent: 3,
+		},
+		"RetryTooManyRequestsPost": {
+			method:   http.MethodPost,
+			statuses: []int{http.StatusTooManyRequests, http.StatusCreated},
+			want:     http.StatusCreated,
+			wantSent: 2,
+		},
+		"NoRetryUnavailablePost": {
+			method:   http.MethodPost,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusCreated},
+			want:     http.StatusServiceUnavailable,
+			wantSent: 1,
+		},
+		"GiveUp": {
+			method:   http.MethodGet,
+			statuses: []int{503, 503, 503, 503, 503},
+			want:     http.StatusServiceUnavailable,
+			wantSent: DefaultMaxRetries + 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sent := 0
+			next := roundTripFn(func(req *http.Request) (*http.Response, error) {
+				status := tc.statuses[sent]
+				sent++
+				return &http.Response{StatusCode: status, Header: http.Header{}, Body: http.NoBody}, nil
+			})
+			rt := NewRetryTransport(next, DefaultMaxRetries).(*retryTransport)
+			rt.baseDelay = 0
+
+			req, _ := http.NewRequest(tc.method, "https://api.equinix.com/metal/v1/devices", strings.NewReader("{}"))
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, resp.StatusCode); diff != "" {
+				t.Errorf("RoundTrip(...): -want status, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantSent, sent); diff != "" {
+				t.Errorf("RoundTrip(...): -want requests sent, +got:\n%s", diff)
+			}
+		})
+	}
+}
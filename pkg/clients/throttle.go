@@ -0,0 +1,275 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate limit response headers returned by the Equinix Metal API.
+const (
+	HeaderRateLimit     = "X-RateLimit-Limit"
+	HeaderRateRemaining = "X-RateLimit-Remaining"
+	HeaderRateReset     = "X-RateLimit-Reset"
+	HeaderRetryAfter    = "Retry-After"
+)
+
+const (
+	// DefaultMinRemaining is the remaining request budget at which a
+	// Throttler starts delaying requests until the rate limit resets.
+	DefaultMinRemaining = 5
+
+	// DefaultMaxThrottleDelay is the longest a Throttler blocks a request.
+	// Longer delays fail the request so that the reconcile is requeued
+	// rather than holding a worker.
+	DefaultMaxThrottleDelay = 30 * time.Second
+
+	// DefaultLowPriorityReserve is the fraction of the rate limit reserved
+	// for requests that are not low priority. Low priority requests fail
+	// once less of the request budget remains, until the rate limit resets.
+	DefaultLowPriorityReserve = 0.2
+
+	errThrottledFmt = "Equinix Metal API rate limit nearly exhausted, retry after %s"
+)
+
+// A Throttler delays Equinix Metal API requests when the rate limit reported
+// by previous responses is nearly exhausted.
+type Throttler struct {
+	// MinRemaining is the remaining request budget at which requests are
+	// delayed until the rate limit resets.
+	MinRemaining int
+
+	// MaxDelay is the longest a request is delayed.
+	MaxDelay time.Duration
+
+	// LowPriorityReserve is the fraction of the rate limit reserved for
+	// requests that are not low priority, see WithLowPriority.
+	LowPriorityReserve float64
+
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	reset     time.Time
+	resume    time.Time
+	now       func() time.Time
+}
+
+// A ThrottledError is returned instead of sending a request when the rate
+// limit will not allow requests for longer than the MaxDelay of a Throttler.
+type ThrottledError struct {
+	// RetryAfter is how long until the rate limit allows requests again.
+	RetryAfter time.Duration
+
+	// Deferred is true if the request was low priority and deferred until
+	// the rate limit resets, see WithLowPriority.
+	Deferred bool
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf(errThrottledFmt, e.RetryAfter.Round(time.Second))
+}
+
+// NewThrottler returns a Throttler with the default settings.
+func NewThrottler() *Throttler {
+	return &Throttler{
+		MinRemaining:       DefaultMinRemaining,
+		MaxDelay:           DefaultMaxThrottleDelay,
+		LowPriorityReserve: DefaultLowPriorityReserve,
+		remaining:          -1,
+		now:                time.Now,
+	}
+}
+
+var throttlers sync.Map
+
+// ThrottlerFor returns the Throttler shared by all clients using the supplied
+// API key. The Equinix Metal rate limit is accounted per API key.
+func ThrottlerFor(apiKey string) *Throttler {
+	sum := sha256.Sum256([]byte(apiKey))
+	t, _ := throttlers.LoadOrStore(hex.EncodeToString(sum[:]), NewThrottler())
+	return t.(*Throttler)
+}
+
+// Delay returns how long the next request should be delayed.
+func (t *Throttler) Delay() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d := t.resume.Sub(t.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Deferral returns how long low priority requests are deferred: until the
+// rate limit resets if the remaining request budget is within the reserve,
+// otherwise zero.
+func (t *Throttler) Deferral() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.limit <= 0 || t.remaining < 0 || float64(t.remaining) > t.LowPriorityReserve*float64(t.limit) {
+		return 0
+	}
+	if d := t.reset.Sub(t.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Wait blocks until the next request may be sent. It returns an error
+// without waiting if the required delay exceeds MaxDelay, or if the request
+// is low priority and deferred until the rate limit resets.
+func (t *Throttler) Wait(ctx context.Context) error {
+	if lowPriority(ctx) {
+		if d := t.Deferral(); d > 0 {
+			return &ThrottledError{RetryAfter: d, Deferred: true}
+		}
+	}
+	d := t.Delay()
+	if d <= 0 {
+		return nil
+	}
+	if d > t.MaxDelay {
+		return &ThrottledError{RetryAfter: d}
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe records the rate limit headers of the supplied response.
+func (t *Throttler) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+
+	if v, err := strconv.Atoi(resp.Header.Get(HeaderRateLimit)); err == nil {
+		t.limit = v
+	}
+	reset, hasReset := parseReset(resp.Header.Get(HeaderRateReset))
+	if hasReset {
+		t.reset = reset
+	}
+	if v, err := strconv.Atoi(resp.Header.Get(HeaderRateRemaining)); err == nil {
+		t.remaining = v
+		if v <= t.MinRemaining && hasReset {
+			t.extend(reset)
+		}
+	}
+	if after, ok := ParseRetryAfter(resp.Header.Get(HeaderRetryAfter), now); ok {
+		t.extend(now.Add(after))
+	} else if resp.StatusCode == http.StatusTooManyRequests {
+		t.extend(now.Add(time.Second))
+	}
+}
+
+// Remaining returns the most recently observed remaining request budget and
+// limit. Both are -1 and 0 respectively until a response was observed.
+func (t *Throttler) Remaining() (remaining, limit int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.remaining, t.limit
+}
+
+func (t *Throttler) extend(until time.Time) {
+	if until.After(t.resume) {
+		t.resume = until
+	}
+}
+
+type lowPriorityKey struct{}
+
+// WithLowPriority returns a context whose Equinix Metal API requests are low
+// priority, such as those checking resources in a steady state for drift.
+// Clients bind the context they are created with to their requests, see
+// NewClient. Low priority requests fail once the rate limit is nearly
+// exhausted, leaving the remaining requests to others.
+func WithLowPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lowPriorityKey{}, true)
+}
+
+func lowPriority(ctx context.Context) bool {
+	low, _ := ctx.Value(lowPriorityKey{}).(bool)
+	return low
+}
+
+// parseReset parses an X-RateLimit-Reset value, the unix time at which the
+// rate limit resets.
+func parseReset(v string) (time.Time, bool) {
+	s, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || s <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(s, 0), true
+}
+
+// ParseRetryAfter parses a Retry-After header value, either a number of
+// seconds or an HTTP date, returning the duration to wait.
+func ParseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0, false
+		}
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// throttledTransport delays requests according to a Throttler and feeds it
+// the rate limit headers of every response.
+type throttledTransport struct {
+	next      http.RoundTripper
+	throttler *Throttler
+}
+
+// NewThrottledTransport returns an http.RoundTripper that delays requests
+// when the supplied Throttler reports the rate limit is nearly exhausted.
+func NewThrottledTransport(next http.RoundTripper, t *Throttler) http.RoundTripper {
+	return &throttledTransport{next: next, throttler: t}
+}
+
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.throttler.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	t.throttler.Observe(resp)
+	return resp, err
+}
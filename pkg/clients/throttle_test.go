@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestThrottlerObserve(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	cases := map[string]struct {
+		status  int
+		headers map[string]string
+		want    time.Duration
+	}{
+		"PlentyRemaining": {
+			status: http.StatusOK,
+			headers: map[string]string{
+				HeaderRateRemaining: "100",
+				HeaderRateReset:     strconv.FormatInt(now.Add(time.Minute).Unix(), 10),
+			},
+			want: 0,
+		},
+		"NearlyExhausted": {
+			status: http.StatusOK,
+			headers: map[string]string{
+				HeaderRateRemaining: "2",
+				HeaderRateReset:     strconv.FormatInt(now.Add(10*time.Second).Unix(), 10),
+			},
+			want: 10 * time.Second,
+		},
+		"RetryAfterSeconds": {
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{HeaderRetryAfter: "7"},
+			want:    7 * time.Second,
+		},
+		"RetryAfterDate": {
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{HeaderRetryAfter: now.Add(3 * time.Second).UTC().Format(http.TimeFormat)},
+			want:    3 * time.Second,
+		},
+		"TooManyRequestsWithoutHeaders": {
+			status: http.StatusTooManyRequests,
+			want:   time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			th := NewThrottler()
+			th.now = func() time.Time { return now }
+
+			resp := &http.Response{StatusCode: tc.status, Header: http.Header{}}
+			for k, v := range tc.headers {
+				resp.Header.Set(k, v)
+			}
+			th.Observe(resp)
+
+			if diff := cmp.Diff(tc.want, th.Delay()); diff != "" {
+				t.Errorf("Delay(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestThrottlerWait(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	th := NewThrottler()
+	th.now = func() time.Time { return now }
+	th.resume = now.Add(time.Hour)
+
+	if err := th.Wait(context.Background()); err == nil {
+		t.Errorf("Wait(): expected an error when the delay exceeds MaxDelay")
+	}
+
+	th.resume = now
+	if err := th.Wait(context.Background()); err != nil {
+		t.Errorf("Wait(): unexpected error: %s", err)
+	}
+}
+
+func TestThrottlerDeferral(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	reset := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+
+	cases := map[string]struct {
+		headers map[string]string
+		want    time.Duration
+	}{
+		"PlentyRemaining": {
+			headers: map[string]string{HeaderRateLimit: "100", HeaderRateRemaining: "50", HeaderRateReset: reset},
+			want:    0,
+		},
+		"WithinReserve": {
+			headers: map[string]string{HeaderRateLimit: "100", HeaderRateRemaining: "20", HeaderRateReset: reset},
+			want:    time.Minute,
+		},
+		"UnknownLimit": {
+			headers: map[string]string{HeaderRateRemaining: "20", HeaderRateReset: reset},
+			want:    0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			th := NewThrottler()
+			th.now = func() time.Time { return now }
+
+			resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+			for k, v := range tc.headers {
+				resp.Header.Set(k, v)
+			}
+			th.Observe(resp)
+
+			if diff := cmp.Diff(tc.want, th.Deferral()); diff != "" {
+				t.Errorf("Deferral(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestThrottlerWaitLowPriority(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	th := NewThrottler()
+	th.now = func() time.Time { return now }
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set(HeaderRateLimit, "100")
+	resp.Header.Set(HeaderRateRemaining, "10")
+	resp.Header.Set(HeaderRateReset, strconv.FormatInt(now.Add(time.Minute).Unix(), 10))
+	th.Observe(resp)
+
+	if err := th.Wait(context.Background()); err != nil {
+		t.Errorf("Wait(): unexpected error: %s", err)
+	}
+	if err := th.Wait(WithLowPriority(context.Background())); err == nil {
+		t.Errorf("Wait(): expected an error for a low priority request within the reserve")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the OpenTelemetry tracer of the provider.
+const TracerName = "github.com/packethost/crossplane-provider-equinix-metal"
+
+// AttributeDeviceID is the span attribute of the Device an API request is
+// sent for.
+const AttributeDeviceID = attribute.Key("metal.device_id")
+
+var devicePath = regexp.MustCompile(`/devices/([0-9a-fA-F-]{36})(?:/|$)`)
+
+// A TraceParent holds the span of the external client call that the API
+// requests of a connection are currently sent for. The requests of a
+// connection are bound to the context it was connected with, not to the
+// context of each call, so the call spans are passed along separately.
+type TraceParent struct {
+	mu   sync.RWMutex
+	span trace.Span
+}
+
+// Set the span that subsequent API requests are children of.
+func (p *TraceParent) Set(s trace.Span) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.span = s
+}
+
+func (p *TraceParent) get() trace.Span {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.span
+}
+
+type traceParentKey struct{}
+
+// WithTraceParent returns a copy of the supplied context holding the supplied
+// TraceParent.
+func WithTraceParent(ctx context.Context, p *TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, p)
+}
+
+func traceParentFrom(ctx context.Context) *TraceParent {
+	p, _ := ctx.Value(traceParentKey{}).(*TraceParent)
+	return p
+}
+
+// tracingTransport records a span for every API request.
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer trace.Tracer
+}
+
+// NewTracingTransport returns an http.RoundTripper that records a client span
+// for every request, including its retries, with the method, URL and status
+// code of the request and the ID of the Device it is sent for, if any. The
+// span is a child of the TraceParent of the request context if one is set,
+// or of the span of the request context otherwise. Spans are recorded with
+// the global TracerProvider, which discards them unless tracing is enabled.
+func NewTracingTransport(next http.RoundTripper) http.RoundTripper {
+	return &tracingTransport{next: next, tracer: otel.Tracer(TracerName)}
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if p := traceParentFrom(ctx); p != nil {
+		if s := p.get(); s != nil {
+			ctx = trace.ContextWithSpan(ctx, s)
+		}
+	}
+	attrs := semconv.HTTPClientAttributesFromHTTPRequest(req)
+	if m := devicePath.FindStringSubmatch(req.URL.Path); m != nil {
+		attrs = append(attrs, AttributeDeviceID.String(m[1]))
+	}
+	ctx, span := t.tracer.Start(ctx, "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(resp.StatusCode)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(resp.StatusCode))
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+func TestTracingTransport(t *testing.T) {
+	deviceID := "8f3d2a6e-2b5c-4c4e-9d7a-1f0b6f2e3c4d"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	tr := &tracingTransport{next: http.DefaultTransport, tracer: tp.Tracer(TracerName)}
+
+	_, parent := tp.Tracer(TracerName).Start(context.Background(), "Observe Device")
+	p := &TraceParent{}
+	p.Set(parent)
+	req, _ := http.NewRequestWithContext(WithTraceParent(context.Background(), p), http.MethodGet, srv.URL+"/metal/v1/devices/"+deviceID, nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip(): %s", err)
+	}
+	resp.Body.Close()
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("RoundTrip(): want 1 span, got %d", len(spans))
+	}
+	s := spans[0]
+	if diff := cmp.Diff("HTTP GET", s.Name); diff != "" {
+		t.Errorf("RoundTrip(): -want name, +got:\n%s", diff)
+	}
+	if s.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("RoundTrip(): span is not a child of the TraceParent")
+	}
+	attrs := attribute.NewSet(s.Attributes...)
+	if v, _ := attrs.Value(AttributeDeviceID); v.AsString() != deviceID {
+		t.Errorf("RoundTrip(): want %s %q, got %q", AttributeDeviceID, deviceID, v.AsString())
+	}
+	if v, _ := attrs.Value(semconv.HTTPStatusCodeKey); v.AsInt64() != http.StatusNotFound {
+		t.Errorf("RoundTrip(): want %s %d, got %d", semconv.HTTPStatusCodeKey, http.StatusNotFound, v.AsInt64())
+	}
+}
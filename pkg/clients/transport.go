@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseProxy   = "cannot parse proxy URL"
+	errReadCABundle = "cannot read CA bundle"
+	errNoCACerts    = "CA bundle contains no PEM encoded certificates"
+)
+
+// DefaultTimeout is the default time limit of an API call, including its
+// retries and any delay imposed by rate limiting.
+const DefaultTimeout = time.Minute
+
+var (
+	baseTransportMu sync.RWMutex
+	baseTransport   http.RoundTripper = http.DefaultTransport
+	timeout                           = DefaultTimeout
+)
+
+// SetBaseTransport sets the transport that the API clients created afterwards
+// send their requests with. It is http.DefaultTransport by default. Cached
+// HTTP clients are dropped, so that no API client keeps using the previous
+// transport.
+func SetBaseTransport(t http.RoundTripper) {
+	baseTransportMu.Lock()
+	defer baseTransportMu.Unlock()
+	baseTransport = t
+	apiClients.Reset()
+}
+
+// SetTimeout sets the time limit of the calls of the API clients created
+// afterwards. Zero means no limit. Cached HTTP clients are dropped, as they
+// are by SetBaseTransport.
+func SetTimeout(d time.Duration) {
+	baseTransportMu.Lock()
+	defer baseTransportMu.Unlock()
+	timeout = d
+	apiClients.Reset()
+}
+
+func getBaseTransport() (http.RoundTripper, time.Duration) {
+	baseTransportMu.RLock()
+	defer baseTransportMu.RUnlock()
+	return baseTransport, timeout
+}
+
+// NewBaseTransport returns a copy of http.DefaultTransport that sends requests
+// through the supplied proxy, if any, and trusts the CA certificates of the
+// supplied PEM bundle file, if any, in addition to the system's. Without a
+// proxy the HTTPS_PROXY and NO_PROXY environment variables are honored.
+func NewBaseTransport(proxy, caBundle string) (http.RoundTripper, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseProxy)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if caBundle != "" {
+		pem, err := ioutil.ReadFile(caBundle) //nolint:gosec // The path is supplied by the operator.
+		if err != nil {
+			return nil, errors.Wrap(err, errReadCABundle)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New(errNoCACerts)
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return t, nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetBaseTransportResetsClients(t *testing.T) {
+	defer SetBaseTransport(http.DefaultTransport)
+
+	built := 0
+	newFn := func() *http.Client {
+		built++
+		return &http.Client{}
+	}
+	apiClients.Get("reset-test", newFn)
+	apiClients.Get("reset-test", newFn)
+	if built != 1 {
+		t.Fatalf("Get(...): want 1 client built, got %d", built)
+	}
+
+	SetBaseTransport(http.DefaultTransport)
+	apiClients.Get("reset-test", newFn)
+	if built != 2 {
+		t.Errorf("Get(...) after SetBaseTransport(...): want 2 clients built, got %d", built)
+	}
+}
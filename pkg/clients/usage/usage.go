@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// usageTimeFormat is the format of the times filtering usages.
+const usageTimeFormat = "2006-01-02T15:04:05Z"
+
+// A ProjectUsage is the usage of a project that is billed, such as an hour of
+// a Device or a gigabyte of outbound traffic.
+type ProjectUsage struct {
+	Name        string  `json:"name,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Type        string  `json:"type,omitempty"`
+	Plan        string  `json:"plan,omitempty"`
+	Facility    string  `json:"facility,omitempty"`
+	Unit        string  `json:"unit,omitempty"`
+	Price       float64 `json:"price,omitempty"`
+	Total       float64 `json:"total,omitempty"`
+	StartDate   string  `json:"start_date,omitempty"`
+	EndDate     string  `json:"end_date,omitempty"`
+}
+
+type projectUsageRoot struct {
+	Usages []ProjectUsage `json:"usages"`
+}
+
+// Client implements the Equinix Metal API methods needed to observe the usage
+// of projects for the Equinix Metal Crossplane Provider
+type Client interface {
+	List(projectID string, after, before time.Time) ([]ProjectUsage, *packngo.Response, error)
+}
+
+// A requestDoer makes requests to the Equinix Metal API.
+type requestDoer interface {
+	DoRequest(method, path string, body, v interface{}) (*packngo.Response, error)
+}
+
+// build-time test that the interface is implemented
+var _ requestDoer = &packngo.Client{}
+
+// ProjectUsageServiceOp implements Client with the usage API of Equinix
+// Metal, which packngo does not cover.
+type ProjectUsageServiceOp struct {
+	client requestDoer
+}
+
+var _ Client = &ProjectUsageServiceOp{}
+
+// List returns the usages of the supplied project created between after and
+// before.
+func (s *ProjectUsageServiceOp) List(projectID string, after, before time.Time) ([]ProjectUsage, *packngo.Response, error) {
+	q := url.Values{}
+	q.Set("created[after]", after.UTC().Format(usageTimeFormat))
+	q.Set("created[before]", before.UTC().Format(usageTimeFormat))
+	path := fmt.Sprintf("projects/%s/usages?%s", url.PathEscape(projectID), q.Encode())
+
+	root := new(projectUsageRoot)
+	resp, err := s.client.DoRequest("GET", path, nil, root)
+	if err != nil {
+		return nil, resp, err
+	}
+	return root.Usages, resp, nil
+}
+
+// Generate the fake used by controller tests.
+//go:generate go run -tags generate github.com/matryer/moq -out fake/zz_generated.mock.go -pkg fake . ClientWithDefaults:MockClient
+
+// ClientWithDefaults is an interface that provides usage services and
+// provides default values for common properties
+type ClientWithDefaults interface {
+	Client
+	clients.DefaultGetter
+}
+
+// CredentialedClient is a credentialed client to Equinix Metal usage services
+type CredentialedClient struct {
+	Client
+	*clients.Credentials
+}
+
+var _ ClientWithDefaults = &CredentialedClient{}
+
+// NewClient returns a Client implementing the Equinix Metal API methods needed
+// to observe the usage of projects for the Equinix Metal Crossplane Provider
+func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
+	client, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	usageClient := CredentialedClient{
+		Client:      &ProjectUsageServiceOp{client: client.Client},
+		Credentials: client.Credentials,
+	}
+	usageClient.SetProjectID(config.ProjectID)
+	return usageClient, nil
+}
+
+// MonthStart returns the start of the UTC calendar month of the supplied time.
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Total returns the sum of the totals of the supplied usages.
+func Total(usages []ProjectUsage) float64 {
+	total := 0.0
+	for _, u := range usages {
+		total += u.Total
+	}
+	return total
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+)
+
+type doerFn func(method, path string, body, v interface{}) (*packngo.Response, error)
+
+func (fn doerFn) DoRequest(method, path string, body, v interface{}) (*packngo.Response, error) {
+	return fn(method, path, body, v)
+}
+
+func TestList(t *testing.T) {
+	var gotPath string
+	s := &ProjectUsageServiceOp{client: doerFn(func(method, path string, _, v interface{}) (*packngo.Response, error) {
+		gotPath = path
+		v.(*projectUsageRoot).Usages = []ProjectUsage{{Name: "c3.small.x86", Total: 12.5}, {Name: "outbound", Total: 0.25}}
+		return nil, nil
+	})}
+
+	after := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2021, time.June, 15, 12, 0, 0, 0, time.UTC)
+	usages, _, err := s.List("my-project", after, before)
+	if err != nil {
+		t.Fatalf("List(...): unexpected error: %s", err)
+	}
+
+	wantPath := "projects/my-project/usages?created%5Bafter%5D=2021-06-01T00%3A00%3A00Z&created%5Bbefore%5D=2021-06-15T12%3A00%3A00Z"
+	if diff := cmp.Diff(wantPath, gotPath); diff != "" {
+		t.Errorf("List(...): -want path, +got path:\n%s", diff)
+	}
+	if diff := cmp.Diff(12.75, Total(usages)); diff != "" {
+		t.Errorf("Total(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestMonthStart(t *testing.T) {
+	now := time.Date(2021, time.July, 1, 1, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	want := time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)
+	if diff := cmp.Diff(want, MonthStart(now)); diff != "" {
+		t.Errorf("MonthStart(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import "net/http"
+
+// userAgentTransport sets the User-Agent of every request.
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+// NewUserAgentTransport returns an http.RoundTripper that sets the User-Agent
+// header of every request to the supplied value.
+func NewUserAgentTransport(next http.RoundTripper, userAgent string) http.RoundTripper {
+	return &userAgentTransport{next: next, userAgent: userAgent}
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the supplied request.
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+// A FaultyClient wraps a VirtualNetwork client, injecting the Faults of each
+// of its methods into their calls.
+type FaultyClient struct {
+	vlan.ClientWithDefaults
+	Faults *packettest.Faults
+}
+
+var _ vlan.ClientWithDefaults = &FaultyClient{}
+
+// NewFaultyClient returns a FaultyClient wrapping the supplied client.
+func NewFaultyClient(c vlan.ClientWithDefaults, f *packettest.Faults) *FaultyClient {
+	return &FaultyClient{ClientWithDefaults: c, Faults: f}
+}
+
+// List calls List of the wrapped client, injecting its next fault.
+func (c *FaultyClient) List(projectID string, listOpt *packngo.ListOptions) (*packngo.VirtualNetworkListResponse, *packngo.Response, error) {
+	f := c.Faults.Next("List")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	l, resp, err := c.ClientWithDefaults.List(projectID, listOpt)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial && l != nil && len(l.VirtualNetworks) > 1 {
+		p := *l
+		p.VirtualNetworks = p.VirtualNetworks[:1]
+		l = &p
+	}
+	return l, resp, nil
+}
+
+// Create calls Create of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Create(req *packngo.VirtualNetworkCreateRequest) (*packngo.VirtualNetwork, *packngo.Response, error) {
+	f := c.Faults.Next("Create")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	v, resp, err := c.ClientWithDefaults.Create(req)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		v = partialVirtualNetwork(v)
+	}
+	return v, resp, nil
+}
+
+// Get calls Get of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Get(vlanID string, getOpt *packngo.GetOptions) (*packngo.VirtualNetwork, *packngo.Response, error) {
+	f := c.Faults.Next("Get")
+	if f.Err != nil && !f.Lost {
+		return nil, nil, f.Err
+	}
+	v, resp, err := c.ClientWithDefaults.Get(vlanID, getOpt)
+	if err != nil || f.Err != nil {
+		return nil, resp, firstErr(err, f.Err)
+	}
+	if f.Partial {
+		v = partialVirtualNetwork(v)
+	}
+	return v, resp, nil
+}
+
+// Delete calls Delete of the wrapped client, injecting its next fault.
+func (c *FaultyClient) Delete(virtualNetworkID string) (*packngo.Response, error) {
+	f := c.Faults.Next("Delete")
+	if f.Err != nil && !f.Lost {
+		return nil, f.Err
+	}
+	resp, err := c.ClientWithDefaults.Delete(virtualNetworkID)
+	return resp, firstErr(err, f.Err)
+}
+
+// partialVirtualNetwork returns a copy of the supplied virtual network without
+// its nested objects, as returned when they are excluded.
+func partialVirtualNetwork(v *packngo.VirtualNetwork) *packngo.VirtualNetwork {
+	if v == nil {
+		return nil
+	}
+	p := *v
+	p.Project = nil
+	p.Instances = nil
+	p.Facility = nil
+	p.Metro = nil
+	return &p
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -42,6 +42,9 @@ type Client interface {
 // build-time test that the interface is implemented
 var _ Client = (&packngo.Client{}).ProjectVirtualNetworks
 
+// Generate the fake used by controller tests.
+//go:generate go run -tags generate github.com/matryer/moq -out fake/zz_generated.mock.go -pkg fake . ClientWithDefaults:MockClient
+
 // ClientWithDefaults is an interface that provides VirtualNetwork services and
 // provides default values for common properties
 type ClientWithDefaults interface {
@@ -57,6 +60,13 @@ type CredentialedClient struct {
 
 var _ ClientWithDefaults = &CredentialedClient{}
 
+// ObserveOptions returns the options of the Get calls made to observe a
+// VirtualNetwork. They exclude the nested objects the provider does not
+// read, most notably the devices attached to the VirtualNetwork.
+func ObserveOptions() *packngo.GetOptions {
+	return &packngo.GetOptions{Excludes: []string{"assigned_to", "instances", "facility", "metro"}}
+}
+
 // NewClient returns a Client implementing the Equinix Metal API methods needed to
 // interact with VirtualNetworks for the Equinix Metal Crossplane Provider
 func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefaults, error) {
@@ -72,6 +82,21 @@ func NewClient(ctx context.Context, config *clients.Credentials) (ClientWithDefa
 	return vlanClient, nil
 }
 
+// ListAll returns every VirtualNetwork of the supplied project, requesting as
+// many pages as needed.
+func ListAll(c Client, projectID string, opts *packngo.ListOptions) ([]packngo.VirtualNetwork, error) {
+	vlans := []packngo.VirtualNetwork{}
+	err := clients.ListAll(opts, func(o *packngo.ListOptions) (int, error) {
+		page, _, err := c.List(projectID, o)
+		if err != nil {
+			return 0, err
+		}
+		vlans = append(vlans, page.VirtualNetworks...)
+		return len(page.VirtualNetworks), nil
+	})
+	return vlans, err
+}
+
 // CreateFromVirtualNetwork return packngo.VirtualNetworkCreateRequest created from Kubernetes
 func CreateFromVirtualNetwork(d *v1alpha1.VirtualNetwork, projectID string) *packngo.VirtualNetworkCreateRequest {
 	return &packngo.VirtualNetworkCreateRequest{
@@ -0,0 +1,206 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package alias reconciles the managed resources of an alias API group with
+// the external clients of the API group they alias. Managed resources are
+// converted to the aliased kind before every external client call and back
+// after it, so that the external clients only see the kind they were written
+// for.
+package alias
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errNotAlias    = "managed resource is not of an alias kind"
+	errConvertTo   = "cannot convert managed resource to the kind it aliases"
+	errConvertFrom = "cannot convert managed resource from the kind it aliases"
+)
+
+// An Object is a managed resource of an alias kind. It converts to and from
+// the managed resource of the kind it aliases.
+type Object interface {
+	resource.Managed
+	ConvertTo(hub conversion.Hub) error
+	ConvertFrom(hub conversion.Hub) error
+}
+
+// A Hub is a managed resource of an aliased kind.
+type Hub interface {
+	resource.Managed
+	Hub()
+}
+
+// A Kind pairs an alias kind with the kind it aliases.
+type Kind struct {
+	// GroupVersionKind of the alias kind.
+	GroupVersionKind schema.GroupVersionKind
+
+	// New returns a managed resource of the alias kind.
+	New func() Object
+
+	// NewHub returns a managed resource of the aliased kind.
+	NewHub func() Hub
+}
+
+func (k Kind) toHub(mg resource.Managed) (Hub, error) {
+	a, ok := mg.(Object)
+	if !ok {
+		return nil, errors.New(errNotAlias)
+	}
+	h := k.NewHub()
+	// The hub keeps the kind of the alias managed resource, so that the events
+	// and ProviderConfig usages recorded for it refer to the alias.
+	h.GetObjectKind().SetGroupVersionKind(k.GroupVersionKind)
+	return h, errors.Wrap(a.ConvertTo(h), errConvertTo)
+}
+
+// fromHub updates the supplied alias managed resource from the supplied hub,
+// returning err if it is not nil.
+func fromHub(mg resource.Managed, h Hub, err error) error {
+	if cerr := mg.(Object).ConvertFrom(h); cerr != nil && err == nil {
+		return errors.Wrap(cerr, errConvertFrom)
+	}
+	return err
+}
+
+// NewConnecter returns an ExternalConnecter of managed resources of the
+// supplied alias kind, which calls the supplied ExternalConnecter of the kind
+// they alias.
+func NewConnecter(c managed.ExternalConnecter, k Kind) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c, kind: k}
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+	kind Kind
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	h, err := c.kind.toHub(mg)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := c.ExternalConnecter.Connect(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return &external{ExternalClient: ec, kind: c.kind}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+	kind Kind
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	h, err := e.kind.toHub(mg)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	o, err := e.ExternalClient.Observe(ctx, h)
+	return o, fromHub(mg, h, err)
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	h, err := e.kind.toHub(mg)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	c, err := e.ExternalClient.Create(ctx, h)
+	return c, fromHub(mg, h, err)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	h, err := e.kind.toHub(mg)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	u, err := e.ExternalClient.Update(ctx, h)
+	return u, fromHub(mg, h, err)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	h, err := e.kind.toHub(mg)
+	if err != nil {
+		return err
+	}
+	return fromHub(mg, h, e.ExternalClient.Delete(ctx, h))
+}
+
+// NewClient returns a client that gets, updates and deletes managed resources
+// of the kind aliased by the supplied kind as managed resources of the alias
+// kind. The external clients of the aliased kind use it to update the alias
+// managed resources they are called with. All other objects are passed through.
+func NewClient(c client.Client, k Kind) client.Client {
+	return &kube{Client: c, kind: k, hub: reflect.TypeOf(k.NewHub())}
+}
+
+type kube struct {
+	client.Client
+	kind Kind
+	hub  reflect.Type
+}
+
+func (c *kube) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	h, ok := obj.(Hub)
+	if !ok || reflect.TypeOf(obj) != c.hub {
+		return c.Client.Get(ctx, key, obj)
+	}
+	a := c.kind.New()
+	if err := c.Client.Get(ctx, key, a); err != nil {
+		return err
+	}
+	return errors.Wrap(a.ConvertTo(h), errConvertTo)
+}
+
+func (c *kube) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	h, ok := obj.(Hub)
+	if !ok || reflect.TypeOf(obj) != c.hub {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+	a := c.kind.New()
+	if err := a.ConvertFrom(h); err != nil {
+		return errors.Wrap(err, errConvertFrom)
+	}
+	if err := c.Client.Update(ctx, a, opts...); err != nil {
+		return err
+	}
+	return errors.Wrap(a.ConvertTo(h), errConvertTo)
+}
+
+func (c *kube) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	h, ok := obj.(Hub)
+	if !ok || reflect.TypeOf(obj) != c.hub {
+		return c.Client.Delete(ctx, obj, opts...)
+	}
+	a := c.kind.New()
+	if err := a.ConvertFrom(h); err != nil {
+		return errors.Wrap(err, errConvertFrom)
+	}
+	return c.Client.Delete(ctx, a, opts...)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alias
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	emvlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/vlan/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+var errBoom = errors.New("boom")
+
+var kind = Kind{
+	GroupVersionKind: emvlanv1alpha1.VirtualNetworkGroupVersionKind,
+	New:              func() Object { return &emvlanv1alpha1.VirtualNetwork{} },
+	NewHub:           func() Hub { return &v1alpha1.VirtualNetwork{} },
+}
+
+func TestExternal(t *testing.T) {
+	var got *v1alpha1.VirtualNetwork
+	ec := managed.ExternalClientFns{
+		ObserveFn: func(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+			got = mg.(*v1alpha1.VirtualNetwork).DeepCopy()
+			v := mg.(*v1alpha1.VirtualNetwork)
+			meta.SetExternalName(v, "cool-id")
+			v.Status.AtProvider.ID = "cool-id"
+			return managed.ExternalObservation{ResourceExists: true}, errBoom
+		},
+	}
+	c := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		if _, ok := mg.(*v1alpha1.VirtualNetwork); !ok {
+			t.Errorf("Connect(...): want the aliased VirtualNetwork, got %T", mg)
+		}
+		return ec, nil
+	}), kind)
+
+	a := &emvlanv1alpha1.VirtualNetwork{}
+	a.SetName("cool-vlan")
+	a.Spec.ForProvider.VXLAN = 1000
+
+	e, err := c.Connect(context.Background(), a)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+	o, err := e.Observe(context.Background(), a)
+	if diff := cmp.Diff(errBoom, err, test.EquateErrors()); diff != "" {
+		t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+	}
+	if !o.ResourceExists {
+		t.Errorf("Observe(...): want the observation of the aliased external client")
+	}
+	if diff := cmp.Diff(kind.GroupVersionKind, got.GroupVersionKind()); diff != "" {
+		t.Errorf("Observe(...): -want hub kind, +got hub kind:\n%s", diff)
+	}
+	if diff := cmp.Diff(a.Spec, got.Spec); diff != "" {
+		t.Errorf("Observe(...): -want hub spec, +got hub spec:\n%s", diff)
+	}
+	if diff := cmp.Diff("cool-id", meta.GetExternalName(a)); diff != "" {
+		t.Errorf("Observe(...): -want external name, +got external name:\n%s", diff)
+	}
+	if diff := cmp.Diff("cool-id", a.Status.AtProvider.ID); diff != "" {
+		t.Errorf("Observe(...): -want ID, +got ID:\n%s", diff)
+	}
+}
+
+func TestClientUpdate(t *testing.T) {
+	var updated client.Object
+	c := NewClient(&test.MockClient{
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			updated = obj
+			obj.SetResourceVersion("2")
+			return nil
+		},
+	}, kind)
+
+	h := &v1alpha1.VirtualNetwork{}
+	h.SetName("cool-vlan")
+	h.Spec.ForProvider.VXLAN = 1000
+	if err := c.Update(context.Background(), h); err != nil {
+		t.Fatalf("Update(...): %s", err)
+	}
+	a, ok := updated.(*emvlanv1alpha1.VirtualNetwork)
+	if !ok {
+		t.Fatalf("Update(...): want the alias VirtualNetwork to be updated, got %T", updated)
+	}
+	if diff := cmp.Diff(h.Spec, a.Spec); diff != "" {
+		t.Errorf("Update(...): -want spec, +got spec:\n%s", diff)
+	}
+	if diff := cmp.Diff("2", h.GetResourceVersion()); diff != "" {
+		t.Errorf("Update(...): -want resource version, +got resource version:\n%s", diff)
+	}
+
+	s := &corev1.Secret{}
+	if err := c.Update(context.Background(), s); err != nil {
+		t.Fatalf("Update(...): %s", err)
+	}
+	if updated != s {
+		t.Errorf("Update(...): want other objects to be passed through, got %T", updated)
+	}
+}
+
+func TestClientDelete(t *testing.T) {
+	var deleted client.Object
+	c := NewClient(&test.MockClient{
+		MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+			deleted = obj
+			return nil
+		},
+	}, kind)
+
+	h := &v1alpha1.VirtualNetwork{}
+	h.SetName("cool-vlan")
+	if err := c.Delete(context.Background(), h); err != nil {
+		t.Fatalf("Delete(...): %s", err)
+	}
+	a, ok := deleted.(*emvlanv1alpha1.VirtualNetwork)
+	if !ok {
+		t.Fatalf("Delete(...): want the alias VirtualNetwork to be deleted, got %T", deleted)
+	}
+	if diff := cmp.Diff("cool-vlan", a.GetName()); diff != "" {
+		t.Errorf("Delete(...): -want name, +got name:\n%s", diff)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apierror reports why the Equinix Metal API refused the most recent
+// reconcile of a managed resource with an APIError condition, whose reason
+// tells apart failures that call for different reactions, such as fixing the
+// credentials of a ProviderConfig or waiting for capacity.
+package apierror
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// TypeAPIError resources were refused by the Equinix Metal API for a known
+// reason during their most recent reconcile.
+const TypeAPIError xpv1.ConditionType = "APIError"
+
+// Reasons a resource was or was not refused by the Equinix Metal API.
+const (
+	ReasonInvalidCredentials   xpv1.ConditionReason = xpv1.ConditionReason(clients.ErrorClassInvalidCredentials)
+	ReasonInsufficientCapacity xpv1.ConditionReason = xpv1.ConditionReason(clients.ErrorClassInsufficientCapacity)
+	ReasonQuotaExceeded        xpv1.ConditionReason = xpv1.ConditionReason(clients.ErrorClassQuotaExceeded)
+	ReasonRateLimited          xpv1.ConditionReason = xpv1.ConditionReason(clients.ErrorClassRateLimited)
+	ReasonConflict             xpv1.ConditionReason = xpv1.ConditionReason(clients.ErrorClassConflict)
+	ReasonNoAPIError           xpv1.ConditionReason = "NoAPIError"
+)
+
+var messages = map[clients.ErrorClass]string{
+	clients.ErrorClassInvalidCredentials:   "The Equinix Metal API key of the ProviderConfig is invalid or cannot access the project",
+	clients.ErrorClassInsufficientCapacity: "There is no capacity for the requested plan in the requested metro or facility",
+	clients.ErrorClassQuotaExceeded:        "The request exceeds a quota of the Equinix Metal project or organization",
+	clients.ErrorClassRateLimited:          "The Equinix Metal API rate limit was exceeded",
+	clients.ErrorClassConflict:             "The request conflicts with the current state of the external resource",
+}
+
+// APIError returns a condition indicating the resource was refused by the
+// Equinix Metal API with an error of the supplied class.
+func APIError(class clients.ErrorClass) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAPIError,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             xpv1.ConditionReason(class),
+		Message:            messages[class],
+	}
+}
+
+// NoAPIError returns a condition indicating the resource is no longer refused
+// by the Equinix Metal API for a known reason.
+func NoAPIError() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAPIError,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoAPIError,
+	}
+}
+
+// NewConnecter returns an ExternalConnecter that sets the APIError condition
+// of the resources it, or its external clients, fail to reconcile.
+func NewConnecter(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c}
+}
+
+// record sets the APIError condition of mg according to err, and returns err.
+// Resources that were never refused for a known reason get no condition.
+func record(mg resource.Managed, err error) error {
+	class := clients.Classify(err)
+	if class == clients.ErrorClassNone {
+		if mg.GetCondition(TypeAPIError).Status == corev1.ConditionTrue {
+			mg.SetConditions(NoAPIError())
+		}
+		return err
+	}
+	mg.SetConditions(APIError(class))
+	return err
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, record(mg, err)
+	}
+	return &external{ExternalClient: ec}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, record(mg, err)
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, record(mg, err)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, record(mg, err)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	return record(mg, e.ExternalClient.Delete(ctx, mg))
+}
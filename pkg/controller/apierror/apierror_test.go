@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apierror
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+var errBoom = errors.New("boom")
+
+func apiError(status int, msg string) error {
+	return errors.Wrap(&packngo.ErrorResponse{
+		Response: &http.Response{StatusCode: status, Request: &http.Request{}},
+		Errors:   []string{msg},
+	}, "cannot create Device")
+}
+
+func TestConnecter(t *testing.T) {
+	type want struct {
+		status corev1.ConditionStatus
+		reason xpv1.ConditionReason
+	}
+
+	cases := map[string]struct {
+		mg     *fake.Managed
+		create error
+		want   want
+	}{
+		"Succeeded": {
+			mg:   &fake.Managed{},
+			want: want{status: corev1.ConditionUnknown},
+		},
+		"Unclassified": {
+			mg:     &fake.Managed{},
+			create: errBoom,
+			want:   want{status: corev1.ConditionUnknown},
+		},
+		"InvalidCredentials": {
+			mg:     &fake.Managed{},
+			create: apiError(http.StatusUnauthorized, "Invalid authentication token"),
+			want:   want{status: corev1.ConditionTrue, reason: ReasonInvalidCredentials},
+		},
+		"InsufficientCapacity": {
+			mg:     &fake.Managed{},
+			create: apiError(http.StatusServiceUnavailable, "Oh snap, the facility has no capacity"),
+			want:   want{status: corev1.ConditionTrue, reason: ReasonInsufficientCapacity},
+		},
+		"QuotaExceeded": {
+			mg:     &fake.Managed{},
+			create: apiError(http.StatusForbidden, "Project device quota exceeded"),
+			want:   want{status: corev1.ConditionTrue, reason: ReasonQuotaExceeded},
+		},
+		"Conflict": {
+			mg:     &fake.Managed{},
+			create: apiError(http.StatusConflict, "Device is locked"),
+			want:   want{status: corev1.ConditionTrue, reason: ReasonConflict},
+		},
+		"NoLongerRefused": {
+			mg: func() *fake.Managed {
+				mg := &fake.Managed{}
+				mg.SetConditions(APIError(clients.ErrorClassRateLimited))
+				return mg
+			}(),
+			want: want{status: corev1.ConditionFalse, reason: ReasonNoAPIError},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			conn := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return managed.ExternalClientFns{
+					CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+						return managed.ExternalCreation{}, tc.create
+					},
+				}, nil
+			}))
+			ec, _ := conn.Connect(context.Background(), tc.mg)
+			_, err := ec.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.create, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Create(...): -want error, +got error:\n%s", diff)
+			}
+			c := tc.mg.GetCondition(TypeAPIError)
+			if diff := cmp.Diff(tc.want, want{status: c.Status, reason: c.Reason}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("GetCondition(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backoff rate limits the reconciles of resources whose reconcile
+// failed. The delays grow exponentially and are jittered, so that resources
+// that failed together during an Equinix Metal API outage are not all retried
+// at once when it ends.
+package backoff
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Defaults of the backoff, those of controller-runtime.
+const (
+	DefaultBaseDelay = 5 * time.Millisecond
+	DefaultMaxDelay  = 1000 * time.Second
+)
+
+// The overall rate at which resources are retried, regardless of their
+// individual delays. These are the defaults of controller-runtime.
+const (
+	overallRate  = 10
+	overallBurst = 100
+)
+
+// NewRateLimiter returns a rate limiter that retries a resource after a delay
+// of base doubled for each of its consecutive failures, capped at max, and
+// then lengthened by a random fraction of up to jitter. Zero base and max
+// delays default to DefaultBaseDelay and DefaultMaxDelay.
+func NewRateLimiter(base, max time.Duration, jitter float64) workqueue.RateLimiter {
+	if base <= 0 {
+		base = DefaultBaseDelay
+	}
+	if max <= 0 {
+		max = DefaultMaxDelay
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		&exponential{base: base, max: max, jitter: jitter, jitterFn: wait.Jitter, failures: map[interface{}]int{}},
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(overallRate), overallBurst)},
+	)
+}
+
+// An exponential rate limiter with jitter.
+type exponential struct {
+	base     time.Duration
+	max      time.Duration
+	jitter   float64
+	jitterFn func(d time.Duration, maxFactor float64) time.Duration
+
+	mu       sync.Mutex
+	failures map[interface{}]int
+}
+
+// When returns the delay before the supplied item is retried.
+func (e *exponential) When(item interface{}) time.Duration {
+	e.mu.Lock()
+	n := e.failures[item]
+	e.failures[item]++
+	e.mu.Unlock()
+
+	d := e.max
+	if b := float64(e.base) * math.Pow(2, float64(n)); b < float64(e.max) {
+		d = time.Duration(b)
+	}
+	if e.jitter <= 0 {
+		return d
+	}
+	return e.jitterFn(d, e.jitter)
+}
+
+// NumRequeues returns the consecutive failures of the supplied item.
+func (e *exponential) NumRequeues(item interface{}) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.failures[item]
+}
+
+// Forget the failures of the supplied item.
+func (e *exponential) Forget(item interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.failures, item)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExponentialWhen(t *testing.T) {
+	cases := map[string]struct {
+		jitter float64
+		want   []time.Duration
+	}{
+		"NoJitter": {
+			want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		"Jitter": {
+			jitter: 0.5,
+			want:   []time.Duration{1500 * time.Millisecond, 3 * time.Second, 6 * time.Second, 7500 * time.Millisecond, 7500 * time.Millisecond},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &exponential{
+				base:   time.Second,
+				max:    5 * time.Second,
+				jitter: tc.jitter,
+				// Always lengthen by the most jitter.
+				jitterFn: func(d time.Duration, f float64) time.Duration { return d + time.Duration(f*float64(d)) },
+				failures: map[interface{}]int{},
+			}
+			got := make([]time.Duration, 0, len(tc.want))
+			for range tc.want {
+				got = append(got, e.When("cool"))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("When(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(len(tc.want), e.NumRequeues("cool")); diff != "" {
+				t.Errorf("NumRequeues(...): -want, +got:\n%s", diff)
+			}
+
+			e.Forget("cool")
+			if diff := cmp.Diff(time.Second+time.Duration(tc.jitter*float64(time.Second)), e.When("cool")); diff != "" {
+				t.Errorf("When(...): after Forget(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExponentialWhenManyFailures(t *testing.T) {
+	e := &exponential{base: time.Second, max: time.Hour, failures: map[interface{}]int{"cool": 1000}}
+	if diff := cmp.Diff(time.Hour, e.When("cool")); diff != "" {
+		t.Errorf("When(...): -want, +got:\n%s", diff)
+	}
+}
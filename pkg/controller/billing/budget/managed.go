@@ -0,0 +1,185 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package budget
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/billing/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	usageclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/usage"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/reconciler"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new usage client"
+	errNotBudget               = "managed resource is not a Budget"
+	errNoProject               = "neither the Budget nor its ProviderConfig specify a project"
+	errListUsages              = "cannot list project usages"
+	errCreateBudget            = "project does not exist; Budgets only observe the spend of existing projects"
+
+	spendThresholdExceededFmt = "Month-to-date spend of %s USD exceeds the monthly threshold of %d USD"
+)
+
+// SetupBudget adds a controller that compares the spend of Equinix Metal
+// projects with the thresholds of their Budgets.
+func SetupBudget(mgr ctrl.Manager, o options.Options) error {
+	name := managed.ControllerName(v1alpha1.BudgetGroupKind)
+
+	recorder := clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
+
+	c := &connecter{
+		kube:     mgr.GetClient(),
+		usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:      o.Logger.WithValues("controller", name),
+		recorder: recorder,
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Budget{}).
+		WithOptions(o.ControllerOptions()).
+		Complete(reconciler.NewManaged(mgr, o, name, v1alpha1.BudgetGroupVersionKind, c, recorder,
+			managed.WithInitializers(&managed.DefaultProviderConfig{}),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	log         logging.Logger
+	recorder    event.Recorder
+	newClientFn func(ctx context.Context, config *clients.Credentials) (usageclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Budget); !ok {
+		return nil, errors.New(errNotBudget)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := usageclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(clients.WithController(ctx, managed.ControllerName(v1alpha1.BudgetGroupKind)), cfg)
+
+	log := c.log.WithValues("name", mg.GetName(), "uid", mg.GetUID())
+	return &external{client: client, log: log, recorder: c.recorder, now: time.Now}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	client   usageclient.ClientWithDefaults
+	log      logging.Logger
+	recorder event.Recorder
+	now      func() time.Time
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	b, ok := mg.(*v1alpha1.Budget)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotBudget)
+	}
+
+	// A Budget never changes its project, so it is gone as soon as it is
+	// deleted.
+	if meta.WasDeleted(b) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	projectID := ""
+	if b.Spec.ForProvider.ProjectID != nil {
+		projectID = *b.Spec.ForProvider.ProjectID
+	}
+	projectID = e.client.GetProjectID(projectID)
+	if projectID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoProject)
+	}
+
+	now := e.now()
+	start := usageclient.MonthStart(now)
+	usages, _, err := e.client.List(projectID, start, now)
+	if clients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListUsages)
+	}
+
+	spend := usageclient.Total(usages)
+	b.Status.AtProvider = v1alpha1.BudgetObservation{
+		Month:            start.Format("2006-01"),
+		MonthToDateSpend: fmt.Sprintf("%.2f", spend),
+	}
+	meta.SetExternalName(b, projectID)
+
+	threshold := b.Spec.ForProvider.MonthlyThreshold
+	if spend <= float64(threshold) {
+		b.Status.SetConditions(xpv1.Available(), v1alpha1.WithinSpendThreshold())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	// Only the first observation exceeding the threshold is recorded, rather
+	// than every poll of the rest of the month.
+	msg := fmt.Sprintf(spendThresholdExceededFmt, b.Status.AtProvider.MonthToDateSpend, threshold)
+	if b.GetCondition(v1alpha1.TypeWarning).Status != corev1.ConditionTrue {
+		e.recorder.Event(b, event.Warning(clients.ReasonSpendThresholdExceeded, errors.New(msg)))
+	}
+	b.Status.SetConditions(xpv1.Available(), v1alpha1.SpendThresholdExceeded(msg))
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	// NOTE: Budgets only observe projects, which are never created for them.
+	return managed.ExternalCreation{}, errors.New(errCreateBudget)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: Budgets are only observed.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	// NOTE: Deleting a Budget leaves its project in place.
+	return nil
+}
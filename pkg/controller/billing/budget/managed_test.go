@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/billing/v1alpha1"
+	usageclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/usage"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/usage/fake"
+)
+
+const projectID = "6a2c4e8b-1f3d-4b5a-9c7e-0d2f4a6b8c1e"
+
+var errBoom = errors.New("boom")
+
+// eventRecorder records the events it is asked to record.
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *eventRecorder) WithAnnotations(...string) event.Recorder { return r }
+
+type budgetModifier func(*v1alpha1.Budget)
+
+func withExternalName(n string) budgetModifier {
+	return func(b *v1alpha1.Budget) { meta.SetExternalName(b, n) }
+}
+
+func withAtProvider(spend string) budgetModifier {
+	return func(b *v1alpha1.Budget) {
+		b.Status.AtProvider = v1alpha1.BudgetObservation{Month: "2021-06", MonthToDateSpend: spend}
+	}
+}
+
+func withConditions(c ...xpv1.Condition) budgetModifier {
+	return func(b *v1alpha1.Budget) { b.Status.SetConditions(c...) }
+}
+
+func budget(bm ...budgetModifier) *v1alpha1.Budget {
+	b := &v1alpha1.Budget{Spec: v1alpha1.BudgetSpec{ForProvider: v1alpha1.BudgetParameters{MonthlyThreshold: 100}}}
+	for _, m := range bm {
+		m(b)
+	}
+	return b
+}
+
+func TestObserve(t *testing.T) {
+	now := time.Date(2021, time.June, 15, 12, 0, 0, 0, time.UTC)
+	exceeded := v1alpha1.SpendThresholdExceeded("Month-to-date spend of 100.50 USD exceeds the monthly threshold of 100 USD")
+
+	type want struct {
+		mg     *v1alpha1.Budget
+		obs    managed.ExternalObservation
+		events int
+		err    error
+	}
+
+	cases := map[string]struct {
+		mg     *v1alpha1.Budget
+		usages []usageclient.ProjectUsage
+		err    error
+		want
+	}{
+		"WithinThreshold": {
+			mg:     budget(),
+			usages: []usageclient.ProjectUsage{{Total: 60}, {Total: 40}},
+			want: want{
+				mg:  budget(withExternalName(projectID), withAtProvider("100.00"), withConditions(xpv1.Available(), v1alpha1.WithinSpendThreshold())),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"ThresholdExceeded": {
+			mg:     budget(),
+			usages: []usageclient.ProjectUsage{{Total: 60}, {Total: 40.5}},
+			want: want{
+				mg:     budget(withExternalName(projectID), withAtProvider("100.50"), withConditions(xpv1.Available(), exceeded)),
+				obs:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				events: 1,
+			},
+		},
+		"ThresholdStillExceeded": {
+			mg:     budget(withConditions(exceeded)),
+			usages: []usageclient.ProjectUsage{{Total: 100.5}},
+			want: want{
+				mg:  budget(withExternalName(projectID), withAtProvider("100.50"), withConditions(xpv1.Available(), exceeded)),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"ListFailed": {
+			mg:  budget(),
+			err: errBoom,
+			want: want{
+				mg:  budget(),
+				err: errors.Wrap(errBoom, errListUsages),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &eventRecorder{}
+			e := &external{
+				log:      logging.NewNopLogger(),
+				recorder: r,
+				now:      func() time.Time { return now },
+				client: &fake.MockClient{
+					GetProjectIDFunc: func(string) string { return projectID },
+					ListFunc: func(id string, after, before time.Time) ([]usageclient.ProjectUsage, *packngo.Response, error) {
+						if id != projectID || !after.Equal(usageclient.MonthStart(now)) || !before.Equal(now) {
+							t.Errorf("List(%q, %s, %s): unexpected arguments", id, after, before)
+						}
+						return tc.usages, nil, tc.err
+					},
+				},
+			}
+
+			obs, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("Observe(...): -want managed resource, +got managed resource:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, len(r.events)); diff != "" {
+				t.Errorf("Observe(...): -want events, +got events:\n%s", diff)
+			}
+		})
+	}
+}
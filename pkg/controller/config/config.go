@@ -15,6 +15,7 @@ package config
 
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -23,10 +24,12 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 )
 
 // Setup adds a controller that reconciles ProviderConfigs by accounting for
-// their current usage.
+// their current usage, and a UsageSweeper deleting orphaned usages. It also
+// registers the API rate limit gauges of the ProviderConfigs.
 func Setup(mgr ctrl.Manager, l logging.Logger) error {
 	name := providerconfig.ControllerName(v1beta1.ProviderConfigGroupKind)
 
@@ -35,6 +38,14 @@ func Setup(mgr ctrl.Manager, l logging.Logger) error {
 		UsageList: v1beta1.ProviderConfigUsageListGroupVersionKind,
 	}
 
+	if err := clients.RegisterQuotaMetrics(metrics.Registry); err != nil {
+		return err
+	}
+
+	if err := mgr.Add(NewUsageSweeper(mgr.GetClient(), l.WithValues("controller", name), UsageSweepInterval)); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1beta1.ProviderConfig{}).
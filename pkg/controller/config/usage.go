@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+const (
+	// UsageSweepInterval is how often orphaned ProviderConfigUsages are
+	// deleted.
+	UsageSweepInterval = time.Hour
+
+	errListUsages  = "cannot list ProviderConfigUsages"
+	errGetResource = "cannot get resource of ProviderConfigUsage"
+	errDeleteUsage = "cannot delete orphaned ProviderConfigUsage"
+	errSweepFmt    = "cannot sweep ProviderConfigUsage %s"
+)
+
+// A UsageSweeper periodically deletes ProviderConfigUsages whose managed
+// resource no longer exists. Usages are usually garbage collected through
+// their owner reference, but a usage that lost it would otherwise block the
+// deletion of its ProviderConfig forever.
+type UsageSweeper struct {
+	kube     client.Client
+	log      logging.Logger
+	interval time.Duration
+}
+
+// NewUsageSweeper returns a UsageSweeper that sweeps every interval.
+func NewUsageSweeper(c client.Client, l logging.Logger, interval time.Duration) *UsageSweeper {
+	return &UsageSweeper{kube: c, log: l, interval: interval}
+}
+
+// Start sweeps until the supplied context is done.
+func (s *UsageSweeper) Start(ctx context.Context) error {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		if err := s.Sweep(ctx); err != nil {
+			s.log.Info("Cannot sweep orphaned ProviderConfigUsages", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Sweep deletes all orphaned ProviderConfigUsages once. A usage that cannot
+// be swept does not stop the others from being swept; the errors of all of
+// them are returned together.
+func (s *UsageSweeper) Sweep(ctx context.Context) error {
+	l := &v1beta1.ProviderConfigUsageList{}
+	if err := s.kube.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListUsages)
+	}
+	errs := []error{}
+	for i := range l.Items {
+		pcu := &l.Items[i]
+		if err := s.sweep(ctx, pcu); err != nil {
+			s.log.Debug("Cannot sweep ProviderConfigUsage", "name", pcu.GetName(), "error", err)
+			errs = append(errs, errors.Wrapf(err, errSweepFmt, pcu.GetName()))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// sweep deletes the supplied usage if it is orphaned.
+func (s *UsageSweeper) sweep(ctx context.Context, pcu *v1beta1.ProviderConfigUsage) error {
+	orphaned, err := s.orphaned(ctx, pcu)
+	if err != nil || !orphaned {
+		return err
+	}
+	s.log.Debug("Deleting orphaned ProviderConfigUsage", "name", pcu.GetName(), "kind", pcu.ResourceReference.Kind, "resource", pcu.ResourceReference.Name)
+	return errors.Wrap(resource.IgnoreNotFound(s.kube.Delete(ctx, pcu)), errDeleteUsage)
+}
+
+// orphaned returns true if the managed resource of the supplied usage does
+// not exist. Usages are named after the UID of their managed resource, so a
+// resource that was recreated with the same name does not own the usage.
+func (s *UsageSweeper) orphaned(ctx context.Context, pcu *v1beta1.ProviderConfigUsage) (bool, error) {
+	ref := pcu.ResourceReference
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false, errors.Wrap(err, errGetResource)
+	}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gv.WithKind(ref.Kind))
+	err = s.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, u)
+	if resource.IgnoreNotFound(err) != nil {
+		return false, errors.Wrap(err, errGetResource)
+	}
+	return err != nil || string(u.GetUID()) != pcu.GetName(), nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+func usage(uid, device string) *v1beta1.ProviderConfigUsage {
+	return &v1beta1.ProviderConfigUsage{
+		ObjectMeta: metav1.ObjectMeta{Name: uid},
+		ProviderConfigUsage: xpv1.ProviderConfigUsage{
+			ProviderConfigReference: xpv1.Reference{Name: "default"},
+			ResourceReference: xpv1.TypedReference{
+				APIVersion: v1alpha2.SchemeGroupVersion.String(),
+				Kind:       v1alpha2.DeviceKind,
+				Name:       device,
+			},
+		},
+	}
+}
+
+func TestUsageSweeperSweep(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&v1alpha2.Device{ObjectMeta: metav1.ObjectMeta{Name: "live", UID: types.UID("live-uid")}},
+		&v1alpha2.Device{ObjectMeta: metav1.ObjectMeta{Name: "recreated", UID: types.UID("new-uid")}},
+		usage("live-uid", "live"),
+		usage("old-uid", "recreated"),
+		usage("gone-uid", "gone"),
+	).Build()
+
+	if err := NewUsageSweeper(kube, logging.NewNopLogger(), UsageSweepInterval).Sweep(context.Background()); err != nil {
+		t.Fatalf("Sweep(...): %s", err)
+	}
+
+	l := &v1beta1.ProviderConfigUsageList{}
+	if err := kube.List(context.Background(), l); err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, pcu := range l.Items {
+		got = append(got, pcu.GetName())
+	}
+	if diff := cmp.Diff([]string{"live-uid"}, got); diff != "" {
+		t.Errorf("Sweep(...): -want remaining usages, +got:\n%s", diff)
+	}
+}
+
+func TestUsageSweeperSweepContinuesOnError(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	// The resource of a usage with an invalid API version cannot be got.
+	invalid := usage("a-invalid-uid", "invalid")
+	invalid.ResourceReference.APIVersion = "not/a/version"
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		invalid,
+		usage("gone-uid", "gone"),
+	).Build()
+
+	if err := NewUsageSweeper(kube, logging.NewNopLogger(), UsageSweepInterval).Sweep(context.Background()); err == nil {
+		t.Error("Sweep(...): want error, got nil")
+	}
+
+	l := &v1beta1.ProviderConfigUsageList{}
+	if err := kube.List(context.Background(), l); err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, pcu := range l.Items {
+		got = append(got, pcu.GetName())
+	}
+	if diff := cmp.Diff([]string{"a-invalid-uid"}, got); diff != "" {
+		t.Errorf("Sweep(...): -want remaining usages, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package connection controls what happens to the connection secret of a
+// managed resource when the managed resource is deleted. Connection secrets
+// are owned by their managed resource, so Kubernetes deletes them with it
+// unless they are retained.
+package connection
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errGetSecret    = "cannot get connection secret"
+	errUpdateSecret = "cannot update connection secret"
+)
+
+// A Policy determines whether the connection secret of a managed resource is
+// deleted or retained when the managed resource is deleted.
+type Policy string
+
+// Connection secret policies.
+const (
+	// PolicyDelete deletes connection secrets with their managed resource.
+	PolicyDelete Policy = "Delete"
+
+	// PolicyRetain retains connection secrets after their managed resource
+	// is deleted.
+	PolicyRetain Policy = "Retain"
+
+	// PolicyRetainOrphaned retains the connection secrets of managed
+	// resources whose deletion policy is Orphan, and deletes the others.
+	PolicyRetainOrphaned Policy = "RetainOrphaned"
+)
+
+// Policies are the supported connection secret policies.
+func Policies() []string {
+	return []string{string(PolicyDelete), string(PolicyRetain), string(PolicyRetainOrphaned)}
+}
+
+// Retains returns true if the connection secret of the supplied managed
+// resource is retained when it is deleted.
+func (p Policy) Retains(mg resource.Managed) bool {
+	switch p {
+	case PolicyRetain:
+		return true
+	case PolicyRetainOrphaned:
+		return mg.GetDeletionPolicy() == xpv1.DeletionOrphan
+	}
+	return false
+}
+
+// NewPublisher returns a ConnectionPublisher that publishes connection details
+// with the supplied publisher, and that retains the connection secrets of
+// deleted managed resources according to the supplied policy. Retained secrets
+// are no longer owned by their managed resource.
+func NewPublisher(kube client.Client, p managed.ConnectionPublisher, policy Policy) managed.ConnectionPublisher {
+	return &publisher{ConnectionPublisher: p, kube: kube, policy: policy}
+}
+
+type publisher struct {
+	managed.ConnectionPublisher
+	kube   client.Client
+	policy Policy
+}
+
+func (p *publisher) UnpublishConnection(ctx context.Context, mg resource.Managed, c managed.ConnectionDetails) error {
+	ref := mg.GetWriteConnectionSecretToReference()
+	if ref == nil || !p.policy.Retains(mg) {
+		return p.ConnectionPublisher.UnpublishConnection(ctx, mg, c)
+	}
+
+	s := &corev1.Secret{}
+	if err := p.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return errors.Wrap(resource.Ignore(kerrors.IsNotFound, err), errGetSecret)
+	}
+	refs := []metav1.OwnerReference{}
+	for _, o := range s.GetOwnerReferences() {
+		if o.UID != mg.GetUID() {
+			refs = append(refs, o)
+		}
+	}
+	if len(refs) == len(s.GetOwnerReferences()) {
+		return nil
+	}
+	s.SetOwnerReferences(refs)
+	return errors.Wrap(p.kube.Update(ctx, s), errUpdateSecret)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestUnpublishConnection(t *testing.T) {
+	const uid = types.UID("managed-uid")
+	other := metav1.OwnerReference{UID: "other-uid"}
+
+	managedWith := func(p xpv1.DeletionPolicy) resource.Managed {
+		mg := &fake.Managed{}
+		mg.SetUID(uid)
+		mg.SetDeletionPolicy(p)
+		mg.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Namespace: "ns", Name: "conn"})
+		return mg
+	}
+
+	type want struct {
+		owners      []metav1.OwnerReference
+		unpublished bool
+	}
+
+	cases := map[string]struct {
+		policy Policy
+		mg     resource.Managed
+		want   want
+	}{
+		"Delete": {
+			policy: PolicyDelete,
+			mg:     managedWith(xpv1.DeletionOrphan),
+			want:   want{unpublished: true},
+		},
+		"Retain": {
+			policy: PolicyRetain,
+			mg:     managedWith(xpv1.DeletionDelete),
+			want:   want{owners: []metav1.OwnerReference{other}},
+		},
+		"RetainOrphaned": {
+			policy: PolicyRetainOrphaned,
+			mg:     managedWith(xpv1.DeletionOrphan),
+			want:   want{owners: []metav1.OwnerReference{other}},
+		},
+		"DeleteNotOrphaned": {
+			policy: PolicyRetainOrphaned,
+			mg:     managedWith(xpv1.DeletionDelete),
+			want:   want{unpublished: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var owners []metav1.OwnerReference
+			unpublished := false
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					obj.(*corev1.Secret).SetOwnerReferences([]metav1.OwnerReference{{UID: uid}, other})
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					owners = obj.GetOwnerReferences()
+					return nil
+				},
+			}
+			p := NewPublisher(kube, managed.ConnectionPublisherFns{
+				UnpublishConnectionFn: func(_ context.Context, _ resource.Managed, _ managed.ConnectionDetails) error {
+					unpublished = true
+					return nil
+				},
+			}, tc.policy)
+
+			if err := p.UnpublishConnection(context.Background(), tc.mg, nil); err != nil {
+				t.Fatalf("UnpublishConnection(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want, want{owners: owners, unpublished: unpublished}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("UnpublishConnection(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
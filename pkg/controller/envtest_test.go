@@ -0,0 +1,305 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/features"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+const (
+	envtestNamespace = "crossplane-system"
+	envtestTimeout   = 30 * time.Second
+)
+
+// eventually polls the supplied condition until it is met, failing the test
+// if it is not met in time.
+func eventually(t *testing.T, what string, condition func() (bool, error)) {
+	t.Helper()
+	if err := wait.PollImmediate(100*time.Millisecond, envtestTimeout, condition); err != nil {
+		t.Fatalf("%s: %s", what, err)
+	}
+}
+
+// TestControllersEnvtest runs the controllers of the provider against an API
+// server with the CRDs of the provider installed, and against a fake Equinix
+// Metal API. It covers what unit tests of the external clients cannot: the
+// watches that trigger reconciles, the finalizers of managed resources and
+// ProviderConfigs, ProviderConfig usage tracking, connection secret
+// publication, deletion, and the deletion of VirtualNetworks waiting for the
+// Assignments that use them. Like TestExamplesEnvtest it requires the API
+// server and etcd binaries of envtest, found through KUBEBUILDER_ASSETS.
+func TestControllersEnvtest(t *testing.T) { //nolint:gocyclo
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("envtest requires KUBEBUILDER_ASSETS")
+	}
+
+	srv := packettest.NewMetalServer()
+	defer srv.Close()
+	defer srv.Install()()
+
+	s := exampleScheme(t)
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{crdsDir},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("cannot start envtest: %s", err)
+	}
+	defer func() { _ = env.Stop() }()
+
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creds, err := json.Marshal(srv.Credentials())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: envtestNamespace}}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: envtestNamespace, Name: "metal-credentials"},
+		Data:       map[string][]byte{"credentials": creds},
+	}
+	pc := &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "metal"},
+		Spec: v1beta1.ProviderConfigSpec{
+			Credentials: v1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: envtestNamespace, Name: sec.GetName()},
+						Key:             "credentials",
+					},
+				},
+			},
+		},
+	}
+	for _, o := range []client.Object{ns, sec, pc} {
+		if err := kube.Create(ctx, o); err != nil {
+			t.Fatalf("cannot create %s: %s", o.GetName(), err)
+		}
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: s, MetricsBindAddress: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := options.Options{
+		Logger:                  logging.NewNopLogger(),
+		Features:                &features.Flags{},
+		PollInterval:            time.Second,
+		MaxConcurrentReconciles: 1,
+	}
+	if err := Setup(mgr, o); err != nil {
+		t.Fatalf("Setup(...): %s", err)
+	}
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("Start(...): %s", err)
+		}
+	}()
+
+	hostname := "envtest"
+	d := &v1alpha2.Device{
+		ObjectMeta: metav1.ObjectMeta{Name: "envtest"},
+		Spec: v1alpha2.DeviceSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference:          &xpv1.Reference{Name: pc.GetName()},
+				WriteConnectionSecretToReference: &xpv1.SecretReference{Namespace: envtestNamespace, Name: "envtest-device"},
+			},
+			ForProvider: v1alpha2.DeviceParameters{
+				Hostname: &hostname,
+				Plan:     "c3.small.x86",
+				Metro:    "sv",
+				OS:       "ubuntu_20_04",
+			},
+		},
+	}
+	if err := kube.Create(ctx, d); err != nil {
+		t.Fatalf("cannot create Device: %s", err)
+	}
+	key := types.NamespacedName{Name: d.GetName()}
+
+	eventually(t, "Device did not become ready", func() (bool, error) {
+		if err := kube.Get(ctx, key, d); err != nil {
+			return false, err
+		}
+		return meta.FinalizerExists(d, "finalizer.managedresource.crossplane.io") &&
+			meta.GetExternalName(d) != d.GetName() &&
+			d.Status.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue, nil
+	})
+	id := meta.GetExternalName(d)
+	if _, ok := srv.Device(id); !ok {
+		t.Fatalf("Device %s was not created in Equinix Metal", id)
+	}
+
+	eventually(t, "connection secret was not published", func() (bool, error) {
+		cs := &corev1.Secret{}
+		err := kube.Get(ctx, types.NamespacedName{Namespace: envtestNamespace, Name: "envtest-device"}, cs)
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil && metav1.IsControlledBy(cs, d), err
+	})
+
+	eventually(t, "ProviderConfig usage was not tracked", func() (bool, error) {
+		if err := kube.Get(ctx, types.NamespacedName{Name: pc.GetName()}, pc); err != nil {
+			return false, err
+		}
+		return pc.Status.Users > 0 && len(pc.GetFinalizers()) > 0, nil
+	})
+
+	// Edits of the Device trigger a reconcile.
+	if err := kube.Get(ctx, key, d); err != nil {
+		t.Fatalf("cannot get Device: %s", err)
+	}
+	desc := "edited"
+	d.Spec.ForProvider.Description = &desc
+	if err := kube.Update(ctx, d); err != nil {
+		t.Fatalf("cannot update Device: %s", err)
+	}
+	eventually(t, "Device edit was not synced", func() (bool, error) {
+		if err := kube.Get(ctx, key, d); err != nil {
+			return false, err
+		}
+		got, _ := srv.Device(id)
+		return d.Status.ObservedGeneration == d.GetGeneration() && got.Description != nil && *got.Description == desc, nil
+	})
+
+	// Devices of the alias API group are reconciled by the same controllers.
+	ad := &emserverv1beta1.Device{ObjectMeta: metav1.ObjectMeta{Name: "envtest-alias"}}
+	ad.Spec.ProviderConfigReference = &xpv1.Reference{Name: pc.GetName()}
+	ad.Spec.ForProvider.Plan = "c3.small.x86"
+	ad.Spec.ForProvider.Metro = "sv"
+	ad.Spec.ForProvider.OS = "ubuntu_20_04"
+	if err := kube.Create(ctx, ad); err != nil {
+		t.Fatalf("cannot create alias Device: %s", err)
+	}
+	eventually(t, "alias Device did not become ready", func() (bool, error) {
+		if err := kube.Get(ctx, types.NamespacedName{Name: ad.GetName()}, ad); err != nil {
+			return false, err
+		}
+		return ad.Status.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue, nil
+	})
+
+	// A VirtualNetwork is not deleted while an Assignment uses it. Its
+	// controller finds the Assignment through the index of Assignments by
+	// VirtualNetwork, and the deletion of the Assignment triggers a reconcile
+	// of the VirtualNetwork through the watch of Assignments.
+	vn := &vlanv1alpha1.VirtualNetwork{ObjectMeta: metav1.ObjectMeta{Name: "envtest"}}
+	vn.Spec.ProviderConfigReference = &xpv1.Reference{Name: pc.GetName()}
+	vn.Spec.ForProvider.Metro = "sv"
+	if err := kube.Create(ctx, vn); err != nil {
+		t.Fatalf("cannot create VirtualNetwork: %s", err)
+	}
+	vnKey := types.NamespacedName{Name: vn.GetName()}
+	eventually(t, "VirtualNetwork did not become ready", func() (bool, error) {
+		if err := kube.Get(ctx, vnKey, vn); err != nil {
+			return false, err
+		}
+		return meta.GetExternalName(vn) != vn.GetName() &&
+			vn.Status.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue, nil
+	})
+	vnID := meta.GetExternalName(vn)
+
+	// The Assignment uses a ProviderConfig that does not exist, so it is
+	// never connected to Equinix Metal and never gets a finalizer.
+	a := &portsv1alpha1.Assignment{ObjectMeta: metav1.ObjectMeta{Name: "envtest"}}
+	a.Spec.ProviderConfigReference = &xpv1.Reference{Name: "envtest-missing"}
+	a.Spec.ForProvider.DeviceID = id
+	a.Spec.ForProvider.Name = "bond0"
+	a.Spec.ForProvider.VirtualNetworkIDRef = &xpv1.Reference{Name: vn.GetName()}
+	if err := kube.Create(ctx, a); err != nil {
+		t.Fatalf("cannot create Assignment: %s", err)
+	}
+	// Once the Assignment was reconciled it is in the cache of the manager.
+	eventually(t, "Assignment was not reconciled", func() (bool, error) {
+		if err := kube.Get(ctx, types.NamespacedName{Name: a.GetName()}, a); err != nil {
+			return false, err
+		}
+		return a.Status.GetCondition(xpv1.TypeSynced).Reason == xpv1.ReasonReconcileError, nil
+	})
+	if err := kube.Delete(ctx, vn); err != nil {
+		t.Fatalf("cannot delete VirtualNetwork: %s", err)
+	}
+	eventually(t, "VirtualNetwork deletion was not blocked", func() (bool, error) {
+		if err := kube.Get(ctx, vnKey, vn); err != nil {
+			return false, err
+		}
+		return vn.Status.GetCondition(xpv1.TypeSynced).Reason == xpv1.ReasonReconcileError, nil
+	})
+	if _, ok := srv.VirtualNetwork(vnID); !ok {
+		t.Fatalf("VirtualNetwork %s was deleted from Equinix Metal while assigned", vnID)
+	}
+	if err := kube.Delete(ctx, a); err != nil {
+		t.Fatalf("cannot delete Assignment: %s", err)
+	}
+	eventually(t, "VirtualNetwork was not deleted", func() (bool, error) {
+		err := kube.Get(ctx, vnKey, vn)
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if _, ok := srv.VirtualNetwork(vnID); ok {
+		t.Errorf("VirtualNetwork %s was not deleted from Equinix Metal", vnID)
+	}
+
+	if err := kube.Delete(ctx, d); err != nil {
+		t.Fatalf("cannot delete Device: %s", err)
+	}
+	eventually(t, "Device was not deleted", func() (bool, error) {
+		err := kube.Get(ctx, key, d)
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if _, ok := srv.Device(id); ok {
+		t.Errorf("Device %s was not deleted from Equinix Metal", id)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/features"
+)
+
+const (
+	examplesDir = "../../cluster/examples"
+	crdsDir     = "../../package/crds"
+)
+
+// placeholders are substituted in the example manifests by the scripts that
+// apply them, such as provider.sh.
+var placeholders = map[string]string{
+	"BASE64ENCODED_METAL_PROVIDER_CREDS": base64.StdEncoding.EncodeToString([]byte(`{"apiKey":"example","projectID":"example"}`)),
+}
+
+func exampleScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// examples strictly decodes every object of the example manifests, failing
+// the test for objects of unknown kinds or with unknown fields.
+func examples(t *testing.T, s *runtime.Scheme) map[string][]client.Object {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(examplesDir, "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no example manifests found in %s", examplesDir)
+	}
+
+	decoder := json.NewSerializerWithOptions(json.DefaultMetaFactory, s, s, json.SerializerOptions{Yaml: true, Strict: true})
+	objs := map[string][]client.Object{}
+	for _, f := range files {
+		r, err := os.Open(filepath.Clean(f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs := kyaml.NewYAMLReader(bufio.NewReader(r))
+		for {
+			doc, err := docs.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %s", f, err)
+			}
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+			for placeholder, v := range placeholders {
+				doc = bytes.ReplaceAll(doc, []byte(placeholder), []byte(v))
+			}
+			o, _, err := decoder.Decode(doc, nil, nil)
+			if err != nil {
+				t.Errorf("%s: %s", f, err)
+				continue
+			}
+			objs[f] = append(objs[f], o.(client.Object))
+		}
+		_ = r.Close()
+	}
+	return objs
+}
+
+// TestExamples validates the example manifests against the API types of the
+// provider.
+func TestExamples(t *testing.T) {
+	examples(t, exampleScheme(t))
+}
+
+// TestExamplesEnvtest applies the example manifests to an API server with the
+// CRDs of the provider installed, and sets up all controllers against it. It
+// requires the API server and etcd binaries of envtest, found through
+// KUBEBUILDER_ASSETS.
+func TestExamplesEnvtest(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("envtest requires KUBEBUILDER_ASSETS")
+	}
+	s := exampleScheme(t)
+	objs := examples(t, s)
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{crdsDir},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("cannot start envtest: %s", err)
+	}
+	defer func() { _ = env.Stop() }()
+
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, ns := range []string{"crossplane-system"} {
+		o := &corev1.Namespace{}
+		o.SetName(ns)
+		if err := kube.Create(ctx, o); err != nil && !kerrors.IsAlreadyExists(err) {
+			t.Fatal(err)
+		}
+	}
+	for f, fobjs := range objs {
+		for _, o := range fobjs {
+			if err := kube.Create(ctx, o); err != nil && !kerrors.IsAlreadyExists(err) {
+				t.Errorf("%s: cannot create %s %q: %s", f, o.GetObjectKind().GroupVersionKind().Kind, o.GetName(), err)
+			}
+		}
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: s, MetricsBindAddress: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := options.Options{Logger: logging.NewNopLogger(), Features: &features.Flags{}}
+	if err := Setup(mgr, o); err != nil {
+		t.Errorf("Setup(...): %s", err)
+	}
+}
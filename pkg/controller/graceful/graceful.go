@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package graceful lets the reconciles in flight when the provider is asked to
+// stop finish within a grace period, rather than abandoning them. A reconcile
+// abandoned between creating an external resource and persisting its external
+// name leaves behind an external resource the provider no longer knows of.
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// A Drainer tracks the reconciles in flight. Once the manager it was added to
+// stops it refuses new reconciles, and waits up to its grace period for those
+// in flight to finish. Reconciles in flight are cancelled only once the grace
+// period elapsed, rather than when the manager stops.
+type Drainer struct {
+	grace time.Duration
+	log   logging.Logger
+
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+
+	// ctx is cancelled once the grace period elapsed.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewDrainer returns a Drainer with the supplied grace period.
+func NewDrainer(grace time.Duration, l logging.Logger) *Drainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Drainer{grace: grace, log: l, ctx: ctx, cancel: cancel}
+}
+
+// Start waits until the supplied context is done, then drains the reconciles
+// in flight. It returns once they finished or the grace period elapsed.
+func (d *Drainer) Start(ctx context.Context) error {
+	<-ctx.Done()
+
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+
+	t := time.NewTimer(d.grace)
+	defer t.Stop()
+	select {
+	case <-done:
+		d.log.Debug("Reconciles in flight finished")
+	case <-t.C:
+		d.log.Info("Cancelling reconciles in flight at the end of the shutdown grace period", "grace-period", d.grace.String())
+	}
+	d.cancel()
+	return nil
+}
+
+// NewReconciler returns a Reconciler that reconciles with the supplied
+// Reconciler until the Drainer starts draining. A nil Drainer returns the
+// supplied Reconciler.
+func (d *Drainer) NewReconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	if d == nil {
+		return r
+	}
+	return &reconciler{Reconciler: r, drainer: d}
+}
+
+// begin records the start of a reconcile, unless the Drainer is draining.
+func (d *Drainer) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inflight.Add(1)
+	return true
+}
+
+type reconciler struct {
+	reconcile.Reconciler
+	drainer *Drainer
+}
+
+// Reconcile the supplied request with a context that carries the values of
+// the supplied context, but is cancelled only once the grace period of a
+// draining Drainer elapsed. Requests are not reconciled once the Drainer is
+// draining; they are reconciled again when the provider starts anew.
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if !r.drainer.begin() {
+		return reconcile.Result{}, nil
+	}
+	defer r.drainer.inflight.Done()
+	return r.Reconciler.Reconcile(detached{Context: r.drainer.ctx, values: ctx}, req)
+}
+
+// A detached context carries the values of one context, but the deadline and
+// cancellation of another.
+type detached struct {
+	context.Context
+	values context.Context
+}
+
+func (c detached) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
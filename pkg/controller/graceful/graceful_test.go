@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+type key struct{}
+
+type reconcileFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func TestDrainer(t *testing.T) {
+	cases := map[string]struct {
+		grace     time.Duration
+		finish    bool
+		wantErr   error
+		wantValue interface{}
+	}{
+		"InFlightReconcileFinishes": {
+			grace:     time.Minute,
+			finish:    true,
+			wantValue: "cool",
+		},
+		"InFlightReconcileCancelledAfterGracePeriod": {
+			grace:     10 * time.Millisecond,
+			wantErr:   context.Canceled,
+			wantValue: "cool",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewDrainer(tc.grace, logging.NewNopLogger())
+			mgrCtx, stop := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			go func() {
+				_ = d.Start(mgrCtx)
+				close(stopped)
+			}()
+
+			started, finish := make(chan struct{}), make(chan struct{})
+			var gotErr error
+			var gotValue interface{}
+			reconciled := make(chan struct{})
+			r := d.NewReconciler(reconcileFn(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				close(started)
+				select {
+				case <-finish:
+				case <-ctx.Done():
+				}
+				gotErr, gotValue = ctx.Err(), ctx.Value(key{})
+				return reconcile.Result{}, nil
+			}))
+
+			// The reconcile's context is cancelled along with the manager's.
+			reqCtx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "cool"))
+			go func() {
+				_, _ = r.Reconcile(reqCtx, reconcile.Request{})
+				close(reconciled)
+			}()
+			<-started
+			stop()
+			cancel()
+
+			// Wait for the Drainer to start draining.
+			for {
+				d.mu.Lock()
+				draining := d.draining
+				d.mu.Unlock()
+				if draining {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+			refused := true
+			if _, err := d.NewReconciler(reconcileFn(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				refused = false
+				return reconcile.Result{}, nil
+			})).Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Errorf("Reconcile(...): %s", err)
+			}
+			if !refused {
+				t.Errorf("Reconcile(...): want new reconciles to be refused while draining")
+			}
+
+			if tc.finish {
+				close(finish)
+			}
+			<-reconciled
+			<-stopped
+			if gotErr != tc.wantErr {
+				t.Errorf("Reconcile(...): want context error %v, got %v", tc.wantErr, gotErr)
+			}
+			if gotValue != tc.wantValue {
+				t.Errorf("Reconcile(...): want context value %v, got %v", tc.wantValue, gotValue)
+			}
+		})
+	}
+}
+
+func TestNilDrainer(t *testing.T) {
+	r := reconcileFn(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{}, nil
+	})
+	var d *Drainer
+	if _, ok := d.NewReconciler(r).(reconcileFn); !ok {
+		t.Errorf("NewReconciler(...): want a nil Drainer to return the supplied Reconciler")
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package managementpolicy restricts which operations an external client may
+// perform on a managed resource. The policies are read from the
+// metal.equinix.com/management-policies annotation, a comma separated list of
+// Observe, Create, Update, Delete or "*". Observe is always allowed.
+package managementpolicy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// AnnotationKeyManagementPolicies is the annotation used to restrict the
+// operations performed on a managed resource.
+const AnnotationKeyManagementPolicies = "metal.equinix.com/management-policies"
+
+// Error strings.
+const (
+	errUnknownPolicyFmt = "unknown management policy %q"
+	errCreateNotAllowed = "external resource does not exist and the management policies do not allow Create"
+)
+
+// A Policy is an operation the provider may perform on an external resource.
+type Policy string
+
+// Supported management policies.
+const (
+	PolicyObserve Policy = "Observe"
+	PolicyCreate  Policy = "Create"
+	PolicyUpdate  Policy = "Update"
+	PolicyDelete  Policy = "Delete"
+	PolicyAll     Policy = "*"
+)
+
+// ObserveOnly is the annotation value that prevents any mutation of the
+// external resource.
+const ObserveOnly = string(PolicyObserve)
+
+// Policies is the set of operations allowed on a managed resource.
+type Policies map[Policy]bool
+
+// Allows returns true if the supplied operation is allowed.
+func (p Policies) Allows(op Policy) bool {
+	return op == PolicyObserve || p[PolicyAll] || p[op]
+}
+
+// All returns true if every operation is allowed.
+func (p Policies) All() bool {
+	return p.Allows(PolicyCreate) && p.Allows(PolicyUpdate) && p.Allows(PolicyDelete)
+}
+
+// Get returns the management policies of the supplied managed resource. All
+// operations are allowed when the annotation is not set.
+func Get(mg resource.Managed) (Policies, error) {
+	v, ok := mg.GetAnnotations()[AnnotationKeyManagementPolicies]
+	if !ok {
+		return Policies{PolicyAll: true}, nil
+	}
+	p := Policies{}
+	for _, s := range strings.Split(v, ",") {
+		op := Policy(strings.TrimSpace(s))
+		switch op {
+		case "":
+		case PolicyObserve, PolicyCreate, PolicyUpdate, PolicyDelete, PolicyAll:
+			p[op] = true
+		default:
+			return nil, errors.Errorf(errUnknownPolicyFmt, op)
+		}
+	}
+	return p, nil
+}
+
+// NewConnecter returns an ExternalConnecter whose external clients honor the
+// management policies of the managed resource they are connected for.
+func NewConnecter(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c}
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	p, err := Get(mg)
+	if err != nil {
+		return nil, err
+	}
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil || p.All() {
+		return ec, err
+	}
+	return &external{ExternalClient: ec, policies: p}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+	policies Policies
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+	// A managed resource whose external resource may not be deleted is
+	// orphaned, so it must be reported gone for the managed reconciler to
+	// remove its finalizer.
+	if meta.WasDeleted(mg) && !e.policies.Allows(PolicyDelete) {
+		o.ResourceExists = false
+		return o, nil
+	}
+	if !o.ResourceExists && !e.policies.Allows(PolicyCreate) && !meta.WasDeleted(mg) {
+		return o, errors.New(errCreateNotAllowed)
+	}
+	if o.ResourceExists && !e.policies.Allows(PolicyUpdate) {
+		o.ResourceUpToDate = true
+	}
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if !e.policies.Allows(PolicyCreate) {
+		return managed.ExternalCreation{}, errors.New(errCreateNotAllowed)
+	}
+	return e.ExternalClient.Create(ctx, mg)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if !e.policies.Allows(PolicyUpdate) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+// Delete leaves the external resource in place when the management policies
+// do not allow Delete, as if the deletion policy were Orphan.
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	if !e.policies.Allows(PolicyDelete) {
+		return nil
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errBoom = errors.New("boom")
+
+func withPolicies(v string) *fake.Managed {
+	mg := &fake.Managed{}
+	mg.SetAnnotations(map[string]string{AnnotationKeyManagementPolicies: v})
+	return mg
+}
+
+func TestGet(t *testing.T) {
+	cases := map[string]struct {
+		mg   resource.Managed
+		want Policies
+		err  error
+	}{
+		"NoAnnotation": {
+			mg:   &fake.Managed{},
+			want: Policies{PolicyAll: true},
+		},
+		"ObserveOnly": {
+			mg:   withPolicies(ObserveOnly),
+			want: Policies{PolicyObserve: true},
+		},
+		"List": {
+			mg:   withPolicies("Observe, Create,Update"),
+			want: Policies{PolicyObserve: true, PolicyCreate: true, PolicyUpdate: true},
+		},
+		"Unknown": {
+			mg:  withPolicies("Observe,Destroy"),
+			err: errors.Errorf(errUnknownPolicyFmt, "Destroy"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Get(tc.mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Get(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Get(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExternal(t *testing.T) {
+	deleted := withPolicies(ObserveOnly)
+	now := metav1.Now()
+	deleted.SetDeletionTimestamp(&now)
+	deletedNoDelete := withPolicies("Observe,Create,Update")
+	deletedNoDelete.SetDeletionTimestamp(&now)
+	deletedFullControl := &fake.Managed{}
+	deletedFullControl.SetDeletionTimestamp(&now)
+
+	type want struct {
+		o         managed.ExternalObservation
+		observe   error
+		create    error
+		deleteErr error
+		deleted   bool
+	}
+
+	cases := map[string]struct {
+		mg     resource.Managed
+		exists bool
+		want   want
+	}{
+		"FullControl": {
+			mg:     &fake.Managed{},
+			exists: true,
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true},
+				deleted: true,
+			},
+		},
+		"ObserveOnlyExisting": {
+			mg:     withPolicies(ObserveOnly),
+			exists: true,
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				create: errors.New(errCreateNotAllowed),
+			},
+		},
+		"ObserveOnlyMissing": {
+			mg: withPolicies(ObserveOnly),
+			want: want{
+				observe: errors.New(errCreateNotAllowed),
+				create:  errors.New(errCreateNotAllowed),
+			},
+		},
+		"ObserveOnlyMissingWhileDeleting": {
+			mg: deleted,
+			want: want{
+				create: errors.New(errCreateNotAllowed),
+			},
+		},
+		"NoDelete": {
+			mg:     withPolicies("Observe,Create,Update"),
+			exists: true,
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+		"NoDeleteWhileDeleting": {
+			mg:     deletedNoDelete,
+			exists: true,
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"ObserveOnlyExistingWhileDeleting": {
+			mg:     deleted,
+			exists: true,
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: false},
+				create: errors.New(errCreateNotAllowed),
+			},
+		},
+		"FullControlWhileDeleting": {
+			mg:     deletedFullControl,
+			exists: true,
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true},
+				deleted: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			ec := managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{ResourceExists: tc.exists}, nil
+				},
+				CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+					return managed.ExternalCreation{}, nil
+				},
+				DeleteFn: func(_ context.Context, _ resource.Managed) error {
+					deleted = true
+					return nil
+				},
+			}
+			c := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}))
+
+			e, err := c.Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("Connect(...): %s", err)
+			}
+
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.observe, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			_, err = e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.create, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Create(...): -want error, +got error:\n%s", diff)
+			}
+			err = e.Delete(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.deleteErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("Delete(...): -want deleted, +got deleted:\n%s", diff)
+			}
+		})
+	}
+}
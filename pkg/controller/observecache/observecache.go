@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package observecache reuses the recent observation of a managed resource
+// that was available and up to date, rather than observing its external
+// resource again, until the observation is older than a TTL or the managed
+// resource changed. It trades bounded staleness for fewer API requests.
+package observecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// AnnotationKeyTTL is the annotation used to override the TTL of the
+// observations of a managed resource, e.g. "10m". Zero disables the cache for
+// the managed resource.
+const AnnotationKeyTTL = "metal.equinix.com/observe-cache-ttl"
+
+// A Cache holds the most recent observation of each managed resource that was
+// available and up to date.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[types.UID]entry
+}
+
+type entry struct {
+	observed    time.Time
+	version     string
+	observation managed.ExternalObservation
+}
+
+// New returns a Cache reusing observations for the supplied TTL, or for the
+// TTL annotated on a managed resource. A zero TTL reuses only observations of
+// managed resources annotated with a TTL.
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, now: time.Now, entries: map[types.UID]entry{}}
+}
+
+// NewConnecter returns an ExternalConnecter whose external clients observe
+// managed resources through the Cache.
+func (c *Cache) NewConnecter(conn managed.ExternalConnecter) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: conn, cache: c}
+}
+
+// ttlOf returns the TTL of the observations of the supplied managed resource.
+func (c *Cache) ttlOf(mg resource.Managed) time.Duration {
+	if v, ok := mg.GetAnnotations()[AnnotationKeyTTL]; ok {
+		if ttl, err := time.ParseDuration(v); err == nil {
+			return ttl
+		}
+	}
+	return c.ttl
+}
+
+// get returns the observation of the supplied managed resource, if one of
+// its current version is younger than its TTL.
+func (c *Cache) get(mg resource.Managed) (managed.ExternalObservation, bool) {
+	ttl := c.ttlOf(mg)
+	if ttl <= 0 || meta.WasDeleted(mg) {
+		return managed.ExternalObservation{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[mg.GetUID()]
+	if !ok || e.version != version(mg) || c.now().Sub(e.observed) > ttl {
+		return managed.ExternalObservation{}, false
+	}
+	return e.observation, true
+}
+
+// put records the supplied observation of the supplied managed resource if
+// it is available and up to date, and forgets any earlier one otherwise.
+func (c *Cache) put(mg resource.Managed, o managed.ExternalObservation) {
+	if !o.ResourceExists || !o.ResourceUpToDate || mg.GetCondition(xpv1.TypeReady).Reason != xpv1.ReasonAvailable {
+		c.forget(mg)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[mg.GetUID()] = entry{observed: c.now(), version: version(mg), observation: o}
+}
+
+// forget forgets the observation of the supplied managed resource.
+func (c *Cache) forget(mg resource.Managed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, mg.GetUID())
+}
+
+// version identifies the generation and annotations of the supplied managed
+// resource. Annotations, such as a requested action, change what observing a
+// resource does without changing its generation.
+func version(mg resource.Managed) string {
+	a, _ := json.Marshal(mg.GetAnnotations())
+	sum := sha256.Sum256(a)
+	return strconv.FormatInt(mg.GetGeneration(), 10) + "/" + hex.EncodeToString(sum[:])
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+	cache *Cache
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{ExternalClient: ec, cache: c.cache}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+	cache *Cache
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	if o, ok := e.cache.get(mg); ok {
+		return o, nil
+	}
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		e.cache.forget(mg)
+		return o, err
+	}
+	e.cache.put(mg, o)
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	e.cache.forget(mg)
+	return e.ExternalClient.Create(ctx, mg)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	e.cache.forget(mg)
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	e.cache.forget(mg)
+	return e.ExternalClient.Delete(ctx, mg)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package observecache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func TestObserve(t *testing.T) {
+	upToDate := managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	deleted := metav1.Now()
+
+	cases := map[string]struct {
+		ttl         time.Duration
+		annotations map[string]string
+		ready       xpv1.Condition
+		obs         managed.ExternalObservation
+		between     func(mg *fake.Managed, e managed.ExternalClient, now *time.Time)
+		want        int
+	}{
+		"Reused": {
+			ttl:   time.Minute,
+			ready: xpv1.Available(),
+			obs:   upToDate,
+			want:  1,
+		},
+		"Disabled": {
+			ready: xpv1.Available(),
+			obs:   upToDate,
+			want:  2,
+		},
+		"AnnotatedTTL": {
+			annotations: map[string]string{AnnotationKeyTTL: "1m"},
+			ready:       xpv1.Available(),
+			obs:         upToDate,
+			want:        1,
+		},
+		"AnnotationDisabled": {
+			ttl:         time.Minute,
+			annotations: map[string]string{AnnotationKeyTTL: "0s"},
+			ready:       xpv1.Available(),
+			obs:         upToDate,
+			want:        2,
+		},
+		"Expired": {
+			ttl:   time.Minute,
+			ready: xpv1.Available(),
+			obs:   upToDate,
+			between: func(_ *fake.Managed, _ managed.ExternalClient, now *time.Time) {
+				*now = now.Add(2 * time.Minute)
+			},
+			want: 2,
+		},
+		"GenerationChanged": {
+			ttl:   time.Minute,
+			ready: xpv1.Available(),
+			obs:   upToDate,
+			between: func(mg *fake.Managed, _ managed.ExternalClient, _ *time.Time) {
+				mg.SetGeneration(2)
+			},
+			want: 2,
+		},
+		"AnnotationsChanged": {
+			ttl:   time.Minute,
+			ready: xpv1.Available(),
+			obs:   upToDate,
+			between: func(mg *fake.Managed, _ managed.ExternalClient, _ *time.Time) {
+				meta.AddAnnotations(mg, map[string]string{"example.org/action": "reboot"})
+			},
+			want: 2,
+		},
+		"Deleted": {
+			ttl:   time.Minute,
+			ready: xpv1.Available(),
+			obs:   upToDate,
+			between: func(mg *fake.Managed, _ managed.ExternalClient, _ *time.Time) {
+				mg.SetDeletionTimestamp(&deleted)
+			},
+			want: 2,
+		},
+		"Updated": {
+			ttl:   time.Minute,
+			ready: xpv1.Available(),
+			obs:   upToDate,
+			between: func(mg *fake.Managed, e managed.ExternalClient, _ *time.Time) {
+				_, _ = e.Update(context.Background(), mg)
+			},
+			want: 2,
+		},
+		"NotAvailable": {
+			ttl:   time.Minute,
+			ready: xpv1.Creating(),
+			obs:   upToDate,
+			want:  2,
+		},
+		"NotUpToDate": {
+			ttl:   time.Minute,
+			ready: xpv1.Available(),
+			obs:   managed.ExternalObservation{ResourceExists: true},
+			want:  2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Unix(1600000000, 0)
+			c := New(tc.ttl)
+			c.now = func() time.Time { return now }
+
+			observed := 0
+			conn := c.NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+						observed++
+						return tc.obs, nil
+					},
+					UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+						return managed.ExternalUpdate{}, nil
+					},
+				}, nil
+			}))
+
+			mg := &fake.Managed{}
+			mg.SetUID("example")
+			mg.SetGeneration(1)
+			mg.SetAnnotations(tc.annotations)
+			mg.SetConditions(tc.ready)
+
+			e, _ := conn.Connect(context.Background(), mg)
+			for i := 0; i < 2; i++ {
+				if _, err := e.Observe(context.Background(), mg); err != nil {
+					t.Fatalf("Observe(...): unexpected error: %s", err)
+				}
+				if i == 0 && tc.between != nil {
+					tc.between(mg, e, &now)
+				}
+			}
+
+			if diff := cmp.Diff(tc.want, observed); diff != "" {
+				t.Errorf("Observe(...): -want observations, +got:\n%s", diff)
+			}
+		})
+	}
+}
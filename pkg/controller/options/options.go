@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package options contains the configuration shared by all Equinix Metal
+// controllers.
+package options
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/backoff"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/connection"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/graceful"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/features"
+)
+
+// Options configures the Equinix Metal controllers.
+type Options struct {
+	// Logger is used by all controllers.
+	Logger logging.Logger
+
+	// PollInterval controls how often an individual resource should be
+	// checked for drift.
+	PollInterval time.Duration
+
+	// MaxConcurrentReconciles of each controller.
+	MaxConcurrentReconciles int
+
+	// ControllerMaxConcurrentReconciles overrides MaxConcurrentReconciles
+	// for the named controllers, for example to reconcile more of the cheap
+	// VirtualNetworks than of the slow Devices at once.
+	ControllerMaxConcurrentReconciles map[string]int
+
+	// BackoffBaseDelay is how long a controller waits before retrying a
+	// resource whose reconcile failed. It doubles with each consecutive
+	// failure, up to BackoffMaxDelay.
+	BackoffBaseDelay time.Duration
+
+	// BackoffMaxDelay is the longest a controller waits before retrying a
+	// resource whose reconcile failed, before jitter.
+	BackoffMaxDelay time.Duration
+
+	// BackoffJitter lengthens each backoff by a random fraction of up to
+	// BackoffJitter of it.
+	BackoffJitter float64
+
+	// Controllers that should be set up. All controllers are set up when
+	// empty.
+	Controllers []string
+
+	// Features that are enabled.
+	Features *features.Flags
+
+	// ReadOnly controllers only observe external resources, as if every
+	// ProviderConfig were read-only.
+	ReadOnly bool
+
+	// Plan controllers only observe external resources, and report the
+	// changes they would make to them instead of making them.
+	Plan bool
+
+	// ConnectionSecretPolicy determines whether connection secrets are
+	// deleted or retained when their managed resource is deleted.
+	ConnectionSecretPolicy connection.Policy
+
+	// DeviceCacheTTL is how long the Devices of a project listed to observe
+	// them are served from a cache. The cache is disabled when zero, and each
+	// Device is read individually.
+	DeviceCacheTTL time.Duration
+
+	// ObserveCacheTTL is how long the observation of a managed resource that
+	// was available and up to date is reused, unless the managed resource
+	// changes. Observations are not reused when zero, unless a managed
+	// resource is annotated with a TTL.
+	ObserveCacheTTL time.Duration
+
+	// DeviceKeyscan scans the SSH host keys of active Devices from the
+	// provider, and adds them to their connection details as known_hosts
+	// entries.
+	DeviceKeyscan bool
+
+	// BatchObserve Devices from one list of the Devices of each project per
+	// poll interval, rather than reading each Device.
+	BatchObserve bool
+
+	// DeviceInventory is the ConfigMap maintained as an inventory of all
+	// managed Devices. No inventory is maintained when its name is empty.
+	DeviceInventory types.NamespacedName
+
+	// Drainer lets reconciles in flight when the provider stops finish. They
+	// are abandoned when it is nil.
+	Drainer *graceful.Drainer
+}
+
+// ControllerOptions returns the options of a controller of managed resources.
+func (o Options) ControllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+		RateLimiter:             backoff.NewRateLimiter(o.BackoffBaseDelay, o.BackoffMaxDelay, o.BackoffJitter),
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package member
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/organization/v1alpha1"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	orgclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/organization"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/reconciler"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errTrackPCUsage            = "cannot track ProviderConfig usage"
+	errGetProviderConfigSecret = "cannot get ProviderConfig Secret"
+	errNewClient               = "cannot create new Organization client"
+	errNotMember               = "managed resource is not a Member"
+	errNoOrganization          = "Member specifies no organization"
+	errGetOrganization         = "cannot get Organization"
+	errCreateMember            = "organization has no member with the email address; members are invited from the Equinix Metal console"
+)
+
+// SetupMember adds a controller that observes the Members of Equinix Metal
+// organizations.
+func SetupMember(mgr ctrl.Manager, o options.Options) error {
+	name := managed.ControllerName(v1alpha1.MemberGroupKind)
+
+	recorder := clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
+
+	c := &connecter{
+		kube:  mgr.GetClient(),
+		usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:   o.Logger.WithValues("controller", name),
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Member{}).
+		WithOptions(o.ControllerOptions()).
+		Complete(reconciler.NewManaged(mgr, o, name, v1alpha1.MemberGroupVersionKind, c, recorder,
+			managed.WithInitializers(&managed.DefaultProviderConfig{}),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+		))
+}
+
+type connecter struct {
+	kube        client.Client
+	usage       resource.Tracker
+	log         logging.Logger
+	newClientFn func(ctx context.Context, config *clients.Credentials) (orgclient.ClientWithDefaults, error)
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Member); !ok {
+		return nil, errors.New(errNotMember)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	newClientFn := orgclient.NewClient
+	if c.newClientFn != nil {
+		newClientFn = c.newClientFn
+	}
+	cfg, err := clients.GetAuthInfo(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	client, err := newClientFn(clients.WithController(ctx, managed.ControllerName(v1alpha1.MemberGroupKind)), cfg)
+
+	log := c.log.WithValues("name", mg.GetName(), "uid", mg.GetUID())
+	return &external{client: client, log: log}, errors.Wrap(err, errNewClient)
+}
+
+type external struct {
+	client orgclient.ClientWithDefaults
+	log    logging.Logger
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	m, ok := mg.(*v1alpha1.Member)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMember)
+	}
+
+	// A Member is never removed from its organization, so it is gone as soon
+	// as it is deleted.
+	if meta.WasDeleted(m) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	if m.Spec.ForProvider.OrganizationID == "" {
+		return managed.ExternalObservation{}, errors.New(errNoOrganization)
+	}
+
+	o, _, err := e.client.Get(m.Spec.ForProvider.OrganizationID, orgclient.MembersOptions())
+	if clients.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetOrganization)
+	}
+
+	u, roles := orgclient.FindMember(o, m.Spec.ForProvider.Email)
+	if u == nil {
+		e.log.Debug("Organization has no member with the email address", "organization", o.ID)
+		m.Status.AtProvider = v1alpha1.MemberObservation{}
+		m.Status.SetConditions(xpv1.Unavailable())
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	meta.SetExternalName(m, u.ID)
+	m.Status.AtProvider = v1alpha1.MemberObservation{UserID: u.ID, FullName: u.FullName, Roles: roles}
+	m.Status.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	// NOTE: Members cannot be invited through the API the provider uses.
+	return managed.ExternalCreation{}, errors.New(errCreateMember)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	// NOTE: Members are only observed.
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	// NOTE: Members are never removed from their organization.
+	return nil
+}
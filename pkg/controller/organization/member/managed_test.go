@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package member
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/organization/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/organization/fake"
+)
+
+const (
+	orgID  = "2b4e6a8c-0d1f-4a3b-9c5e-7f9a1b3c5d7e"
+	userID = "8f6d4b2a-9e7c-4f5a-b3d1-0c2e4a6b8d9f"
+	email  = "ci@example.com"
+)
+
+var errBoom = errors.New("boom")
+
+type memberModifier func(*v1alpha1.Member)
+
+func withExternalName(n string) memberModifier {
+	return func(m *v1alpha1.Member) { meta.SetExternalName(m, n) }
+}
+
+func withAtProvider(o v1alpha1.MemberObservation) memberModifier {
+	return func(m *v1alpha1.Member) { m.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) memberModifier {
+	return func(m *v1alpha1.Member) { m.Status.SetConditions(c...) }
+}
+
+func withOrganizationID(id string) memberModifier {
+	return func(m *v1alpha1.Member) { m.Spec.ForProvider.OrganizationID = id }
+}
+
+func withDeletionTimestamp() memberModifier {
+	return func(m *v1alpha1.Member) { m.SetDeletionTimestamp(&metav1.Time{Time: time.Unix(0, 0)}) }
+}
+
+func member(mm ...memberModifier) *v1alpha1.Member {
+	m := &v1alpha1.Member{
+		Spec: v1alpha1.MemberSpec{
+			ForProvider: v1alpha1.MemberParameters{OrganizationID: orgID, Email: email},
+		},
+	}
+	for _, mod := range mm {
+		mod(m)
+	}
+	return m
+}
+
+func organization() *packngo.Organization {
+	u := packngo.User{ID: userID, FullName: "CI", Email: "someone@example.com", Emails: []packngo.Email{{Address: "CI@example.com"}}}
+	return &packngo.Organization{ID: orgID, Users: []packngo.User{{ID: "other", Email: "other@example.com"}, u}}
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		mg  *v1alpha1.Member
+		obs managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		mg  *v1alpha1.Member
+		org func() *packngo.Organization
+		err error
+		want
+	}{
+		"Member": {
+			mg:  member(),
+			org: organization,
+			want: want{
+				mg: member(
+					withExternalName(userID),
+					withAtProvider(v1alpha1.MemberObservation{UserID: userID, FullName: "CI", Roles: []string{v1alpha1.RoleMember}}),
+					withConditions(xpv1.Available()),
+				),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Owner": {
+			mg: member(),
+			org: func() *packngo.Organization {
+				o := organization()
+				o.Owners = []packngo.User{{ID: userID}}
+				return o
+			},
+			want: want{
+				mg: member(
+					withExternalName(userID),
+					withAtProvider(v1alpha1.MemberObservation{UserID: userID, FullName: "CI", Roles: []string{v1alpha1.RoleMember, v1alpha1.RoleOwner}}),
+					withConditions(xpv1.Available()),
+				),
+				obs: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"NotAMember": {
+			mg: member(withAtProvider(v1alpha1.MemberObservation{UserID: userID})),
+			org: func() *packngo.Organization {
+				return &packngo.Organization{ID: orgID}
+			},
+			want: want{
+				mg:  member(withConditions(xpv1.Unavailable())),
+				obs: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"OrganizationNotFound": {
+			mg:  member(),
+			err: &packngo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}},
+			want: want{
+				mg:  member(),
+				obs: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"GetFailed": {
+			mg:  member(),
+			err: errBoom,
+			want: want{
+				mg:  member(),
+				err: errors.Wrap(errBoom, errGetOrganization),
+			},
+		},
+		"NoOrganization": {
+			mg: member(withOrganizationID("")),
+			want: want{
+				mg:  member(withOrganizationID("")),
+				err: errors.New(errNoOrganization),
+			},
+		},
+		"Deleted": {
+			mg: member(withDeletionTimestamp()),
+			want: want{
+				mg:  member(withDeletionTimestamp()),
+				obs: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				log: logging.NewNopLogger(),
+				client: &fake.MockClient{
+					GetFunc: func(organizationID string, getOpt *packngo.GetOptions) (*packngo.Organization, *packngo.Response, error) {
+						if tc.err != nil {
+							return nil, nil, tc.err
+						}
+						return tc.org(), nil, nil
+					},
+				},
+			}
+
+			obs, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.obs, obs); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions()); diff != "" {
+				t.Errorf("Observe(...): -want managed resource, +got managed resource:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	e := &external{log: logging.NewNopLogger(), client: &fake.MockClient{}}
+	if _, err := e.Create(context.Background(), member()); err == nil {
+		t.Errorf("Create(...): expected an error, since members cannot be invited")
+	}
+}
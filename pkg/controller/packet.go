@@ -17,24 +17,112 @@ limitations under the License.
 package controller
 
 import (
-	ctrl "sigs.k8s.io/controller-runtime"
+	"strconv"
+	"strings"
 
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/billing/budget"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/config"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/organization/member"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/ports/assignment"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/server/device"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/vlan/virtualnetwork"
 )
 
-// Setup creates all Equinix Metal controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
-		assignment.SetupAssignment,
-		device.SetupDevice,
-		virtualnetwork.SetupVirtualNetwork,
-	} {
-		if err := setup(mgr, l); err != nil {
+const (
+	errUnknownControllerFmt = "unknown controller %q"
+	errMaxReconcilesFmt     = "max reconciles %q is not of the form controller=n with n at least 1"
+)
+
+// Controller names accepted by Options.Controllers.
+const (
+	ControllerAssignment     = "assignment"
+	ControllerBudget         = "budget"
+	ControllerDevice         = "device"
+	ControllerMember         = "member"
+	ControllerVirtualNetwork = "virtualnetwork"
+)
+
+type setupFn func(ctrl.Manager, options.Options) error
+
+var setups = []struct {
+	name  string
+	setup setupFn
+}{
+	{name: ControllerAssignment, setup: assignment.SetupAssignment},
+	{name: ControllerBudget, setup: budget.SetupBudget},
+	{name: ControllerDevice, setup: device.SetupDevice},
+	{name: ControllerMember, setup: member.SetupMember},
+	{name: ControllerVirtualNetwork, setup: virtualnetwork.SetupVirtualNetwork},
+}
+
+// ControllerNames returns the names of all Equinix Metal controllers.
+func ControllerNames() []string {
+	names := make([]string, len(setups))
+	for i, s := range setups {
+		names[i] = s.name
+	}
+	return names
+}
+
+// ParseMaxReconciles parses max reconciles of the form controller=n, such as
+// device=2, into the number of concurrent reconciles of each named controller.
+func ParseMaxReconciles(values ...string) (map[string]int, error) {
+	known := map[string]bool{}
+	for _, s := range setups {
+		known[s.name] = true
+	}
+	max := map[string]int{}
+	for _, v := range values {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf(errMaxReconcilesFmt, v)
+		}
+		n, err := strconv.Atoi(kv[1])
+		if err != nil || n < 1 {
+			return nil, errors.Errorf(errMaxReconcilesFmt, v)
+		}
+		if !known[kv[0]] {
+			return nil, errors.Errorf(errUnknownControllerFmt, kv[0])
+		}
+		max[kv[0]] = n
+	}
+	return max, nil
+}
+
+// Setup creates the ProviderConfig controller and the enabled Equinix Metal
+// controllers with the supplied options and adds them to the supplied manager.
+// All controllers are enabled when o.Controllers is empty.
+func Setup(mgr ctrl.Manager, o options.Options) error {
+	enabled := map[string]bool{}
+	for _, name := range o.Controllers {
+		enabled[name] = true
+	}
+	known := map[string]bool{}
+	for _, s := range setups {
+		known[s.name] = true
+	}
+	for name := range enabled {
+		if !known[name] {
+			return errors.Errorf(errUnknownControllerFmt, name)
+		}
+	}
+
+	if err := config.Setup(mgr, o.Logger); err != nil {
+		return err
+	}
+	for _, s := range setups {
+		if len(enabled) > 0 && !enabled[s.name] {
+			continue
+		}
+		co := o
+		if n, ok := o.ControllerMaxConcurrentReconciles[s.name]; ok {
+			co.MaxConcurrentReconciles = n
+		}
+		if err := s.setup(mgr, co); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseMaxReconciles(t *testing.T) {
+	cases := map[string]struct {
+		values  []string
+		want    map[string]int
+		wantErr bool
+	}{
+		"None": {
+			want: map[string]int{},
+		},
+		"PerController": {
+			values: []string{"device=2", "virtualnetwork=10"},
+			want:   map[string]int{ControllerDevice: 2, ControllerVirtualNetwork: 10},
+		},
+		"UnknownController": {
+			values:  []string{"sshkey=10"},
+			wantErr: true,
+		},
+		"NotANumber": {
+			values:  []string{"device=many"},
+			wantErr: true,
+		},
+		"Zero": {
+			values:  []string{"device=0"},
+			wantErr: true,
+		},
+		"MissingNumber": {
+			values:  []string{"device"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseMaxReconciles(tc.values...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseMaxReconciles(...): want error %t, got %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ParseMaxReconciles(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan observes the external resources of managed resources and
+// reports the changes the provider would make to them, without ever creating,
+// updating or deleting them. It suits evaluating what adopting the provider
+// would change.
+package plan
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Messages of the events describing planned changes.
+var messages = map[xpv1.ConditionReason]string{
+	v1beta1.ReasonPendingCreate: "Would create external resource",
+	v1beta1.ReasonPendingUpdate: "Would update external resource that differs from its desired state",
+	v1beta1.ReasonPendingDelete: "Would delete external resource; leaving it in place",
+}
+
+type plannedKey struct{}
+
+// Planning returns true if the supplied context is that of an observation
+// whose changes are only planned. External clients must then not make any
+// change themselves, and may propose one with Propose instead.
+func Planning(ctx context.Context) bool {
+	_, ok := ctx.Value(plannedKey{}).(*xpv1.Condition)
+	return ok
+}
+
+// Propose reports the supplied Planned condition, such as PendingDelete, for
+// a change an external client would make itself while observing the external
+// resource. It overrides the change derived from the observation, unless the
+// managed resource is being deleted.
+func Propose(ctx context.Context, c xpv1.Condition) {
+	if p, ok := ctx.Value(plannedKey{}).(*xpv1.Condition); ok {
+		*p = c
+	}
+}
+
+// NewConnecter returns an ExternalConnecter whose external clients only
+// observe external resources, and report the changes they would make with
+// the Planned condition of each managed resource, an event and a log entry.
+func NewConnecter(c managed.ExternalConnecter, r event.Recorder, l logging.Logger) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c, recorder: r, log: l}
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+	recorder event.Recorder
+	log      logging.Logger
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{ExternalClient: ec, recorder: c.recorder, log: c.log}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+	recorder event.Recorder
+	log      logging.Logger
+}
+
+// Observe reports the change that would be made to the external resource,
+// then reports it as existing and up to date so that it is never created or
+// updated. An existing external resource of a deleted managed resource is
+// reported as gone, so that the managed resource is deleted and its external
+// resource left in place, as if the deletion policy were Orphan.
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	proposed := &xpv1.Condition{}
+	o, err := e.ExternalClient.Observe(context.WithValue(ctx, plannedKey{}, proposed), mg)
+	if err != nil {
+		return o, err
+	}
+
+	c := v1beta1.NoChanges()
+	switch {
+	case meta.WasDeleted(mg):
+		if o.ResourceExists && mg.GetDeletionPolicy() != xpv1.DeletionOrphan {
+			c = v1beta1.PendingDelete()
+		}
+		o.ResourceExists = false
+	case proposed.Type != "":
+		c = *proposed
+		o.ResourceExists, o.ResourceUpToDate = true, true
+	case !o.ResourceExists:
+		c = v1beta1.PendingCreate()
+		o.ResourceExists, o.ResourceUpToDate = true, true
+	case !o.ResourceUpToDate:
+		c = v1beta1.PendingUpdate()
+		o.ResourceUpToDate = true
+	}
+	e.report(mg, c)
+	return o, nil
+}
+
+// report sets the supplied Planned condition, and records and logs a change
+// that was not planned at the last observation.
+func (e *external) report(mg resource.Managed, c xpv1.Condition) {
+	previous := mg.GetCondition(v1beta1.TypePlanned)
+	mg.SetConditions(c)
+	msg, ok := messages[c.Reason]
+	if !ok || previous.Reason == c.Reason {
+		return
+	}
+	e.recorder.Event(mg, event.Normal(clients.ReasonPlannedChange, msg))
+	e.log.Info(msg, "name", mg.GetName(), "external-name", meta.GetExternalName(mg))
+}
+
+func (e *external) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(_ context.Context, _ resource.Managed) error {
+	return nil
+}
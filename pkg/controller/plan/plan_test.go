@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+var errBoom = errors.New("boom")
+
+// eventRecorder records the events it is asked to record.
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestObserve(t *testing.T) {
+	managedResource := func(deleted bool, conditions ...xpv1.Condition) *fake.Managed {
+		mg := &fake.Managed{}
+		if deleted {
+			now := metav1.Now()
+			mg.SetDeletionTimestamp(&now)
+		}
+		mg.SetConditions(conditions...)
+		return mg
+	}
+
+	type want struct {
+		o      managed.ExternalObservation
+		err    error
+		reason xpv1.ConditionReason
+		events int
+	}
+
+	cases := map[string]struct {
+		mg      *fake.Managed
+		o       managed.ExternalObservation
+		propose xpv1.Condition
+		err     error
+		want    want
+	}{
+		"Missing": {
+			mg: managedResource(false),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason: v1beta1.ReasonPendingCreate,
+				events: 1,
+			},
+		},
+		"StillMissing": {
+			mg: managedResource(false, v1beta1.PendingCreate()),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason: v1beta1.ReasonPendingCreate,
+			},
+		},
+		"Drifted": {
+			mg: managedResource(false),
+			o:  managed.ExternalObservation{ResourceExists: true},
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason: v1beta1.ReasonPendingUpdate,
+				events: 1,
+			},
+		},
+		"UpToDate": {
+			mg: managedResource(false, v1beta1.PendingUpdate()),
+			o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason: v1beta1.ReasonNoChanges,
+			},
+		},
+		"Deleted": {
+			mg: managedResource(true),
+			o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				o:      managed.ExternalObservation{ResourceUpToDate: true},
+				reason: v1beta1.ReasonPendingDelete,
+				events: 1,
+			},
+		},
+		"Proposed": {
+			mg:      managedResource(false),
+			o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			propose: v1beta1.PendingDelete(),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason: v1beta1.ReasonPendingDelete,
+				events: 1,
+			},
+		},
+		"ObserveFailed": {
+			mg:  managedResource(false),
+			err: errBoom,
+			want: want{
+				err: errBoom,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ec := managed.ExternalClientFns{
+				ObserveFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					if !Planning(ctx) {
+						t.Error("Observe(...): want a planning context")
+					}
+					if tc.propose.Type != "" {
+						Propose(ctx, tc.propose)
+					}
+					return tc.o, tc.err
+				},
+			}
+			r := &eventRecorder{}
+			c := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}), r, logging.NewNopLogger())
+			e, err := c.Connect(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("Connect(...): %s", err)
+			}
+
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, tc.mg.GetCondition(v1beta1.TypePlanned).Reason); diff != "" {
+				t.Errorf("Observe(...): -want reason, +got reason:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.events, len(r.events)); diff != "" {
+				t.Errorf("Observe(...): -want events, +got events:\n%s", diff)
+			}
+		})
+	}
+}
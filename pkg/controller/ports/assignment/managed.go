@@ -25,11 +25,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	emportsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/ports/v1alpha1"
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
 	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	portsclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ports"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/alias"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/reconciler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -50,32 +54,66 @@ const (
 	errDeleteAssignment        = "cannot delete Assignment"
 )
 
-// SetupAssignment adds a controller that reconciles Assignments
-func SetupAssignment(mgr ctrl.Manager, l logging.Logger) error {
+// SetupAssignment adds controllers that reconcile the Assignments of the
+// ports.metal.equinix.com API group, and of the
+// ports.equinixmetal.crossplane.io API group that aliases it.
+func SetupAssignment(mgr ctrl.Manager, o options.Options) error {
 	name := managed.ControllerName(v1alpha1.AssignmentGroupKind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.AssignmentGroupVersionKind),
-		managed.WithExternalConnecter(&connecter{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
-		}),
-		managed.WithInitializers(&managed.DefaultProviderConfig{}),
-		managed.WithConnectionPublishers(),
-		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
-		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	)
+	recorder := clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
 
-	return ctrl.NewControllerManagedBy(mgr).
+	c := &connecter{
+		kube:     mgr.GetClient(),
+		usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:      o.Logger.WithValues("controller", name),
+		recorder: recorder,
+	}
+	err := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha1.Assignment{}).
-		Complete(r)
+		WithOptions(o.ControllerOptions()).
+		Complete(reconciler.NewManaged(mgr, o, name, v1alpha1.AssignmentGroupVersionKind, c, recorder, assignmentOptions(mgr)...))
+	if err != nil {
+		return err
+	}
+
+	name = managed.ControllerName(emportsv1alpha1.AssignmentGroupKind)
+	recorder = clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
+	ac := &connecter{
+		kube:     alias.NewClient(mgr.GetClient(), aliasKind),
+		usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:      o.Logger.WithValues("controller", name),
+		recorder: recorder,
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&emportsv1alpha1.Assignment{}).
+		WithOptions(o.ControllerOptions()).
+		Complete(reconciler.NewManaged(mgr, o, name, emportsv1alpha1.AssignmentGroupVersionKind, alias.NewConnecter(ac, aliasKind), recorder, assignmentOptions(mgr)...))
+}
+
+// aliasKind is the Assignment of the ports.equinixmetal.crossplane.io API
+// group, which aliases the Assignment of the ports.metal.equinix.com API group.
+var aliasKind = alias.Kind{
+	GroupVersionKind: emportsv1alpha1.AssignmentGroupVersionKind,
+	New:              func() alias.Object { return &emportsv1alpha1.Assignment{} },
+	NewHub:           func() alias.Hub { return &v1alpha1.Assignment{} },
+}
+
+// assignmentOptions returns the options of the managed reconcilers of
+// Assignments, which resolve their Device and VirtualNetwork references.
+func assignmentOptions(mgr ctrl.Manager) []managed.ReconcilerOption {
+	return []managed.ReconcilerOption{
+		managed.WithInitializers(&managed.DefaultProviderConfig{}),
+		managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+	}
 }
 
 type connecter struct {
 	kube        client.Client
 	usage       resource.Tracker
+	log         logging.Logger
+	recorder    event.Recorder
 	newClientFn func(ctx context.Context, config *clients.Credentials) (portsclient.ClientWithDefaults, error)
 }
 
@@ -96,14 +134,17 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errGetProviderConfigSecret)
 	}
-	client, err := newClientFn(ctx, cfg)
+	client, err := newClientFn(clients.WithController(ctx, managed.ControllerName(v1alpha1.AssignmentGroupKind)), cfg)
 
-	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+	log := c.log.WithValues("name", mg.GetName(), "uid", mg.GetUID())
+	return &external{kube: c.kube, client: client, log: log, recorder: c.recorder}, errors.Wrap(err, errNewClient)
 }
 
 type external struct {
-	kube   client.Client
-	client portsclient.ClientWithDefaults
+	kube     client.Client
+	client   portsclient.ClientWithDefaults
+	log      logging.Logger
+	recorder event.Recorder
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -118,6 +159,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 	if err != nil {
+		recordRequestID(a, err)
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetPort)
 	}
 
@@ -133,6 +175,10 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		}
 	}
 
+	if !o.ResourceExists {
+		e.log.Debug("VirtualNetwork is not assigned to Port", "port", port.ID, "virtualNetwork", a.Spec.ForProvider.VirtualNetworkID)
+	}
+
 	meta.SetExternalName(a, port.ID)
 	return o, nil
 }
@@ -144,7 +190,10 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 	a.Status.SetConditions(xpv1.Creating())
 	_, _, err := e.client.Assign(&packngo.PortAssignRequest{PortID: meta.GetExternalName(a), VirtualNetworkID: a.Spec.ForProvider.VirtualNetworkID})
-	return managed.ExternalCreation{}, errors.Wrap(resource.Ignore(packetclient.IsAlreadyDone, err), errCreateAssignment)
+	err = resource.Ignore(packetclient.IsAlreadyDone, err)
+	packetclient.RecordAPIError(e.recorder, a, errCreateAssignment, err)
+	recordRequestID(a, err)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateAssignment)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -159,5 +208,16 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 	a.SetConditions(xpv1.Deleting())
 	_, _, err := e.client.Unassign(&packngo.PortAssignRequest{PortID: meta.GetExternalName(a), VirtualNetworkID: a.Spec.ForProvider.VirtualNetworkID})
-	return errors.Wrap(resource.IgnoreAny(err, packetclient.IsNotFound, packetclient.IsAlreadyDone), errDeleteAssignment)
+	err = resource.IgnoreAny(err, packetclient.IsNotFound, packetclient.IsAlreadyDone)
+	packetclient.RecordAPIError(e.recorder, a, errDeleteAssignment, err)
+	recordRequestID(a, err)
+	return errors.Wrap(err, errDeleteAssignment)
+}
+
+// recordRequestID records the ID of the failed Equinix Metal API request
+// wrapped by err, if any, in the status of the supplied Assignment.
+func recordRequestID(a *v1alpha1.Assignment, err error) {
+	if id := packetclient.RequestID(err); id != "" {
+		a.Status.AtProvider.LastRequestID = id
+	}
 }
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assignment
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/ports/fake"
+)
+
+const (
+	portID   = "7f3b2c1d-4e5a-4b6c-8d7e-9f0a1b2c3d4e"
+	deviceID = "3c1d2e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f"
+)
+
+func assignment() *v1alpha1.Assignment {
+	a := &v1alpha1.Assignment{}
+	a.SetName("my-assignment")
+	meta.SetExternalName(a, portID)
+	a.Spec.ForProvider.DeviceID = deviceID
+	a.Spec.ForProvider.Name = "bond0"
+	a.Spec.ForProvider.VirtualNetworkID = "5d3c6a1e-8b2f-4e7a-9c1d-3f6b0a2e4c8d"
+	return a
+}
+
+func TestRecordRequestID(t *testing.T) {
+	apiErr := &packngo.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{},
+			Request:    &http.Request{},
+		},
+	}
+	apiErr.Response.Header.Set(clients.HeaderRequestID, "9a8b7c6d")
+
+	e := &external{
+		log:      logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+		client: &fake.MockClient{
+			GetPortByNameFunc: func(_ string, _ string) (*packngo.Port, error) {
+				return nil, apiErr
+			},
+			AssignFunc: func(_ *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+				return nil, nil, apiErr
+			},
+			UnassignFunc: func(_ *packngo.PortAssignRequest) (*packngo.Port, *packngo.Response, error) {
+				return nil, nil, apiErr
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		call func(a *v1alpha1.Assignment) error
+		err  error
+	}{
+		"Observe": {
+			call: func(a *v1alpha1.Assignment) error {
+				_, err := e.Observe(context.Background(), a)
+				return err
+			},
+			err: errors.Wrap(apiErr, errGetPort),
+		},
+		"Create": {
+			call: func(a *v1alpha1.Assignment) error {
+				_, err := e.Create(context.Background(), a)
+				return err
+			},
+			err: errors.Wrap(apiErr, errCreateAssignment),
+		},
+		"Delete": {
+			call: func(a *v1alpha1.Assignment) error {
+				return e.Delete(context.Background(), a)
+			},
+			err: errors.Wrap(apiErr, errDeleteAssignment),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := assignment()
+			err := tc.call(a)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s(...): -want error, +got error:\n%s", name, diff)
+			}
+			if diff := cmp.Diff("9a8b7c6d", a.Status.AtProvider.LastRequestID); diff != "" {
+				t.Errorf("%s(...): -want last request ID, +got:\n%s", name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimited reports managed resources whose external client was
+// refused by the Equinix Metal API rate limit with a RateLimited condition,
+// rather than as failed reconciles, and requeues them once the rate limit
+// allows requests again instead of backing off blindly. Reconciles of resources in a steady state are low
+// priority, so that they are deferred while the rate limit is nearly
+// exhausted rather than starving deletes and provisioning. A deferred
+// reconcile is requeued until the rate limit resets without reporting an
+// error, so the resource stays steady and is deferred again if need be.
+package ratelimited
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// TypeRateLimited resources were refused by the Equinix Metal API rate limit
+// during their most recent reconcile.
+const TypeRateLimited xpv1.ConditionType = "RateLimited"
+
+// Reasons a resource is or is not rate limited.
+const (
+	ReasonRateLimited    xpv1.ConditionReason = "RateLimitExceeded"
+	ReasonNotRateLimited xpv1.ConditionReason = "RateLimitNotExceeded"
+)
+
+// RateLimited returns a condition indicating the resource is rate limited
+// until the supplied time.
+func RateLimited(until time.Time) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRateLimited,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRateLimited,
+		Message:            fmt.Sprintf("Equinix Metal API rate limit exceeded, retrying after %s", until.UTC().Format(time.RFC3339)),
+	}
+}
+
+// NotRateLimited returns a condition indicating the resource is no longer
+// rate limited.
+func NotRateLimited() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRateLimited,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotRateLimited,
+	}
+}
+
+// A Tracker records when rate limited resources may be reconciled again.
+// Its connecter sets the RateLimited condition of the resources, and its
+// reconciler requeues them accordingly.
+type Tracker struct {
+	mu    sync.Mutex
+	until map[types.NamespacedName]time.Time
+	now   func() time.Time
+}
+
+// NewTracker returns a Tracker without rate limited resources.
+func NewTracker() *Tracker {
+	return &Tracker{until: map[types.NamespacedName]time.Time{}, now: time.Now}
+}
+
+// NewConnecter returns an ExternalConnecter whose external clients record the
+// resources refused by the rate limit with the Tracker.
+func (t *Tracker) NewConnecter(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c, tracker: t}
+}
+
+// NewReconciler returns a Reconciler that requeues the resources recorded by
+// the Tracker when the rate limit allows requests again.
+func (t *Tracker) NewReconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return &reconciler{Reconciler: r, tracker: t}
+}
+
+// limited returns true if err is a request refused by the rate limit,
+// recording when the supplied resource may be reconciled again. It sets the
+// RateLimited condition of the resource accordingly.
+func (t *Tracker) limited(mg resource.Managed, err error) bool {
+	after, ok := clients.RetryAfter(err)
+	if !ok {
+		if mg.GetCondition(TypeRateLimited).Status == corev1.ConditionTrue {
+			mg.SetConditions(NotRateLimited())
+		}
+		return false
+	}
+	until := t.now().Add(after)
+	mg.SetConditions(RateLimited(until))
+	t.requeue(mg, until)
+	return true
+}
+
+// deferred returns true if err is a deferred low priority request, recording
+// when the supplied resource may be reconciled again.
+func (t *Tracker) deferred(mg resource.Managed, err error) bool {
+	after, ok := clients.Deferred(err)
+	if !ok {
+		return false
+	}
+	t.requeue(mg, t.now().Add(after))
+	return true
+}
+
+func (t *Tracker) requeue(mg resource.Managed, until time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.until[types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()}] = until
+}
+
+// take returns how long until the supplied resource may be reconciled again,
+// if it was rate limited, and forgets it.
+func (t *Tracker) take(nn types.NamespacedName) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	until, ok := t.until[nn]
+	if !ok {
+		return 0, false
+	}
+	delete(t.until, nn)
+	return until.Sub(t.now()), true
+}
+
+type reconciler struct {
+	reconcile.Reconciler
+	tracker *Tracker
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	res, err := r.Reconciler.Reconcile(ctx, req)
+	after, ok := r.tracker.take(req.NamespacedName)
+	if !ok || err != nil {
+		return res, err
+	}
+	if after <= 0 {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	return reconcile.Result{RequeueAfter: after}, nil
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+	tracker *Tracker
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if steady(mg) {
+		ctx = clients.WithLowPriority(ctx)
+	}
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{ExternalClient: ec, tracker: c.tracker}, nil
+}
+
+// steady returns true if the supplied resource is available and is not being
+// deleted. Reconciling it only checks it for drift, which can wait until the
+// rate limit resets.
+func steady(mg resource.Managed) bool {
+	return !meta.WasDeleted(mg) && mg.GetCondition(xpv1.TypeReady).Reason == xpv1.ReasonAvailable
+}
+
+type external struct {
+	managed.ExternalClient
+	tracker *Tracker
+}
+
+// Observe reports a resource whose observation was deferred or refused by the
+// rate limit as existing and up to date, leaving its status as it was, so
+// that it is requeued rather than failing to reconcile. The RateLimited
+// condition, not Synced, reports that it was refused.
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if e.tracker.deferred(mg, err) || e.tracker.limited(mg, err) {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	return o, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	if e.tracker.limited(mg, err) {
+		return managed.ExternalCreation{}, nil
+	}
+	return c, err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	if e.tracker.deferred(mg, err) || e.tracker.limited(mg, err) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return u, err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	err := e.ExternalClient.Delete(ctx, mg)
+	if e.tracker.limited(mg, err) {
+		return nil
+	}
+	return err
+}
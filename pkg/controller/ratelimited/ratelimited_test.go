@@ -0,0 +1,282 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimited
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+var errBoom = errors.New("boom")
+
+func rateLimited(retryAfter string) error {
+	return errors.Wrap(&packngo.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{clients.HeaderRetryAfter: []string{retryAfter}},
+			Request:    &http.Request{},
+		},
+	}, "cannot get Device")
+}
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	type want struct {
+		status corev1.ConditionStatus
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		mg       *fake.Managed
+		observe  error
+		reconErr error
+		want     want
+	}{
+		"NotRateLimited": {
+			mg:   &fake.Managed{},
+			want: want{status: corev1.ConditionUnknown, result: reconcile.Result{Requeue: true}},
+		},
+		"RateLimited": {
+			mg:      &fake.Managed{},
+			observe: rateLimited("30"),
+			want: want{
+				status: corev1.ConditionTrue,
+				result: reconcile.Result{RequeueAfter: 30 * time.Second},
+			},
+		},
+		"NoLongerRateLimited": {
+			mg: func() *fake.Managed {
+				mg := &fake.Managed{}
+				mg.SetConditions(RateLimited(now))
+				return mg
+			}(),
+			observe: errBoom,
+			want:    want{status: corev1.ConditionFalse, result: reconcile.Result{Requeue: true}, err: errBoom},
+		},
+		"ReconcileFailed": {
+			mg:       &fake.Managed{},
+			observe:  rateLimited("30"),
+			reconErr: errBoom,
+			want: want{
+				status: corev1.ConditionTrue,
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := NewTracker()
+			tr.now = func() time.Time { return now }
+			tc.mg.SetName("example")
+
+			conn := tr.NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+						return managed.ExternalObservation{}, tc.observe
+					},
+				}, nil
+			}))
+			ec, _ := conn.Connect(context.Background(), tc.mg)
+			_, err := ec.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.mg.GetCondition(TypeRateLimited).Status); diff != "" {
+				t.Errorf("GetCondition(...): -want status, +got:\n%s", diff)
+			}
+
+			r := tr.NewReconciler(reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{Requeue: true}, tc.reconErr
+			}))
+			got, _ := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "example"}})
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("Reconcile(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRateLimitedChanges(t *testing.T) {
+	tr := NewTracker()
+	mg := &fake.Managed{}
+	mg.SetName("example")
+	conn := tr.NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return managed.ExternalClientFns{
+			CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+				return managed.ExternalCreation{}, rateLimited("30")
+			},
+			UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+				return managed.ExternalUpdate{}, rateLimited("30")
+			},
+			DeleteFn: func(_ context.Context, _ resource.Managed) error {
+				return rateLimited("30")
+			},
+		}, nil
+	}))
+	ec, _ := conn.Connect(context.Background(), mg)
+
+	// Changes refused by the rate limit are not reported as failed
+	// reconciles, which would set Synced to ReconcileError.
+	if _, err := ec.Create(context.Background(), mg); err != nil {
+		t.Errorf("Create(...): unexpected error: %s", err)
+	}
+	if _, err := ec.Update(context.Background(), mg); err != nil {
+		t.Errorf("Update(...): unexpected error: %s", err)
+	}
+	if err := ec.Delete(context.Background(), mg); err != nil {
+		t.Errorf("Delete(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(corev1.ConditionTrue, mg.GetCondition(TypeRateLimited).Status); diff != "" {
+		t.Errorf("GetCondition(...): -want status, +got:\n%s", diff)
+	}
+}
+
+// reserved returns a Throttler whose request budget is within the low
+// priority reserve until the rate limit resets in an hour.
+func reserved() *clients.Throttler {
+	th := clients.NewThrottler()
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set(clients.HeaderRateLimit, "100")
+	resp.Header.Set(clients.HeaderRateRemaining, "10")
+	resp.Header.Set(clients.HeaderRateReset, strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	th.Observe(resp)
+	return th
+}
+
+func TestLowPriority(t *testing.T) {
+	th := reserved()
+
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		mg       *fake.Managed
+		deferred bool
+	}{
+		"Steady": {
+			mg: func() *fake.Managed {
+				mg := &fake.Managed{}
+				mg.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+				return mg
+			}(),
+			deferred: true,
+		},
+		"Creating": {
+			mg: func() *fake.Managed {
+				mg := &fake.Managed{}
+				mg.SetConditions(xpv1.Creating(), xpv1.ReconcileSuccess())
+				return mg
+			}(),
+		},
+		"FailedToReconcile": {
+			mg: func() *fake.Managed {
+				mg := &fake.Managed{}
+				mg.SetConditions(xpv1.Available(), xpv1.ReconcileError(errBoom))
+				return mg
+			}(),
+			deferred: true,
+		},
+		"Deleted": {
+			mg: func() *fake.Managed {
+				mg := &fake.Managed{}
+				mg.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+				mg.SetDeletionTimestamp(&now)
+				return mg
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var connected context.Context
+			conn := NewTracker().NewConnecter(managed.ExternalConnectorFn(func(ctx context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				connected = ctx
+				return managed.ExternalClientFns{}, nil
+			}))
+			if _, err := conn.Connect(context.Background(), tc.mg); err != nil {
+				t.Fatalf("Connect(...): unexpected error: %s", err)
+			}
+			deferred := th.Wait(connected) != nil
+			if diff := cmp.Diff(tc.deferred, deferred); diff != "" {
+				t.Errorf("Wait(...): -want deferred, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeferred(t *testing.T) {
+	th := reserved()
+	mg := &fake.Managed{}
+	mg.SetName("example")
+	mg.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+
+	tr := NewTracker()
+	conn := tr.NewConnecter(managed.ExternalConnectorFn(func(ctx context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return managed.ExternalClientFns{
+			ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{}, errors.Wrap(th.Wait(ctx), "cannot get Device")
+			},
+		}, nil
+	}))
+	r := tr.NewReconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		ec, err := conn.Connect(ctx, mg)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		_, err = ec.Observe(ctx, mg)
+		return reconcile.Result{}, err
+	}))
+
+	// A steady resource is deferred by every reconcile until the rate limit
+	// resets, not only by the first.
+	for i := 0; i < 2; i++ {
+		got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "example"}})
+		if err != nil {
+			t.Fatalf("Reconcile(...) %d: %s", i, err)
+		}
+		if got.RequeueAfter < 59*time.Minute {
+			t.Errorf("Reconcile(...) %d: want requeue after the rate limit resets, got %+v", i, got)
+		}
+		if diff := cmp.Diff(xpv1.ReconcileSuccess(), mg.GetCondition(xpv1.TypeSynced), test.EquateConditions()); diff != "" {
+			t.Errorf("Reconcile(...) %d: -want synced, +got:\n%s", i, diff)
+		}
+		if diff := cmp.Diff(corev1.ConditionUnknown, mg.GetCondition(TypeRateLimited).Status); diff != "" {
+			t.Errorf("Reconcile(...) %d: -want rate limited status, +got:\n%s", i, diff)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readonly observes, but never creates, updates or deletes, the
+// external resources of managed resources whose ProviderConfig is read-only.
+// It suits audit and reporting installs whose credentials are only allowed to
+// read the Equinix Metal API.
+package readonly
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+// Error strings.
+const (
+	errGetProviderConfig = "cannot get ProviderConfig"
+	errCreateReadOnly    = "external resource does not exist and cannot be created with a read-only ProviderConfig"
+)
+
+// IsReadOnly returns true if the ProviderConfig of the supplied managed
+// resource is read-only.
+func IsReadOnly(ctx context.Context, kube client.Reader, mg resource.Managed) (bool, error) {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		return false, nil
+	}
+	pc := &v1beta1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return false, errors.Wrap(err, errGetProviderConfig)
+	}
+	return pc.Spec.ReadOnly, nil
+}
+
+// NewConnecter returns an ExternalConnecter whose external clients only
+// observe the external resources of managed resources with a read-only
+// ProviderConfig, or of all managed resources if all is true.
+func NewConnecter(c managed.ExternalConnecter, kube client.Reader, all bool) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c, kube: kube, all: all}
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+	kube client.Reader
+	all  bool
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	if c.all {
+		return &external{ExternalClient: ec}, nil
+	}
+	ro, err := IsReadOnly(ctx, c.kube, mg)
+	if err != nil || !ro {
+		return ec, err
+	}
+	return &external{ExternalClient: ec}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+}
+
+// Observe reports existing external resources as up to date, so that they
+// are never updated, and fails for external resources that do not exist. The
+// external resources of deleted managed resources are reported gone, so that
+// their finalizers are removed while the external resources are left in
+// place.
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+	if meta.WasDeleted(mg) {
+		o.ResourceExists = false
+		return o, nil
+	}
+	if !o.ResourceExists {
+		return o, errors.New(errCreateReadOnly)
+	}
+	o.ResourceUpToDate = o.ResourceExists
+	return o, nil
+}
+
+func (e *external) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, errors.New(errCreateReadOnly)
+}
+
+func (e *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete leaves the external resource in place, as if the deletion policy
+// were Orphan.
+func (e *external) Delete(_ context.Context, _ resource.Managed) error {
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readonly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+var errBoom = errors.New("boom")
+
+func TestExternal(t *testing.T) {
+	withConfig := func(deleted bool) *fake.Managed {
+		mg := &fake.Managed{}
+		mg.SetProviderConfigReference(&xpv1.Reference{Name: "audit"})
+		if deleted {
+			now := metav1.Now()
+			mg.SetDeletionTimestamp(&now)
+		}
+		return mg
+	}
+
+	type want struct {
+		o       managed.ExternalObservation
+		connect error
+		observe error
+		create  error
+		deleted bool
+	}
+
+	cases := map[string]struct {
+		mg       resource.Managed
+		readOnly bool
+		all      bool
+		getErr   error
+		exists   bool
+		want     want
+	}{
+		"ReadWrite": {
+			mg:     withConfig(false),
+			exists: true,
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true},
+				deleted: true,
+			},
+		},
+		"ReadOnlyExisting": {
+			mg:       withConfig(false),
+			readOnly: true,
+			exists:   true,
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				create: errors.New(errCreateReadOnly),
+			},
+		},
+		"ReadOnlyMissing": {
+			mg:       withConfig(false),
+			readOnly: true,
+			want: want{
+				observe: errors.New(errCreateReadOnly),
+				create:  errors.New(errCreateReadOnly),
+			},
+		},
+		"ReadOnlyMissingWhileDeleting": {
+			mg:       withConfig(true),
+			readOnly: true,
+			want: want{
+				create: errors.New(errCreateReadOnly),
+			},
+		},
+		"ReadOnlyExistingWhileDeleting": {
+			mg:       withConfig(true),
+			readOnly: true,
+			exists:   true,
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: false},
+				create: errors.New(errCreateReadOnly),
+			},
+		},
+		"AllReadOnly": {
+			mg:     withConfig(false),
+			all:    true,
+			getErr: errBoom,
+			exists: true,
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				create: errors.New(errCreateReadOnly),
+			},
+		},
+		"GetProviderConfigFailed": {
+			mg:     withConfig(false),
+			getErr: errBoom,
+			want: want{
+				connect: errors.Wrap(errBoom, errGetProviderConfig),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			ec := managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{ResourceExists: tc.exists}, nil
+				},
+				CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+					return managed.ExternalCreation{}, nil
+				},
+				DeleteFn: func(_ context.Context, _ resource.Managed) error {
+					deleted = true
+					return nil
+				},
+			}
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if tc.getErr != nil {
+						return tc.getErr
+					}
+					obj.(*v1beta1.ProviderConfig).Spec.ReadOnly = tc.readOnly
+					return nil
+				},
+			}
+			c := NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return ec, nil
+			}), kube, tc.all)
+
+			e, err := c.Connect(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.connect, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Connect(...): -want error, +got error:\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			o, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.observe, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("Observe(...): -want, +got:\n%s", diff)
+			}
+			_, err = e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.create, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Create(...): -want error, +got error:\n%s", diff)
+			}
+			if err := e.Delete(context.Background(), tc.mg); err != nil {
+				t.Errorf("Delete(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("Delete(...): -want deleted, +got deleted:\n%s", diff)
+			}
+		})
+	}
+}
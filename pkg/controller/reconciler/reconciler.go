@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reconciler builds the reconcilers of the managed resources of the
+// provider, so that the external clients of every kind are wrapped alike.
+package reconciler
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/apierror"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/managementpolicy"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/observecache"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/plan"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/ratelimited"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/readonly"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/redact"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/synced"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/tracing"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/features"
+)
+
+// NewManaged returns a Reconciler of the supplied kind of managed resource
+// that connects to Equinix Metal with the supplied ExternalConnecter. From
+// the innermost, its external clients redact credentials from errors,
+// classify API errors, honor management policies, plan mode and read-only
+// ProviderConfigs, record the sync status of the resources, cache
+// observations, and are traced and rate limited. The supplied options
+// configure the managed reconciler further, for example its initializers.
+func NewManaged(mgr ctrl.Manager, o options.Options, name string, kind schema.GroupVersionKind, conn managed.ExternalConnecter, recorder event.Recorder, opts ...managed.ReconcilerOption) reconcile.Reconciler {
+	conn = apierror.NewConnecter(redact.NewConnecter(conn))
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		conn = managementpolicy.NewConnecter(conn)
+	}
+	if o.Plan {
+		conn = plan.NewConnecter(conn, recorder, o.Logger.WithValues("controller", name))
+	}
+	conn = synced.NewConnecter(readonly.NewConnecter(conn, mgr.GetClient(), o.ReadOnly))
+	conn = observecache.New(o.ObserveCacheTTL).NewConnecter(conn)
+	limited := ratelimited.NewTracker()
+	conn = limited.NewConnecter(tracing.NewConnecter(conn, kind.Kind))
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(kind), append([]managed.ReconcilerOption{
+		managed.WithExternalConnecter(conn),
+		managed.WithConnectionPublishers(),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithRecorder(recorder),
+	}, opts...)...)
+	return o.Drainer.NewReconciler(tracing.NewReconciler(limited.NewReconciler(r), kind.Kind))
+}
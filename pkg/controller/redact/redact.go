@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redact keeps the sensitive values of managed resources, such as the
+// userdata of a Device, out of the errors their external clients return, and
+// so out of the logs, events and conditions those errors end up in.
+package redact
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// NewConnecter returns an ExternalConnecter whose errors, and the errors and
+// condition messages of whose external clients, have the sensitive values of
+// the managed resource they concern redacted.
+func NewConnecter(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c}
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, redact(mg, err)
+	}
+	return &external{ExternalClient: ec}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, redact(mg, err)
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, redact(mg, err)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, redact(mg, err)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	return redact(mg, e.ExternalClient.Delete(ctx, mg))
+}
+
+// redact redacts the sensitive values of the supplied managed resource from
+// the supplied error and from the messages of its conditions.
+func redact(mg resource.Managed, err error) error {
+	values := clients.SensitiveValues(mg)
+	for _, ct := range []xpv1.ConditionType{xpv1.TypeReady, xpv1.TypeSynced} {
+		c := mg.GetCondition(ct)
+		if m := clients.Redact(c.Message, values...); m != c.Message {
+			mg.SetConditions(c.WithMessage(m))
+		}
+	}
+	return clients.RedactError(err, values...)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// AnnotationKeyAction is the annotation used to request an action, such as a
+// reboot, on the device of a Device.
+const AnnotationKeyAction = "metal.equinix.com/action"
+
+// An action is performed on a device by requesting an Equinix Metal device
+// action of a type, and is complete once the device reaches a state.
+type action struct {
+	apiType string
+	state   string
+}
+
+// actions are the actions that can be requested with the action annotation.
+// Adding an action is a matter of adding it here.
+var actions = map[string]action{
+	v1alpha2.ActionReboot:    {apiType: "reboot", state: v1alpha2.StateActive},
+	v1alpha2.ActionPowerOff:  {apiType: "power_off", state: v1alpha2.StateInactive},
+	v1alpha2.ActionPowerOn:   {apiType: "power_on", state: v1alpha2.StateActive},
+	v1alpha2.ActionReinstall: {apiType: "reinstall", state: v1alpha2.StateActive},
+	v1alpha2.ActionRescue:    {apiType: "rescue", state: v1alpha2.StateActive},
+}
+
+// requestedAction returns the action requested with the action annotation of
+// the supplied Device, if any.
+func requestedAction(d *v1alpha2.Device) (string, bool) {
+	name, ok := d.GetAnnotations()[AnnotationKeyAction]
+	return name, ok
+}
+
+// act performs the action requested with the action annotation of the
+// supplied Device. The annotation is removed before the action is requested,
+// so that an action is requested at most once; a failed action is not
+// retried unless it is requested again.
+func (e *external) act(ctx context.Context, d *v1alpha2.Device, name string) error {
+	// Updating the Device overwrites its status with the stored one.
+	status := d.Status.DeepCopy()
+	meta.RemoveAnnotations(d, AnnotationKeyAction)
+	if err := e.kube.Update(ctx, d); err != nil {
+		return errors.Wrap(err, errManagedUpdateFailed)
+	}
+	d.Status = *status
+
+	a, ok := actions[name]
+	if !ok {
+		setAction(d, name, v1alpha2.ActionFailed, fmt.Sprintf(errUnknownActionFmt, name))
+		return errors.Errorf(errUnknownActionFmt, name)
+	}
+
+	e.log.Info("Performing Device action", "id", meta.GetExternalName(d), "action", name)
+	_, err := e.client.PerformAction(meta.GetExternalName(d), a.apiType)
+	packetclient.RecordAPIError(e.recorder, d, errPerformAction, err)
+	recordRequestID(d, err)
+	if err != nil {
+		setAction(d, name, v1alpha2.ActionFailed, err.Error())
+		return errors.Wrap(err, errPerformAction)
+	}
+	setAction(d, name, v1alpha2.ActionInProgress, fmt.Sprintf("Requested %s, waiting for the device to be %s", name, a.state))
+	e.recorder.Event(d, event.Normal(packetclient.ReasonAction, fmt.Sprintf("Requested %s of Device", name)))
+	return nil
+}
+
+// progress completes the action in progress on the supplied Device once the
+// supplied device reached the state the action leads to.
+func (e *external) progress(d *v1alpha2.Device, device *packngo.Device) {
+	s := d.Status.AtProvider.Action
+	if s == nil || s.Phase != v1alpha2.ActionInProgress {
+		return
+	}
+	if a, ok := actions[s.Name]; !ok || device.State != a.state {
+		return
+	}
+	setAction(d, s.Name, v1alpha2.ActionSucceeded, fmt.Sprintf("Device is %s", device.State))
+	e.recorder.Event(d, event.Normal(packetclient.ReasonAction, fmt.Sprintf("Completed %s of Device", s.Name)))
+}
+
+// setAction sets the status of the action of the supplied Device.
+func setAction(d *v1alpha2.Device, name, phase, result string) {
+	now := metav1.NewTime(time.Now())
+	d.Status.AtProvider.Action = &v1alpha2.DeviceActionStatus{
+		Name:               name,
+		Phase:              phase,
+		Result:             result,
+		LastTransitionTime: &now,
+	}
+}
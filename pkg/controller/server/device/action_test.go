@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device/fake"
+)
+
+func withAction(name string) deviceModifier {
+	return func(i *v1alpha2.Device) { meta.AddAnnotations(i, map[string]string{AnnotationKeyAction: name}) }
+}
+
+func withActionStatus(name, phase, result string) deviceModifier {
+	return func(i *v1alpha2.Device) {
+		i.Status.AtProvider.Action = &v1alpha2.DeviceActionStatus{Name: name, Phase: phase, Result: result}
+	}
+}
+
+// ignoreActionTime ignores when actions changed phase.
+var ignoreActionTime = cmpopts.IgnoreFields(v1alpha2.DeviceActionStatus{}, "LastTransitionTime")
+
+func TestAct(t *testing.T) {
+	type want struct {
+		mg      *v1alpha2.Device
+		apiType string
+		err     error
+	}
+
+	cases := map[string]struct {
+		kube    *test.MockClient
+		perform func(deviceID, actionType string) (*packngo.Response, error)
+		mg      *v1alpha2.Device
+		want    want
+	}{
+		"PerformedAction": {
+			kube:    &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			perform: func(string, string) (*packngo.Response, error) { return nil, nil },
+			mg:      device(withAction(v1alpha2.ActionPowerOff)),
+			want: want{
+				mg:      device(withActionStatus(v1alpha2.ActionPowerOff, v1alpha2.ActionInProgress, "Requested power-off, waiting for the device to be inactive")),
+				apiType: "power_off",
+			},
+		},
+		"UnknownAction": {
+			kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			mg:   device(withAction("explode")),
+			want: want{
+				mg:  device(withActionStatus("explode", v1alpha2.ActionFailed, `unknown Device action "explode"`)),
+				err: errors.Errorf(errUnknownActionFmt, "explode"),
+			},
+		},
+		"FailedToPerformAction": {
+			kube:    &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			perform: func(string, string) (*packngo.Response, error) { return nil, errorBoom },
+			mg:      device(withAction(v1alpha2.ActionReboot)),
+			want: want{
+				mg:      device(withActionStatus(v1alpha2.ActionReboot, v1alpha2.ActionFailed, errorBoom.Error())),
+				apiType: "reboot",
+				err:     errors.Wrap(errorBoom, errPerformAction),
+			},
+		},
+		"FailedToRemoveAnnotation": {
+			kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errorBoom)},
+			mg:   device(withAction(v1alpha2.ActionReboot)),
+			want: want{
+				mg:  device(),
+				err: errors.Wrap(errorBoom, errManagedUpdateFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var apiType string
+			e := &external{
+				kube:     tc.kube,
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client: &fake.MockClient{
+					PerformActionFunc: func(deviceID, actionType string) (*packngo.Response, error) {
+						apiType = actionType
+						return tc.perform(deviceID, actionType)
+					},
+				},
+			}
+			_, err := e.Update(context.Background(), tc.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("e.Update(): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.apiType, apiType); diff != "" {
+				t.Errorf("PerformAction(): -want type, +got type:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg, test.EquateConditions(), ignoreActionTime); diff != "" {
+				t.Errorf("e.Update(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	cases := map[string]struct {
+		mg    *v1alpha2.Device
+		state string
+		want  *v1alpha2.Device
+	}{
+		"ReachedState": {
+			mg:    device(withActionStatus(v1alpha2.ActionPowerOff, v1alpha2.ActionInProgress, "Requested")),
+			state: v1alpha2.StateInactive,
+			want:  device(withActionStatus(v1alpha2.ActionPowerOff, v1alpha2.ActionSucceeded, "Device is inactive")),
+		},
+		"NotReachedState": {
+			mg:    device(withActionStatus(v1alpha2.ActionPowerOff, v1alpha2.ActionInProgress, "Requested")),
+			state: v1alpha2.StatePoweringOff,
+			want:  device(withActionStatus(v1alpha2.ActionPowerOff, v1alpha2.ActionInProgress, "Requested")),
+		},
+		"NotInProgress": {
+			mg:    device(withActionStatus(v1alpha2.ActionReboot, v1alpha2.ActionFailed, "boom")),
+			state: v1alpha2.StateActive,
+			want:  device(withActionStatus(v1alpha2.ActionReboot, v1alpha2.ActionFailed, "boom")),
+		},
+		"NoAction": {
+			mg:    device(),
+			state: v1alpha2.StateActive,
+			want:  device(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder()}
+			e.progress(tc.mg, &packngo.Device{State: tc.state})
+
+			if diff := cmp.Diff(tc.want, tc.mg, ignoreActionTime); diff != "" {
+				t.Errorf("e.progress(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
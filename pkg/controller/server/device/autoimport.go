@@ -0,0 +1,194 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/managementpolicy"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/importer"
+)
+
+const (
+	errListProviderConfigs = "cannot list ProviderConfigs"
+	errListAliasDevices    = "cannot list Devices of the alias API group"
+	errAutoImport          = "cannot import the Devices of a ProviderConfig"
+	errCreateImported      = "cannot create imported Device"
+	errDeleteImported      = "cannot delete imported Device"
+)
+
+// LabelKeyAutoImport is the label of the Devices imported by the auto import
+// of a ProviderConfig, whose name is its value.
+const LabelKeyAutoImport = "metal.equinix.com/auto-import"
+
+// An autoImporter creates a Device managed resource for each device carrying
+// the auto import tag of a ProviderConfig once per poll interval, and deletes
+// those it created whose device no longer exists or, if their deletion policy
+// is Orphan, no longer carries the tag.
+type autoImporter struct {
+	kube     client.Client
+	interval time.Duration
+	log      logging.Logger
+
+	credentialsFn func(ctx context.Context, c client.Client, pc *packetv1beta1.ProviderConfig) (*clients.Credentials, error)
+	newClientFn   func(ctx context.Context, config *clients.Credentials) (devicesclient.ClientWithDefaults, error)
+}
+
+// Start imports the Devices of every ProviderConfig with an auto import until
+// the supplied context is done.
+func (a *autoImporter) Start(ctx context.Context) error {
+	t := time.NewTicker(a.interval)
+	defer t.Stop()
+	for {
+		a.importAll(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// importAll imports the Devices of every ProviderConfig with an auto import.
+func (a *autoImporter) importAll(ctx context.Context) {
+	pcs := &packetv1beta1.ProviderConfigList{}
+	if err := a.kube.List(ctx, pcs); err != nil {
+		a.log.Debug(errListProviderConfigs, "error", err)
+		return
+	}
+	for i := range pcs.Items {
+		pc := &pcs.Items[i]
+		if pc.Spec.AutoImport == nil || pc.Spec.AutoImport.Tag == "" {
+			continue
+		}
+		if err := a.importTagged(ctx, pc); err != nil {
+			a.log.Debug(errAutoImport, "providerConfig", pc.GetName(), "error", err)
+		}
+	}
+}
+
+// importTagged imports the Devices of the project of the supplied
+// ProviderConfig that carry its auto import tag.
+func (a *autoImporter) importTagged(ctx context.Context, pc *packetv1beta1.ProviderConfig) error { //nolint:gocyclo
+	cfg, err := a.credentialsFn(ctx, a.kube, pc)
+	if err != nil {
+		return errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	cl, err := a.newClientFn(clients.WithController(ctx, managed.ControllerName(v1alpha2.DeviceGroupKind)), cfg)
+	if err != nil {
+		return errors.Wrap(err, errNewClient)
+	}
+	listed, _, err := cl.List(cl.GetProjectID(clients.CredentialProjectID), devicesclient.ImportListOptions())
+	if err != nil {
+		return errors.Wrap(err, errListProjectDevices)
+	}
+
+	existing := &v1alpha2.DeviceList{}
+	if err := a.kube.List(ctx, existing); err != nil {
+		return errors.Wrap(err, errListDevices)
+	}
+	aliases := &emserverv1beta1.DeviceList{}
+	if err := a.kube.List(ctx, aliases); err != nil {
+		return errors.Wrap(err, errListAliasDevices)
+	}
+	managedIDs := map[string]bool{}
+	names := make([]string, 0, len(existing.Items)+len(aliases.Items))
+	for i := range existing.Items {
+		managedIDs[meta.GetExternalName(&existing.Items[i])] = true
+		names = append(names, existing.Items[i].GetName())
+	}
+	for i := range aliases.Items {
+		managedIDs[meta.GetExternalName(&aliases.Items[i])] = true
+		names = append(names, aliases.Items[i].GetName())
+	}
+
+	ai := pc.Spec.AutoImport
+	deletionPolicy := ai.DeletionPolicy
+	if deletionPolicy == "" {
+		deletionPolicy = xpv1.DeletionOrphan
+	}
+	policies := ai.ManagementPolicies
+	if policies == "" {
+		policies = managementpolicy.ObserveOnly
+	}
+	im := importer.New(importer.Options{ProviderConfig: pc.GetName(), DeletionPolicy: deletionPolicy})
+	im.Reserve(names...)
+
+	exists, tagged := map[string]bool{}, map[string]bool{}
+	for _, d := range listed {
+		exists[d.ID] = true
+		if !hasTag(d.Tags, ai.Tag) {
+			continue
+		}
+		tagged[d.ID] = true
+		// Devices created by the provider are managed, even if their
+		// managed resource does not record their ID yet.
+		if managedIDs[d.ID] || len(devicesclient.UserTags(d.Tags)) != len(d.Tags) {
+			continue
+		}
+		mg := im.Device(d)
+		meta.AddAnnotations(mg, map[string]string{managementpolicy.AnnotationKeyManagementPolicies: policies})
+		meta.AddLabels(mg, map[string]string{LabelKeyAutoImport: pc.GetName()})
+		a.log.Info("Importing Device", "providerConfig", pc.GetName(), "id", d.ID, "name", mg.GetName())
+		if err := a.kube.Create(ctx, mg); err != nil && !kerrors.IsAlreadyExists(err) {
+			return errors.Wrap(err, errCreateImported)
+		}
+	}
+
+	for i := range existing.Items {
+		d := &existing.Items[i]
+		id := meta.GetExternalName(d)
+		if d.GetLabels()[LabelKeyAutoImport] != pc.GetName() || tagged[id] || meta.WasDeleted(d) {
+			continue
+		}
+		// Deleting a Device whose deletion policy is Delete would delete its
+		// device, so it is only deleted once its device is gone.
+		if exists[id] && d.GetDeletionPolicy() != xpv1.DeletionOrphan {
+			continue
+		}
+		a.log.Info("Deleting imported Device that is no longer tagged", "providerConfig", pc.GetName(), "id", id, "name", d.GetName())
+		if err := a.kube.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteImported)
+		}
+	}
+	return nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/managementpolicy"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+func TestAutoImport(t *testing.T) {
+	srv := packettest.NewMetalServer()
+	defer srv.Close()
+	defer srv.Install()()
+
+	srv.AddDevice(packngo.Device{Hostname: "tagged", Tags: []string{"import"}, Plan: &packngo.Plan{ID: "e69c0169-4726-46ea-98f1-939c9e8a3607", Slug: "c3.small.x86"}})
+	srv.AddDevice(packngo.Device{Hostname: "untagged"})
+	managedID := srv.AddDevice(packngo.Device{Hostname: "managed", Tags: []string{"import"}})
+	srv.AddDevice(packngo.Device{Hostname: "created", Tags: []string{"import", devicesclient.UIDTag("some-uid")}})
+	retainedID := srv.AddDevice(packngo.Device{Hostname: "retained"})
+
+	managedDevice := func(name, id string, labels map[string]string, p xpv1.DeletionPolicy) v1alpha2.Device {
+		d := v1alpha2.Device{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		d.SetDeletionPolicy(p)
+		meta.SetExternalName(&d, id)
+		return d
+	}
+	imported := map[string]string{LabelKeyAutoImport: "default"}
+
+	created, deleted := []string{}, []string{}
+	a := &autoImporter{
+		kube: &test.MockClient{
+			MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+				switch l := obj.(type) {
+				case *packetv1beta1.ProviderConfigList:
+					pc := packetv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+					pc.Spec.AutoImport = &packetv1beta1.AutoImport{Tag: "import"}
+					l.Items = []packetv1beta1.ProviderConfig{pc, {ObjectMeta: metav1.ObjectMeta{Name: "other"}}}
+				case *v1alpha2.DeviceList:
+					l.Items = []v1alpha2.Device{
+						managedDevice("managed", managedID, nil, xpv1.DeletionDelete),
+						managedDevice("gone", "c8b4f2d6-8ef6-4d1e-b5e9-0e3e7a6f6a1d", imported, xpv1.DeletionOrphan),
+						managedDevice("retained", retainedID, imported, xpv1.DeletionDelete),
+					}
+				case *emserverv1beta1.DeviceList:
+				}
+				return nil
+			},
+			MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+				d := obj.(*v1alpha2.Device)
+				want := map[string]string{
+					managementpolicy.AnnotationKeyManagementPolicies: managementpolicy.ObserveOnly,
+					meta.AnnotationKeyExternalName:                   meta.GetExternalName(d),
+				}
+				if diff := cmp.Diff(want, d.GetAnnotations()); diff != "" {
+					t.Errorf("Create(...): annotations: -want, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff(imported, d.GetLabels()); diff != "" {
+					t.Errorf("Create(...): labels: -want, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff("c3.small.x86", d.Spec.ForProvider.Plan); diff != "" {
+					t.Errorf("Create(...): plan: -want, +got:\n%s", diff)
+				}
+				if diff := cmp.Diff(xpv1.DeletionOrphan, d.GetDeletionPolicy()); diff != "" {
+					t.Errorf("Create(...): deletion policy: -want, +got:\n%s", diff)
+				}
+				created = append(created, d.GetName())
+				return nil
+			},
+			MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+				deleted = append(deleted, obj.GetName())
+				return nil
+			},
+		},
+		log: logging.NewNopLogger(),
+		credentialsFn: func(_ context.Context, _ client.Client, _ *packetv1beta1.ProviderConfig) (*clients.Credentials, error) {
+			return srv.Credentials(), nil
+		},
+		newClientFn: devicesclient.NewClient,
+	}
+	a.importAll(context.Background())
+
+	if diff := cmp.Diff([]string{"tagged"}, created); diff != "" {
+		t.Errorf("importAll(...): created Devices: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"gone"}, deleted); diff != "" {
+		t.Errorf("importAll(...): deleted Devices: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+)
+
+const errListProjectDevices = "cannot list the Devices of a project"
+
+// A batchObserver lists the Devices of every project used by a managed Device
+// once per poll interval into the Device cache, so that the Devices of large
+// fleets are observed from one paginated list per project instead of a
+// request each. Managed Devices whose listed state differs from their
+// observed state are queued to be reconciled at once rather than at their
+// next poll.
+type batchObserver struct {
+	kube     client.Client
+	cache    *devicesclient.Cache
+	interval time.Duration
+	changed  chan<- ctrlevent.GenericEvent
+	log      logging.Logger
+
+	authFn      func(ctx context.Context, c client.Client, mg resource.Managed) (*clients.Credentials, error)
+	newClientFn func(ctx context.Context, config *clients.Credentials) (devicesclient.ClientWithDefaults, error)
+}
+
+// Start lists the Devices of every project until the supplied context is
+// done.
+func (b *batchObserver) Start(ctx context.Context) error {
+	t := time.NewTicker(b.interval)
+	defer t.Stop()
+	for {
+		b.observe(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// observe lists the Devices of every project used by a managed Device. The
+// managed Devices are grouped by ProviderConfig, whose credentials determine
+// the project that is listed.
+func (b *batchObserver) observe(ctx context.Context) {
+	l := &v1alpha2.DeviceList{}
+	if err := b.kube.List(ctx, l); err != nil {
+		b.log.Debug(errListDevices, "error", err)
+		return
+	}
+	byConfig := map[string][]*v1alpha2.Device{}
+	for i := range l.Items {
+		d := &l.Items[i]
+		if ref := d.GetProviderConfigReference(); ref != nil {
+			byConfig[ref.Name] = append(byConfig[ref.Name], d)
+		}
+	}
+	for name, devices := range byConfig {
+		if err := b.observeProject(ctx, devices); err != nil {
+			b.log.Debug(errListProjectDevices, "providerConfig", name, "error", err)
+		}
+	}
+}
+
+// observeProject lists the Devices of the project of the supplied managed
+// Devices, which must share a ProviderConfig, and queues those whose state
+// changed.
+func (b *batchObserver) observeProject(ctx context.Context, devices []*v1alpha2.Device) error {
+	cfg, err := b.authFn(ctx, b.kube, devices[0])
+	if err != nil {
+		return errors.Wrap(err, errGetProviderConfigSecret)
+	}
+	cl, err := b.newClientFn(clients.WithController(ctx, managed.ControllerName(v1alpha2.DeviceGroupKind)), cfg)
+	if err != nil {
+		return errors.Wrap(err, errNewClient)
+	}
+	listed, err := b.cache.Refresh(cl, clients.CredentialsKey(cfg), cl.GetProjectID(clients.CredentialProjectID))
+	if err != nil {
+		return errors.Wrap(err, errListProjectDevices)
+	}
+
+	states := make(map[string]string, len(listed))
+	for _, d := range listed {
+		states[d.ID] = d.State
+	}
+	for _, d := range devices {
+		s, ok := states[meta.GetExternalName(d)]
+		if !ok || s == d.Status.AtProvider.State {
+			continue
+		}
+		select {
+		case b.changed <- ctrlevent.GenericEvent{Object: d}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+func TestBatchObserve(t *testing.T) {
+	srv := packettest.NewMetalServer()
+	defer srv.Close()
+	defer srv.Install()()
+
+	active := srv.AddDevice(packngo.Device{Hostname: "active", State: v1alpha2.StateActive})
+	provisioned := srv.AddDevice(packngo.Device{Hostname: "provisioned", State: v1alpha2.StateActive})
+
+	managedDevice := func(name, id, state string) v1alpha2.Device {
+		d := v1alpha2.Device{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		d.SetProviderConfigReference(&xpv1.Reference{Name: providerName})
+		meta.SetExternalName(&d, id)
+		d.Status.AtProvider.State = state
+		return d
+	}
+
+	changed := make(chan ctrlevent.GenericEvent, 3)
+	cache := devicesclient.NewCache(time.Hour)
+	b := &batchObserver{
+		kube: &test.MockClient{
+			MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+				obj.(*v1alpha2.DeviceList).Items = []v1alpha2.Device{
+					managedDevice("active", active, v1alpha2.StateActive),
+					managedDevice("provisioned", provisioned, v1alpha2.StateProvisioning),
+					managedDevice("created", "my-cool-device", ""),
+				}
+				return nil
+			},
+		},
+		cache:   cache,
+		changed: changed,
+		log:     logging.NewNopLogger(),
+		authFn: func(_ context.Context, _ client.Client, _ resource.Managed) (*clients.Credentials, error) {
+			return srv.Credentials(), nil
+		},
+		newClientFn: devicesclient.NewClient,
+	}
+	b.observe(context.Background())
+	close(changed)
+
+	if diff := cmp.Diff(1, srv.Calls("GET", "/projects/{id}/devices")); diff != "" {
+		t.Errorf("observe(...): project lists: -want, +got:\n%s", diff)
+	}
+
+	queued := []string{}
+	for e := range changed {
+		queued = append(queued, e.Object.GetName())
+	}
+	if diff := cmp.Diff([]string{"provisioned"}, queued); diff != "" {
+		t.Errorf("observe(...): queued Devices: -want, +got:\n%s", diff)
+	}
+
+	// The listed Devices are served from the cache.
+	cl, err := devicesclient.NewClient(context.Background(), srv.Credentials())
+	if err != nil {
+		t.Fatalf("NewClient(...): %s", err)
+	}
+	if _, ok, err := cache.Get(cl, clients.CredentialsKey(srv.Credentials()), packettest.MetalProjectID, active); !ok || err != nil {
+		t.Errorf("Get(...): want the listed Device, got ok %t and error %v", ok, err)
+	}
+	if diff := cmp.Diff(0, srv.Calls("GET", "/devices/{id}")); diff != "" {
+		t.Errorf("Get(...): Device reads: -want, +got:\n%s", diff)
+	}
+}
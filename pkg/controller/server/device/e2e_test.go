@@ -0,0 +1,99 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+// TestE2E creates, updates and deletes a real Device.
+func TestE2E(t *testing.T) {
+	creds := packettest.E2E(t)
+	ctx := context.Background()
+	c, err := devicesclient.NewClient(ctx, creds)
+	if err != nil {
+		t.Fatalf("NewClient(...): %s", err)
+	}
+	e := &external{
+		kube:     &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+		client:   c,
+		log:      logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+
+	hostname := "crossplane-e2e"
+	billing := "hourly"
+	d := device(func(d *v1alpha2.Device) {
+		meta.SetExternalName(d, "")
+		d.Spec.ForProvider = v1alpha2.DeviceParameters{
+			Hostname:     &hostname,
+			Plan:         packettest.E2EPlan(),
+			Metro:        packettest.E2EMetro(),
+			OS:           packettest.DefaultE2EOS,
+			BillingCycle: &billing,
+			Tags:         []string{"crossplane-e2e"},
+		}
+	})
+
+	if _, err := e.Create(ctx, d); err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+	defer func() {
+		if err := e.Delete(ctx, d); err != nil {
+			t.Errorf("Delete(...): %s", err)
+		}
+	}()
+
+	packettest.Eventually(t, 30*time.Minute, 30*time.Second, func() (bool, error) {
+		o, err := e.Observe(ctx, d)
+		return o.ResourceExists && d.Status.AtProvider.State == v1alpha2.StateActive, err
+	})
+
+	d.Spec.ForProvider.Tags = []string{"crossplane-e2e", "updated"}
+	if _, err := e.Update(ctx, d); err != nil {
+		t.Fatalf("Update(...): %s", err)
+	}
+	o, err := e.Observe(ctx, d)
+	if err != nil {
+		t.Fatalf("Observe(...): %s", err)
+	}
+	if !o.ResourceUpToDate {
+		t.Errorf("Observe(...): want updated Device to be up to date")
+	}
+
+	if err := e.Delete(ctx, d); err != nil {
+		t.Fatalf("Delete(...): %s", err)
+	}
+	packettest.Eventually(t, 10*time.Minute, 15*time.Second, func() (bool, error) {
+		o, err := e.Observe(ctx, d)
+		return !o.ResourceExists, err
+	})
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+// InventoryKey is the key of the device inventory ConfigMap whose value is
+// the JSON encoded inventory.
+const InventoryKey = "devices.json"
+
+const (
+	inventoryControllerName = "inventory/device"
+
+	errConvertAlias     = "cannot convert Device of the alias API group"
+	errMarshalInventory = "cannot marshal device inventory"
+	errGetInventory     = "cannot get device inventory ConfigMap"
+	errCreateInventory  = "cannot create device inventory ConfigMap"
+	errUpdateInventory  = "cannot update device inventory ConfigMap"
+	errSetupInventory   = "cannot setup device inventory controller"
+	errWatchInventory   = "cannot watch Devices for the device inventory"
+	errWatchConfigMap   = "cannot watch the device inventory ConfigMap"
+)
+
+// An InventoryEntry summarizes a managed Device for systems that consume the
+// device inventory, such as DNS automation or a CMDB, without access to the
+// Equinix Metal API.
+type InventoryEntry struct {
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion"`
+	ID         string `json:"id,omitempty"`
+	Hostname   string `json:"hostname,omitempty"`
+	IPv4       string `json:"ipv4,omitempty"`
+	IPv6       string `json:"ipv6,omitempty"`
+	Metro      string `json:"metro,omitempty"`
+	Facility   string `json:"facility,omitempty"`
+	Plan       string `json:"plan,omitempty"`
+	State      string `json:"state,omitempty"`
+}
+
+// An inventory maintains a ConfigMap summarizing all managed Devices, of both
+// API groups. Any change to a Device, or to the ConfigMap itself, reconciles
+// the whole inventory, so that a deleted or edited ConfigMap is restored.
+type inventory struct {
+	kube      client.Client
+	configMap types.NamespacedName
+	log       logging.Logger
+}
+
+// SetupInventory adds a controller that maintains the supplied ConfigMap as
+// an inventory of all managed Devices.
+func SetupInventory(mgr ctrl.Manager, configMap types.NamespacedName, l logging.Logger) error {
+	r := &inventory{
+		kube:      mgr.GetClient(),
+		configMap: configMap,
+		log:       l.WithValues("controller", inventoryControllerName),
+	}
+	c, err := controller.New(inventoryControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return errors.Wrap(err, errSetupInventory)
+	}
+	toInventory := handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: configMap}}
+	})
+	for _, o := range []client.Object{&v1alpha2.Device{}, &emserverv1beta1.Device{}} {
+		if err := c.Watch(&source.Kind{Type: o}, toInventory); err != nil {
+			return errors.Wrap(err, errWatchInventory)
+		}
+	}
+	// NOTE: ConfigMaps are already cached to get the inventory, so watching
+	// them costs no more than filtering out the others.
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, toInventory, isInventory(configMap)); err != nil {
+		return errors.Wrap(err, errWatchConfigMap)
+	}
+	return nil
+}
+
+// isInventory returns a predicate accepting only the supplied ConfigMap.
+func isInventory(configMap types.NamespacedName) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetNamespace() == configMap.Namespace && o.GetName() == configMap.Name
+	})
+}
+
+// Reconcile writes the current inventory of managed Devices to the ConfigMap,
+// creating it if it does not exist. The ConfigMap is only updated when the
+// inventory changed.
+func (i *inventory) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	entries, err := i.entries(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errMarshalInventory)
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = i.kube.Get(ctx, i.configMap, cm)
+	if kerrors.IsNotFound(err) {
+		cm.SetNamespace(i.configMap.Namespace)
+		cm.SetName(i.configMap.Name)
+		cm.Data = map[string]string{InventoryKey: string(data)}
+		i.log.Debug("Creating device inventory", "devices", len(entries))
+		return reconcile.Result{}, errors.Wrap(i.kube.Create(ctx, cm), errCreateInventory)
+	}
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errGetInventory)
+	}
+	if cm.Data[InventoryKey] == string(data) {
+		return reconcile.Result{}, nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[InventoryKey] = string(data)
+	i.log.Debug("Updating device inventory", "devices", len(entries))
+	return reconcile.Result{}, errors.Wrap(i.kube.Update(ctx, cm), errUpdateInventory)
+}
+
+// entries returns an inventory entry for each managed Device, sorted by API
+// version and name.
+func (i *inventory) entries(ctx context.Context) ([]InventoryEntry, error) {
+	l := &v1alpha2.DeviceList{}
+	if err := i.kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+	al := &emserverv1beta1.DeviceList{}
+	if err := i.kube.List(ctx, al); err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+
+	entries := make([]InventoryEntry, 0, len(l.Items)+len(al.Items))
+	for j := range l.Items {
+		entries = append(entries, inventoryEntry(&l.Items[j], v1alpha2.SchemeGroupVersion.String()))
+	}
+	for j := range al.Items {
+		d := &v1alpha2.Device{}
+		if err := al.Items[j].ConvertTo(d); err != nil {
+			return nil, errors.Wrap(err, errConvertAlias)
+		}
+		entries = append(entries, inventoryEntry(d, emserverv1beta1.SchemeGroupVersion.String()))
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		if entries[a].APIVersion != entries[b].APIVersion {
+			return entries[a].APIVersion < entries[b].APIVersion
+		}
+		return entries[a].Name < entries[b].Name
+	})
+	return entries, nil
+}
+
+func inventoryEntry(d *v1alpha2.Device, apiVersion string) InventoryEntry {
+	e := InventoryEntry{
+		Name:       d.GetName(),
+		APIVersion: apiVersion,
+		ID:         d.Status.AtProvider.ID,
+		IPv4:       d.Status.AtProvider.IPv4,
+		IPv6:       d.Status.AtProvider.IPv6,
+		Metro:      d.Status.AtProvider.Metro,
+		Facility:   d.Status.AtProvider.Facility,
+		Plan:       d.Spec.ForProvider.Plan,
+		State:      d.Status.AtProvider.State,
+	}
+	if d.Spec.ForProvider.Hostname != nil {
+		e.Hostname = *d.Spec.ForProvider.Hostname
+	}
+	if e.Metro == "" {
+		e.Metro = d.Spec.ForProvider.Metro
+	}
+	if e.Facility == "" {
+		e.Facility = d.Spec.ForProvider.Facility
+	}
+	return e
+}
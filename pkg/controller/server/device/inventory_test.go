@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+func TestInventoryReconcile(t *testing.T) {
+	inventoryName := types.NamespacedName{Namespace: "crossplane-system", Name: "devices"}
+	hostname := "web-1"
+
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *v1alpha2.DeviceList:
+			d := v1alpha2.Device{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+			d.Spec.ForProvider.Hostname = &hostname
+			d.Spec.ForProvider.Plan = "c3.small.x86"
+			d.Spec.ForProvider.Metro = "sv"
+			d.Status.AtProvider.ID = deviceID
+			d.Status.AtProvider.IPv4 = "192.0.2.1"
+			d.Status.AtProvider.IPv6 = "2001:db8::1"
+			d.Status.AtProvider.State = v1alpha2.StateActive
+			l.Items = []v1alpha2.Device{d}
+		case *emserverv1beta1.DeviceList:
+			d := emserverv1beta1.Device{ObjectMeta: metav1.ObjectMeta{Name: "db"}}
+			d.Spec.ForProvider.Plan = "m3.large.x86"
+			d.Spec.ForProvider.Facility = "ny5"
+			d.Status.AtProvider.State = v1alpha2.StateProvisioning
+			l.Items = []emserverv1beta1.Device{d}
+		}
+		return nil
+	}
+	entries := []InventoryEntry{
+		{
+			Name:       "db",
+			APIVersion: emserverv1beta1.SchemeGroupVersion.String(),
+			Facility:   "ny5",
+			Plan:       "m3.large.x86",
+			State:      v1alpha2.StateProvisioning,
+		},
+		{
+			Name:       "web",
+			APIVersion: v1alpha2.SchemeGroupVersion.String(),
+			ID:         deviceID,
+			Hostname:   hostname,
+			IPv4:       "192.0.2.1",
+			IPv6:       "2001:db8::1",
+			Metro:      "sv",
+			Plan:       "c3.small.x86",
+			State:      v1alpha2.StateActive,
+		},
+	}
+	data, _ := json.MarshalIndent(entries, "", "  ")
+
+	cases := map[string]struct {
+		existing *corev1.ConfigMap
+		want     map[string]string
+		wantOp   string
+	}{
+		"Create": {
+			want:   map[string]string{InventoryKey: string(data)},
+			wantOp: "create",
+		},
+		"Update": {
+			existing: &corev1.ConfigMap{Data: map[string]string{"owner": "dns", InventoryKey: "[]"}},
+			want:     map[string]string{"owner": "dns", InventoryKey: string(data)},
+			wantOp:   "update",
+		},
+		"Unchanged": {
+			existing: &corev1.ConfigMap{Data: map[string]string{InventoryKey: string(data)}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]string
+			var op string
+			i := &inventory{
+				kube: &test.MockClient{
+					MockList: list,
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if diff := cmp.Diff(inventoryName, key); diff != "" {
+							t.Errorf("Get(...): -want key, +got key:\n%s", diff)
+						}
+						if tc.existing == nil {
+							return kerrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, key.Name)
+						}
+						tc.existing.DeepCopyInto(obj.(*corev1.ConfigMap))
+						return nil
+					},
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						op, got = "create", obj.(*corev1.ConfigMap).Data
+						return nil
+					},
+					MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+						op, got = "update", obj.(*corev1.ConfigMap).Data
+						return nil
+					},
+				},
+				configMap: inventoryName,
+				log:       logging.NewNopLogger(),
+			}
+			if _, err := i.Reconcile(context.Background(), reconcile.Request{NamespacedName: inventoryName}); err != nil {
+				t.Fatalf("Reconcile(...): %s", err)
+			}
+			if diff := cmp.Diff(tc.wantOp, op); diff != "" {
+				t.Errorf("Reconcile(...): -want operation, +got operation:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Reconcile(...): -want data, +got data:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsInventory(t *testing.T) {
+	p := isInventory(types.NamespacedName{Namespace: "crossplane-system", Name: "devices"})
+	cases := map[string]struct {
+		cm   *corev1.ConfigMap
+		want bool
+	}{
+		"Inventory":      {cm: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "devices"}}, want: true},
+		"OtherName":      {cm: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "other"}}},
+		"OtherNamespace": {cm: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "devices"}}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, p.Delete(event.DeleteEvent{Object: tc.cm})); diff != "" {
+				t.Errorf("Delete(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
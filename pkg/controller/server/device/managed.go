@@ -19,19 +19,33 @@ package device
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
 	v1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
 	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/alias"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/connection"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/plan"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/reconciler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -51,35 +65,139 @@ const (
 	errNotDevice               = "managed resource is not a Device"
 	errGetDevice               = "cannot get Device"
 	errCreateDevice            = "cannot create Device"
+	errFindCreated             = "cannot find a Device created earlier"
+	errNoCapacity              = "no capacity for the requested plan in the requested metro or facility"
+	errListPlans               = "cannot list plans"
+	errUnsupportedFeaturesFmt  = "plan %q does not support the required features %s"
 	errUpdateDevice            = "cannot modify Device"
 	errDeleteDevice            = "cannot delete Device"
+	errDeleteExpired           = "cannot delete Device whose deadline passed"
+	errDeadlinePassedFmt       = "not creating Device whose deadline passed at %s"
+	errRegisterMetrics         = "cannot register Device metrics"
+	errAddBatchObserver        = "cannot add Device batch observer"
+	errAddAutoImporter         = "cannot add Device auto importer"
+	errGetCloneSourceRef       = "cannot get Device referenced by cloneFromRef"
+	errCloneSourceNotCreated   = "Device referenced by cloneFromRef has not been created yet"
+	errGetCloneSource          = "cannot get Device to clone"
+	errListOS                  = "cannot list operating systems"
+	errPerformAction           = "cannot perform Device action"
+	errUnknownActionFmt        = "unknown Device action %q"
+	errInvalidExternalNameFmt  = "external name %q is not a Device ID: set it to the ID of an existing Device, or remove it to create a new Device"
 
 	userdataMapKey = "cloud-init"
 )
 
-// SetupDevice adds a controller that reconciles Devices
-func SetupDevice(mgr ctrl.Manager, l logging.Logger) error {
+// SetupDevice adds controllers that reconcile the Devices of the
+// server.metal.equinix.com API group, and of the
+// server.equinixmetal.crossplane.io API group that aliases it.
+func SetupDevice(mgr ctrl.Manager, o options.Options) error {
 	name := managed.ControllerName(v1alpha2.DeviceGroupKind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha2.DeviceGroupVersionKind),
-		managed.WithExternalConnecter(&connecter{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
-		}),
-		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	)
+	recorder := clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
 
-	return ctrl.NewControllerManagedBy(mgr).
+	c := &connecter{
+		kube:     mgr.GetClient(),
+		usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:      o.Logger.WithValues("controller", name),
+		recorder: recorder,
+	}
+	// In batch mode the cache is refreshed every poll interval by the batch
+	// observer. Its TTL only matters should the batch observer fall behind.
+	ttl := o.DeviceCacheTTL
+	if o.BatchObserve && ttl < 2*o.PollInterval {
+		ttl = 2 * o.PollInterval
+	}
+	if ttl > 0 {
+		c.cache = devicesclient.NewCache(ttl)
+	}
+	if o.DeviceKeyscan {
+		c.keyscanner = devicesclient.NewKeyscanner(devicesclient.DefaultKeyscanTimeout)
+	}
+	r := reconciler.NewManaged(mgr, o, name, v1alpha2.DeviceGroupVersionKind, c, recorder, deviceOptions(mgr, o)...)
+
+	if err := registerStateCollector(metrics.Registry, mgr.GetCache()); err != nil {
+		return errors.Wrap(err, errRegisterMetrics)
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha2.Device{}).
-		Complete(r)
+		WithOptions(o.ControllerOptions())
+	if o.BatchObserve {
+		changed := make(chan ctrlevent.GenericEvent)
+		if err := mgr.Add(&batchObserver{
+			kube:        mgr.GetClient(),
+			cache:       c.cache,
+			interval:    o.PollInterval,
+			changed:     changed,
+			log:         o.Logger.WithValues("controller", name),
+			authFn:      clients.GetAuthInfo,
+			newClientFn: devicesclient.NewClient,
+		}); err != nil {
+			return errors.Wrap(err, errAddBatchObserver)
+		}
+		b = b.Watches(&source.Channel{Source: changed}, &handler.EnqueueRequestForObject{})
+	}
+	if err := b.Complete(r); err != nil {
+		return err
+	}
+	if err := mgr.Add(&autoImporter{
+		kube:          mgr.GetClient(),
+		interval:      o.PollInterval,
+		log:           o.Logger.WithValues("controller", name),
+		credentialsFn: clients.ProviderConfigCredentials,
+		newClientFn:   devicesclient.NewClient,
+	}); err != nil {
+		return errors.Wrap(err, errAddAutoImporter)
+	}
+	if o.DeviceInventory.Name != "" {
+		if err := SetupInventory(mgr, o.DeviceInventory, o.Logger); err != nil {
+			return err
+		}
+	}
+
+	// Devices of the alias API group share the cache, but not the batch
+	// observer, of the Devices they alias.
+	name = managed.ControllerName(emserverv1beta1.DeviceGroupKind)
+	recorder = clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
+	ac := &connecter{
+		kube:     alias.NewClient(mgr.GetClient(), aliasKind),
+		usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:      o.Logger.WithValues("controller", name),
+		recorder: recorder,
+		cache:    c.cache,
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&emserverv1beta1.Device{}).
+		WithOptions(o.ControllerOptions()).
+		Complete(reconciler.NewManaged(mgr, o, name, emserverv1beta1.DeviceGroupVersionKind, alias.NewConnecter(ac, aliasKind), recorder, deviceOptions(mgr, o)...))
+}
+
+// aliasKind is the Device of the server.equinixmetal.crossplane.io API group,
+// which aliases the Device of the server.metal.equinix.com API group.
+var aliasKind = alias.Kind{
+	GroupVersionKind: emserverv1beta1.DeviceGroupVersionKind,
+	New:              func() alias.Object { return &emserverv1beta1.Device{} },
+	NewHub:           func() alias.Hub { return &v1alpha2.Device{} },
+}
+
+// deviceOptions returns the options of the managed reconcilers of Devices,
+// which publish connection secrets according to the connection secret
+// policy.
+func deviceOptions(mgr ctrl.Manager, o options.Options) []managed.ReconcilerOption {
+	return []managed.ReconcilerOption{
+		managed.WithConnectionPublishers(connection.NewPublisher(mgr.GetClient(), managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme()), o.ConnectionSecretPolicy)),
+	}
 }
 
 type connecter struct {
 	kube        client.Client
 	usage       resource.Tracker
+	log         logging.Logger
+	recorder    event.Recorder
+	cache       *devicesclient.Cache
+	keyscanner  *devicesclient.Keyscanner
 	newClientFn func(ctx context.Context, config *clients.Credentials) (devicesclient.ClientWithDefaults, error)
 }
 
@@ -101,14 +219,32 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errGetProviderConfigSecret)
 	}
-	client, err := newClientFn(ctx, cfg)
+	client, err := newClientFn(clients.WithController(ctx, managed.ControllerName(v1alpha2.DeviceGroupKind)), cfg)
 
-	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+	log := c.log.WithValues("name", mg.GetName(), "uid", mg.GetUID())
+	return &external{kube: c.kube, client: client, log: log, recorder: c.recorder, cache: c.cache, cacheKey: clients.CredentialsKey(cfg), keyscanner: c.keyscanner}, errors.Wrap(err, errNewClient)
 }
 
 type external struct {
-	kube   client.Client
-	client devicesclient.ClientWithDefaults
+	kube     client.Client
+	client   devicesclient.ClientWithDefaults
+	log      logging.Logger
+	recorder event.Recorder
+
+	// cache serves the Devices of a project from a list of all of them, and
+	// is nil unless enabled. cacheKey identifies the credentials the Devices
+	// are listed with.
+	cache    *devicesclient.Cache
+	cacheKey string
+
+	// keyscanner scans the SSH host keys of active Devices, and is nil
+	// unless enabled.
+	keyscanner *devicesclient.Keyscanner
+
+	// observed is the Device most recently read by Observe. The managed
+	// reconciler connects for every reconcile, so it is reused by Update
+	// instead of reading the Device again.
+	observed *packngo.Device
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) { //nolint:gocyclo
@@ -117,36 +253,103 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotDevice)
 	}
 
+	if err := e.expire(ctx, d); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// The external name is the name of the Device until the Device is created.
+	// Any other external name must be the ID of an existing Device; reading
+	// a Device by anything else fails every time.
+	if id := meta.GetExternalName(d); !packetclient.IsUUID(id) {
+		if id == "" || id == d.GetName() {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Errorf(errInvalidExternalNameFmt, id)
+	}
+
 	// Observe device
-	device, _, err := e.client.Get(meta.GetExternalName(d), nil)
+	device, err := e.get(meta.GetExternalName(d))
 	if packetclient.IsNotFound(err) {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 	if err != nil {
+		recordRequestID(d, err)
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetDevice)
 	}
+	e.observed = device
 
 	current := d.Spec.ForProvider.DeepCopy()
 	devicesclient.LateInitialize(&d.Spec.ForProvider, device)
-	if !cmp.Equal(current, &d.Spec.ForProvider) {
+	// Empty and nil fields are written alike, so late initializing one as
+	// the other changes nothing worth writing.
+	if !cmp.Equal(current, &d.Spec.ForProvider, cmpopts.EquateEmpty()) {
+		e.log.Debug("Late initialized Device parameters")
 		if err := e.kube.Update(ctx, d); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
 		}
 	}
 
+	// The ID of the last failed request, and the last action, outlive
+	// successful observations. So does when the device phoned home, until
+	// it is provisioned again.
+	previous := d.Status.AtProvider
+	lastRequestID := d.Status.AtProvider.LastRequestID
+	lastAction := d.Status.AtProvider.Action
+	lastHardware := d.Status.AtProvider.Hardware
+	phonedHomeAt := d.Status.AtProvider.PhonedHomeAt
 	d.Status.AtProvider, err = devicesclient.GenerateObservation(device)
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
 	}
+	d.Status.AtProvider.LastRequestID = lastRequestID
+	d.Status.AtProvider.Action = lastAction
+	if !reprovisioning(device) {
+		d.Status.AtProvider.PhonedHomeAt = phonedHomeAt
+	}
+	e.progress(d, device)
+	e.hardware(d, lastHardware)
+
+	// Report the most recent events of a Device that is not active, e.g.
+	// one that failed to provision. They are only listed again when the
+	// state or provisioning progress of the Device changed. They are only
+	// informational, so failing to list them does not fail the observation.
+	if device.State != v1alpha2.StateActive {
+		d.Status.AtProvider.RecentEvents = previous.RecentEvents
+		if eventsChanged(previous, d.Status.AtProvider) {
+			events, _, err := e.client.ListEvents(device.ID, devicesclient.RecentEventsOptions())
+			if err != nil {
+				e.log.Debug("Cannot list Device events", "id", device.ID, "error", err)
+			} else {
+				d.Status.AtProvider.RecentEvents = devicesclient.GenerateEvents(events)
+			}
+		}
+	}
+
+	// A Device that is being deprovisioned still exists until the API no
+	// longer returns it. Reporting it as gone any earlier would allow its
+	// finalizer to be removed before its release is complete.
+	if deprovisioning(device) {
+		d.Status.SetConditions(xpv1.Deleting())
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: devicesclient.GetConnectionDetails(device),
+		}, nil
+	}
 
 	// Set Device status and bindable
 	switch d.Status.AtProvider.State {
 	case v1alpha2.StateActive:
+		// The device phoning home is a stronger signal than its state that
+		// its operating system completed its first boot.
+		if waitsForPhoneHome(d) && !e.phonedHome(d, device) {
+			d.Status.SetConditions(xpv1.Creating().WithMessage(msgWaitingForPhoneHome))
+			break
+		}
 		d.Status.SetConditions(xpv1.Available())
 	case v1alpha2.StateProvisioning:
 		d.Status.SetConditions(xpv1.Creating())
 	case v1alpha2.StateQueued,
-		v1alpha2.StateDeprovisioning,
 		v1alpha2.StateFailed,
 		v1alpha2.StateInactive,
 		v1alpha2.StatePoweringOff,
@@ -155,16 +358,119 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	upToDate, networkTypeUpToDate := devicesclient.IsUpToDate(d, device)
+	if !upToDate || !networkTypeUpToDate {
+		summary := devicesclient.SummarizeDifferences(devicesclient.Differences(d, device))
+		e.log.Debug("Device is not up to date", "id", device.ID, "differences", summary)
+		// While planning the update is reported as a planned change.
+		if !plan.Planning(ctx) {
+			e.recorder.Event(d, event.Normal(packetclient.ReasonDrift, "Updating Device that differs from its desired state: "+summary))
+			d.Status.SetConditions(d.Status.GetCondition(xpv1.TypeReady).WithMessage("Updating " + summary))
+		}
+	}
+
+	// A requested action is performed by Update.
+	action, actionRequested := requestedAction(d)
+	if actionRequested {
+		e.log.Debug("Device action requested", "id", device.ID, "action", action)
+	}
 
 	o := managed.ExternalObservation{
 		ResourceExists:    true,
-		ResourceUpToDate:  upToDate && networkTypeUpToDate,
+		ResourceUpToDate:  upToDate && networkTypeUpToDate && !actionRequested,
 		ConnectionDetails: devicesclient.GetConnectionDetails(device),
 	}
 
+	// The SSH host keys of a Device let bootstrap tooling connect to it with
+	// strict host key checking. A Device may not accept connections as soon
+	// as it is active, so failing to scan them does not fail the observation.
+	if e.keyscanner != nil {
+		kh, err := e.keyscanner.KnownHosts(ctx, device)
+		if err != nil {
+			e.log.Debug("Cannot scan Device SSH host keys", "id", device.ID, "error", err)
+		}
+		if kh != "" {
+			o.ConnectionDetails[devicesclient.ConnectionDetailKnownHosts] = []byte(kh)
+		}
+	}
+
 	return o, nil
 }
 
+// hardware completes the observed hardware of the supplied Device with the
+// CPUs, memory and drives the catalog of its plan describes. They are read
+// from the catalog only when the plan differs from that of the last observed
+// hardware, and are only informational, so failing to read them does not fail
+// the observation.
+func (e *external) hardware(d *v1alpha2.Device, last *v1alpha2.DeviceHardware) {
+	plan := d.Spec.ForProvider.Plan
+	if plan == "" {
+		return
+	}
+	h := d.Status.AtProvider.Hardware
+	if h == nil {
+		h = &v1alpha2.DeviceHardware{}
+	}
+	if last != nil && last.Plan == plan {
+		h.Plan, h.CPUs, h.Memory, h.Drives = last.Plan, last.CPUs, last.Memory, last.Drives
+		d.Status.AtProvider.Hardware = h
+		return
+	}
+	plans, _, err := e.client.ProjectList(e.client.GetProjectID(packetclient.CredentialProjectID), nil)
+	if err != nil {
+		e.log.Debug("Cannot list plans", "plan", plan, "error", err)
+		return
+	}
+	p := devicesclient.FindPlan(plans, plan)
+	if p == nil {
+		return
+	}
+	devicesclient.SetPlanHardware(h, p)
+	d.Status.AtProvider.Hardware = h
+}
+
+// expire deletes the supplied Device once its deadline has passed. Deleting
+// the managed resource, rather than the Equinix Metal device, means the
+// Device is deleted like a Device deleted by hand, respecting its deletion
+// policy. While planning the deletion is only proposed.
+func (e *external) expire(ctx context.Context, d *v1alpha2.Device) error {
+	deadline := devicesclient.Deadline(d)
+	if deadline == nil || meta.WasDeleted(d) || time.Now().Before(deadline.Time) {
+		return nil
+	}
+	if plan.Planning(ctx) {
+		plan.Propose(ctx, packetv1beta1.PendingDelete())
+		return nil
+	}
+	e.log.Debug("Deleting Device whose deadline passed", "deadline", deadline)
+	e.recorder.Event(d, event.Normal(packetclient.ReasonDeadlinePassed, "Deleting Device whose deadline passed at "+deadline.UTC().Format(time.RFC3339)))
+	return errors.Wrap(resource.IgnoreNotFound(e.kube.Delete(ctx, d)), errDeleteExpired)
+}
+
+// get returns the Device with the supplied ID, served from the Device cache
+// if it is enabled and listed the Device. Devices the cache did not list, for
+// example because they were just created, are read from the API.
+func (e *external) get(id string) (*packngo.Device, error) {
+	if e.cache != nil {
+		device, ok, err := e.cache.Get(e.client, e.cacheKey, e.client.GetProjectID(packetclient.CredentialProjectID), id)
+		if err != nil {
+			e.log.Debug("Cannot list Devices", "error", err)
+		}
+		if ok {
+			return device, nil
+		}
+	}
+	device, _, err := e.client.Get(id, devicesclient.ObserveOptions())
+	return device, err
+}
+
+// forget removes the Device with the supplied ID from the Device cache, so
+// that it is read from the API once it was changed.
+func (e *external) forget(id string) {
+	if e.cache != nil {
+		e.cache.Forget(e.cacheKey, id)
+	}
+}
+
 // resolveUserDataRefs returns a userdata string fetched from the referenced userdata resource
 // TODO(displague) use reference.NewAPIResolver when TypedReference is support
 func (e *external) resolveUserDataRefs(ctx context.Context, d *v1alpha2.Device) (string, error) { //nolint:gocyclo
@@ -210,6 +516,9 @@ func (e *external) resolveUserDataRefs(ctx context.Context, d *v1alpha2.Device)
 		err := errors.Wrap(fmt.Errorf(errGetUserDataRef), fmt.Sprintf(errRefKeyNotFoundFmt, key))
 		return "", err
 	}
+	if !ok {
+		e.log.Debug("Optional UserDataRef key not found", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name, "key", key)
+	}
 	return userdata, nil
 }
 
@@ -219,10 +528,29 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotDevice)
 	}
 
+	// A Device deleted by expire is not worth creating until its deletion is
+	// reconciled.
+	if deadline := devicesclient.Deadline(d); deadline != nil && !time.Now().Before(deadline.Time) {
+		return managed.ExternalCreation{}, errors.Errorf(errDeadlinePassedFmt, deadline.UTC().Format(time.RFC3339))
+	}
+
 	d.Status.SetConditions(xpv1.Creating())
 
+	if err := e.clone(ctx, d); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
 	createDev := d.DeepCopy()
 
+	if d.Spec.ForProvider.OSVersionConstraint != nil {
+		slug, err := e.resolveOS(d)
+		if err != nil {
+			return managed.ExternalCreation{}, err
+		}
+		d.Status.AtProvider.OS = slug
+		createDev.Spec.ForProvider.OS = slug
+	}
+
 	if d.Spec.ForProvider.UserDataRef != nil {
 		userdata, err := e.resolveUserDataRefs(ctx, d)
 		if err != nil {
@@ -231,12 +559,111 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		createDev.Spec.ForProvider.UserData = &userdata
 	}
 
-	create := devicesclient.CreateFromDevice(createDev, e.client.GetProjectID(packetclient.CredentialProjectID))
+	// A Device may already have been created for this managed resource by a
+	// Create whose external name was lost, for example because the managed
+	// resource could not be updated. It is adopted instead of creating, and
+	// paying for, a second Device.
+	projectID := e.client.GetProjectID(packetclient.CredentialProjectID)
+	if d.GetUID() != "" {
+		devices, _, err := e.client.List(projectID, devicesclient.CreatedForOptions(d.GetUID()))
+		if err != nil {
+			recordRequestID(d, err)
+			return managed.ExternalCreation{}, errors.Wrap(err, errFindCreated)
+		}
+		if device := devicesclient.CreatedFor(devices, d.GetUID()); device != nil {
+			e.log.Info("Adopting Device created earlier", "id", device.ID)
+			return e.created(ctx, d, device)
+		}
+	}
+
+	// Required features the catalog of the plan lists as unsupported are
+	// rejected with an error naming them, rather than by the API.
+	if features := d.Spec.ForProvider.Features; devicesclient.RequiresCatalogFeatures(features) {
+		plans, _, err := e.client.ProjectList(projectID, nil)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errListPlans)
+		}
+		if p := devicesclient.FindPlan(plans, d.Spec.ForProvider.Plan); p != nil {
+			if unsupported := devicesclient.UnsupportedFeatures(p, features); len(unsupported) > 0 {
+				return managed.ExternalCreation{}, errors.Errorf(errUnsupportedFeaturesFmt, p.Slug, strings.Join(unsupported, ", "))
+			}
+		}
+	}
+
+	create := devicesclient.CreateFromDevice(createDev, projectID)
 	device, _, err := e.client.Create(create)
 	if err != nil {
+		// The userdata may have been resolved from a reference, so it is
+		// not among the sensitive values of the Device itself.
+		err = packetclient.RedactError(err, create.UserData, create.CustomData)
+		packetclient.RecordAPIError(packetclient.NewRedactingRecorder(e.recorder, create.UserData, create.CustomData), d, errCreateDevice, err)
+		recordRequestID(d, err)
+		if packetclient.IsCapacity(err) {
+			err = errors.Wrap(err, errNoCapacity)
+		}
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateDevice)
 	}
 
+	e.log.Debug("Created Device", "id", device.ID)
+	return e.created(ctx, d, device)
+}
+
+// clone fills the parameters the supplied Device does not set from the device
+// it clones, if any, and records them in its spec, so that they are not
+// changed by later changes to the cloned device.
+func (e *external) clone(ctx context.Context, d *v1alpha2.Device) error {
+	p := &d.Spec.ForProvider
+	if !p.Clones() {
+		return nil
+	}
+	id := ""
+	if p.CloneFrom != nil {
+		id = *p.CloneFrom
+	}
+	if ref := p.CloneFromRef; ref != nil {
+		src := &v1alpha2.Device{}
+		if err := e.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, src); err != nil {
+			return errors.Wrap(err, errGetCloneSourceRef)
+		}
+		id = v1alpha2.DeviceID()(src)
+		if id == "" {
+			return errors.New(errCloneSourceNotCreated)
+		}
+	}
+
+	source, _, err := e.client.Get(id, nil)
+	if err != nil {
+		recordRequestID(d, err)
+		return errors.Wrap(err, errGetCloneSource)
+	}
+	current := p.DeepCopy()
+	devicesclient.Clone(p, source)
+	if cmp.Equal(current, p, cmpopts.EquateEmpty()) {
+		return nil
+	}
+	return errors.Wrap(e.kube.Update(ctx, d), errManagedUpdateFailed)
+}
+
+// resolveOS returns the slug of the operating system of the supplied Device,
+// whose operatingSystem is a distribution with a version constraint. The slug
+// pinned in its status is preferred over the latest version the catalog
+// offers, so that a Device created again runs the same operating system.
+func (e *external) resolveOS(d *v1alpha2.Device) (string, error) {
+	if slug := d.Status.AtProvider.OS; slug != "" {
+		return slug, nil
+	}
+	oses, _, err := e.client.ListOS()
+	if err != nil {
+		recordRequestID(d, err)
+		return "", errors.Wrap(err, errListOS)
+	}
+	p := d.Spec.ForProvider
+	return devicesclient.ResolveOS(oses, p.OS, *p.OSVersionConstraint, p.Plan)
+}
+
+// created records the supplied Device as the external resource of the
+// supplied managed resource.
+func (e *external) created(ctx context.Context, d *v1alpha2.Device, device *packngo.Device) (managed.ExternalCreation, error) {
 	d.Status.AtProvider.ID = device.ID
 	meta.SetExternalName(d, device.ID)
 	if err := e.kube.Update(ctx, d); err != nil {
@@ -251,21 +678,40 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotDevice)
 	}
+	defer e.forget(meta.GetExternalName(d))
 
-	// NOTE(hasheddan): we must get the device again to see what type of update
-	// we need to make
-	device, _, err := e.client.Get(meta.GetExternalName(d), nil)
-	if err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, errGetDevice)
+	// Any other updates are made by the next reconcile.
+	if action, ok := requestedAction(d); ok {
+		return managed.ExternalUpdate{}, e.act(ctx, d, action)
+	}
+
+	// NOTE(hasheddan): we must know the device to see what type of update we
+	// need to make. The device read by Observe is used unless Update is
+	// called without it.
+	device := e.observed
+	if device == nil || device.ID != meta.GetExternalName(d) {
+		var err error
+		device, _, err = e.client.Get(meta.GetExternalName(d), devicesclient.ObserveOptions())
+		if err != nil {
+			recordRequestID(d, err)
+			return managed.ExternalUpdate{}, errors.Wrap(err, errGetDevice)
+		}
 	}
 
 	// NOTE(hasheddan): if the update is for the network type we return early
 	// and do any updates on subsequent reconciles
-	if _, n := devicesclient.IsUpToDate(d, device); !n && d.Spec.ForProvider.NetworkType != nil {
-		_, err := e.client.DeviceToNetworkType(meta.GetExternalName(d), *d.Spec.ForProvider.NetworkType)
+	networkType, diffs := devicesclient.SplitNetworkType(devicesclient.Differences(d, device))
+	if networkType != nil && d.Spec.ForProvider.NetworkType != nil {
+		e.log.Info("Converting Device network type", append([]interface{}{"id", device.ID}, devicesclient.LogValues([]devicesclient.Difference{*networkType})...)...)
+		err := e.client.ConvertDevice(device, *d.Spec.ForProvider.NetworkType)
+		packetclient.RecordAPIError(e.recorder, d, errUpdateDevice, err)
+		recordRequestID(d, err)
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateDevice)
 	}
-	_, _, err = e.client.Update(meta.GetExternalName(d), devicesclient.NewUpdateDeviceRequest(d))
+	e.log.Info("Updating Device", append([]interface{}{"id", device.ID}, devicesclient.LogValues(diffs)...)...)
+	_, _, err := e.client.Update(meta.GetExternalName(d), devicesclient.NewUpdateDeviceRequest(d))
+	packetclient.RecordAPIError(e.recorder, d, errUpdateDevice, err)
+	recordRequestID(d, err)
 
 	// TODO(displague): use "reinstall" action if userdata changed, after updating the resource
 
@@ -278,7 +724,43 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotDevice)
 	}
 	d.SetConditions(xpv1.Deleting())
+	defer e.forget(meta.GetExternalName(d))
+	if e.keyscanner != nil {
+		defer e.keyscanner.Forget(meta.GetExternalName(d))
+	}
+
+	// The Device read by Observe is already being deprovisioned, so it must
+	// not be deleted again.
+	if e.observed != nil && e.observed.ID == meta.GetExternalName(d) && deprovisioning(e.observed) {
+		return nil
+	}
 
 	_, err := e.client.Delete(meta.GetExternalName(d), false)
-	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteDevice)
+	err = resource.Ignore(packetclient.IsNotFound, err)
+	packetclient.RecordAPIError(e.recorder, d, errDeleteDevice, err)
+	recordRequestID(d, err)
+	return errors.Wrap(err, errDeleteDevice)
+}
+
+// deprovisioning returns true if the supplied device is being, or was,
+// deprovisioned.
+func deprovisioning(device *packngo.Device) bool {
+	return device.State == v1alpha2.StateDeprovisioning || device.State == v1alpha2.StateDeleted
+}
+
+// eventsChanged returns true if the events of a Device may differ from those
+// of its previous observation: its state or provisioning progress changed,
+// or its events were not listed yet.
+func eventsChanged(previous, current v1alpha2.DeviceObservation) bool {
+	return len(previous.RecentEvents) == 0 ||
+		previous.State != current.State ||
+		previous.ProvisionPercentage.Cmp(current.ProvisionPercentage) != 0
+}
+
+// recordRequestID records the ID of the failed Equinix Metal API request
+// wrapped by err, if any, in the status of the supplied Device.
+func recordRequestID(d *v1alpha2.Device, err error) {
+	if id := packetclient.RequestID(err); id != "" {
+		d.Status.AtProvider.LastRequestID = id
+	}
 }
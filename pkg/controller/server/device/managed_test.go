@@ -17,18 +17,28 @@ limitations under the License.
 package device
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-logr/zapr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/packethost/packngo"
 
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	corev1 "k8s.io/api/core/v1"
 	apiresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
@@ -36,9 +46,13 @@ import (
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device/fake"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/plan"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/redact"
 	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -48,6 +62,8 @@ import (
 const (
 	namespace  = "cool-namespace"
 	deviceName = "my-cool-device"
+	deviceID   = "2f8a6c1e-5b7d-4c3a-9e1f-0a6b8d4c2e7f"
+	deviceUID  = types.UID("cool-device-uid")
 
 	providerName       = "cool-equinix-metal"
 	providerSecretName = "cool-equinix-metal-secret"
@@ -162,10 +178,66 @@ func withState(s string) deviceModifier {
 	return func(i *v1alpha2.Device) { i.Status.AtProvider.State = s }
 }
 
+func withHardware(h *v1alpha2.DeviceHardware) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.Hardware = h }
+}
+
+func withRecentEvents(e ...v1alpha2.DeviceEvent) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.RecentEvents = e }
+}
+
+func withLastRequestID(id string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.LastRequestID = id }
+}
+
+func withExternalName(n string) deviceModifier {
+	return func(i *v1alpha2.Device) { meta.SetExternalName(i, n) }
+}
+
+func withUID(uid types.UID) deviceModifier {
+	return func(i *v1alpha2.Device) { i.SetUID(uid) }
+}
+
 func withID(d string) deviceModifier {
 	return func(i *v1alpha2.Device) { i.Status.AtProvider.ID = d }
 }
 
+func withTags(t []string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Tags = t }
+}
+
+func withPlan(p string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Plan = p }
+}
+
+func withFeatures(f map[string]string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.Features = f }
+}
+
+func withTerminateAt(t time.Time) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.TerminateAt = &metav1.Time{Time: t} }
+}
+
+func withOS(os string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.OS = os }
+}
+
+func withOSVersionConstraint(c string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.OSVersionConstraint = &c }
+}
+
+func withObservedOS(slug string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.OS = slug }
+}
+
+func withCloneFrom(id string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.CloneFrom = &id }
+}
+
+func withCloneFromRef(name string) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Spec.ForProvider.CloneFromRef = &xpv1.Reference{Name: name} }
+}
+
 func withNetworkType(d *string) deviceModifier {
 	return func(i *v1alpha2.Device) { i.Spec.ForProvider.NetworkType = d }
 }
@@ -191,7 +263,7 @@ func device(im ...deviceModifier) *v1alpha2.Device {
 			Name:       deviceName,
 			Finalizers: []string{},
 			Annotations: map[string]string{
-				meta.AnnotationKeyExternalName: deviceName,
+				meta.AnnotationKeyExternalName: deviceID,
 			},
 		},
 		Spec: v1alpha2.DeviceSpec{
@@ -275,6 +347,8 @@ func TestConnect(t *testing.T) {
 	}{
 		"Connected": {
 			conn: &connecter{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
 					switch key {
 					case client.ObjectKey{Name: providerName}:
@@ -301,12 +375,14 @@ func TestConnect(t *testing.T) {
 			},
 		},
 		"NotDevice": {
-			conn: &connecter{},
+			conn: &connecter{log: logging.NewNopLogger(), recorder: event.NewNopRecorder()},
 			args: args{ctx: context.Background(), mg: &strange{}},
 			want: want{err: errors.New(errNotDevice)},
 		},
 		"FailedToGetProvider": {
 			conn: &connecter{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
 					return errorBoom
 				}},
@@ -322,6 +398,8 @@ func TestConnect(t *testing.T) {
 		},
 		"FailedToGetProviderSecret": {
 			conn: &connecter{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
 					switch key {
 					case client.ObjectKey{Name: providerName}:
@@ -343,6 +421,8 @@ func TestConnect(t *testing.T) {
 		},
 		"ProviderSecretNil": {
 			conn: &connecter{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
 					switch key {
 					case client.ObjectKey{Name: providerName}:
@@ -366,6 +446,8 @@ func TestConnect(t *testing.T) {
 		},
 		"FailedToCreateDevice": {
 			conn: &connecter{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
 					switch key {
 					case client.ObjectKey{Name: providerName}:
@@ -410,6 +492,17 @@ func TestObserve(t *testing.T) {
 		err         error
 	}
 
+	deadline := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	apiErr := &packngo.ErrorResponse{
+		Response: &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{},
+			Request:    &http.Request{},
+		},
+	}
+	apiErr.Response.Header.Set(clients.HeaderRequestID, "9a8b7c6d")
+
 	cases := map[string]struct {
 		client managed.ExternalClient
 		args   args
@@ -417,11 +510,13 @@ func TestObserve(t *testing.T) {
 	}{
 		"ObservedDeviceAvailableNoUpdateNeeded": {
 			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
-					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
 							State:        v1alpha2.StateActive,
 							ProvisionPer: float32(100),
@@ -449,13 +544,57 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
+		"ObservedDeviceLateInitializedWithoutTags": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					// Tags that are empty rather than nil are omitted when
+					// written, so they must not cause an update.
+					MockUpdate: test.NewMockUpdateFn(errorBoom),
+				},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateActive,
+							ProvisionPer: float32(100),
+							AlwaysPXE:    *alwaysPXE,
+							Tags:         []string{},
+						}
+						return d, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withNetworkType(&networkType)),
+			},
+			want: want{
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withNetworkType(&networkType),
+					withTags([]string{}),
+					withConditions(xpv1.Available()),
+					withProvisionPer(float32(100)),
+					withState(v1alpha2.StateActive)),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
 		"ObservedDeviceAvailableUpdateNeeded": {
 			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
-					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
 							State:        v1alpha2.StateActive,
 							ProvisionPer: float32(100),
@@ -471,6 +610,43 @@ func TestObserve(t *testing.T) {
 			},
 			want: want{
 				mg: device(
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Available().WithMessage("Updating alwaysPXE: desired true, actual false")),
+					withProvisionPer(float32(100)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateActive)),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ObservedDeviceActionRequested": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateActive,
+							ProvisionPer: float32(100),
+							AlwaysPXE:    *alwaysPXE,
+						}
+						return d, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withAction(v1alpha2.ActionReboot)),
+			},
+			want: want{
+				mg: device(
+					withAction(v1alpha2.ActionReboot),
 					withInitializerParams(initializerParams{}),
 					withConditions(xpv1.Available()),
 					withProvisionPer(float32(100)),
@@ -483,13 +659,104 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
+		"ObservedDeviceWaitingForPhoneHome": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateActive,
+							ProvisionPer: float32(100),
+							AlwaysPXE:    *alwaysPXE,
+						}
+						return d, nil, nil
+					},
+					ListEventsFunc: func(deviceID string, listOpt *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error) {
+						return []packngo.Event{{Type: "provisioning.109", Interpolated: "Installation finished, rebooting server"}}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withWaitForPhoneHome()),
+			},
+			want: want{
+				mg: device(
+					withWaitForPhoneHome(),
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Creating().WithMessage(msgWaitingForPhoneHome)),
+					withProvisionPer(float32(100)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateActive)),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ObservedDeviceHardware": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateActive,
+							ProvisionPer: float32(100),
+							AlwaysPXE:    *alwaysPXE,
+							NetworkPorts: []packngo.Port{
+								{Type: "NetworkPort", Name: "eth0", Data: packngo.PortData{MAC: "b8:59:9f:00:00:01"}},
+							},
+						}
+						return d, nil, nil
+					},
+					GetProjectIDFunc: projectIDFromCredentials,
+					ProjectListFunc: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Plan, *packngo.Response, error) {
+						return []packngo.Plan{{Slug: "c3.small.x86", Specs: &packngo.Specs{Memory: &packngo.Memory{Total: "32GB"}}}}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withPlan("c3.small.x86")),
+			},
+			want: want{
+				mg: device(
+					withPlan("c3.small.x86"),
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Available()),
+					withProvisionPer(float32(100)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateActive),
+					withHardware(&v1alpha2.DeviceHardware{
+						Ports:  []v1alpha2.DevicePort{{Name: "eth0", MAC: "b8:59:9f:00:00:01"}},
+						Plan:   "c3.small.x86",
+						Memory: "32GB",
+					})),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
 		"ObservedDeviceCreating": {
 			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
-					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
 							State:        v1alpha2.StateProvisioning,
 							ProvisionPer: float32(50),
@@ -497,8 +764,8 @@ func TestObserve(t *testing.T) {
 						}
 						return d, nil, nil
 					},
-					MockDeviceNetworkType: func(_ string) (string, error) {
-						return networkType, nil
+					ListEventsFunc: func(deviceID string, opts *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error) {
+						return []packngo.Event{{Type: "provisioning.104", Interpolated: "Installing operating system"}}, nil, nil
 					},
 				},
 			},
@@ -513,6 +780,52 @@ func TestObserve(t *testing.T) {
 					withProvisionPer(float32(50)),
 					withNetworkType(&networkType),
 					withState(v1alpha2.StateProvisioning),
+					withRecentEvents(v1alpha2.DeviceEvent{Type: "provisioning.104", Body: "Installing operating system"}),
+				),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ObservedDeviceEventsUnchanged": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:        v1alpha2.StateProvisioning,
+							ProvisionPer: float32(50),
+							AlwaysPXE:    *alwaysPXE,
+						}
+						return d, nil, nil
+					},
+					ListEventsFunc: func(deviceID string, opts *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error) {
+						return []packngo.Event{{Type: "provisioning.105", Interpolated: "Listed although the Device did not change"}}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: device(
+					withProvisionPer(float32(50)),
+					withState(v1alpha2.StateProvisioning),
+					withRecentEvents(v1alpha2.DeviceEvent{Type: "provisioning.104", Body: "Installing operating system"}),
+				),
+			},
+			want: want{
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Creating()),
+					withProvisionPer(float32(50)),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateProvisioning),
+					withRecentEvents(v1alpha2.DeviceEvent{Type: "provisioning.104", Body: "Installing operating system"}),
 				),
 				observation: managed.ExternalObservation{
 					ResourceExists:    true,
@@ -523,11 +836,13 @@ func TestObserve(t *testing.T) {
 		},
 		"ObservedDeviceQueued": {
 			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				kube: &test.MockClient{
 					MockUpdate: test.NewMockUpdateFn(nil),
 				},
 				client: &fake.MockClient{
-					MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
 							State:        v1alpha2.StateQueued,
 							ProvisionPer: float32(50),
@@ -536,6 +851,9 @@ func TestObserve(t *testing.T) {
 
 						return d, nil, nil
 					},
+					ListEventsFunc: func(deviceID string, opts *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
 				},
 			},
 			args: args{
@@ -556,9 +874,47 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
+		"ObservedDeviceDeprovisioning": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						d := &packngo.Device{
+							State:     v1alpha2.StateDeprovisioning,
+							AlwaysPXE: *alwaysPXE,
+						}
+						return d, nil, nil
+					},
+					ListEventsFunc: func(deviceID string, opts *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error) {
+						return []packngo.Event{{Type: "deprovisioning.101", Interpolated: "Device deprovisioning started"}}, nil, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(
+					withInitializerParams(initializerParams{}),
+					withConditions(xpv1.Deleting()),
+					withNetworkType(&networkType),
+					withState(v1alpha2.StateDeprovisioning),
+					withRecentEvents(v1alpha2.DeviceEvent{Type: "deprovisioning.101", Body: "Device deprovisioning started"})),
+				observation: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
 		"ObservedDeviceDoesNotExist": {
-			client: &external{client: &fake.MockClient{
-				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 					return nil, nil, &packngo.ErrorResponse{
 						Response: &http.Response{
 							StatusCode: http.StatusNotFound,
@@ -575,8 +931,30 @@ func TestObserve(t *testing.T) {
 				observation: managed.ExternalObservation{ResourceExists: false},
 			},
 		},
+		"DeviceNotCreated": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withExternalName(deviceName)),
+			},
+			want: want{
+				mg:          device(withExternalName(deviceName)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"InvalidExternalName": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withExternalName("my-old-device")),
+			},
+			want: want{
+				mg:  device(withExternalName("my-old-device")),
+				err: errors.Errorf(errInvalidExternalNameFmt, "my-old-device"),
+			},
+		},
 		"NotDevice": {
-			client: &external{},
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder()},
 			args: args{
 				ctx: context.Background(),
 				mg:  &strange{},
@@ -587,8 +965,8 @@ func TestObserve(t *testing.T) {
 			},
 		},
 		"FailedToGetDevice": {
-			client: &external{client: &fake.MockClient{
-				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 					return nil, nil, errorBoom
 				}},
 			},
@@ -601,28 +979,107 @@ func TestObserve(t *testing.T) {
 				err: errors.Wrap(errorBoom, errGetDevice),
 			},
 		},
-	}
-
-	for name, tc := range cases {
-		t.Run(name, func(t *testing.T) {
-			got, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
-
-			if diff := cmp.Diff(tc.want.observation, got, test.EquateErrors()); diff != "" {
-				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
-			}
-
-			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
-			}
-
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
-				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestCreate(t *testing.T) {
+		"DeadlinePassed": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube:     &test.MockClient{MockDelete: test.NewMockDeleteFn(nil)},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return nil, nil, &packngo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTerminateAt(deadline)),
+			},
+			want: want{
+				mg:          device(withTerminateAt(deadline)),
+				observation: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"DeadlinePassedDeleteFailed": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube:     &test.MockClient{MockDelete: test.NewMockDeleteFn(errorBoom)},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTerminateAt(deadline)),
+			},
+			want: want{
+				mg:  device(withTerminateAt(deadline)),
+				err: errors.Wrap(errorBoom, errDeleteExpired),
+			},
+		},
+		"DeadlinePassedWhilePlanning": {
+			client: planned(&external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube:     &test.MockClient{MockDelete: test.NewMockDeleteFn(errors.New("must not delete while planning"))},
+				client: &fake.MockClient{
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return nil, nil, &packngo.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+					},
+				},
+			}),
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTerminateAt(deadline)),
+			},
+			want: want{
+				mg:          device(withTerminateAt(deadline), withConditions(packetv1beta1.PendingDelete())),
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"FailedToGetDeviceRecordsRequestID": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+					return nil, nil, apiErr
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg:  device(withLastRequestID("9a8b7c6d")),
+				err: errors.Wrap(apiErr, errGetDevice),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.client.Observe(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.observation, got, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.client.Observe(): -want error, +got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateConditions(), packettest.EquateQuantities()); diff != "" {
+				t.Errorf("resource.Managed: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+// planned returns the supplied external client wrapped to only plan changes.
+func planned(e managed.ExternalClient) managed.ExternalClient {
+	c := plan.NewConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return e, nil
+	}), event.NewNopRecorder(), logging.NewNopLogger())
+	ec, _ := c.Connect(context.Background(), nil)
+	return ec
+}
+
+func TestCreate(t *testing.T) {
 	type args struct {
 		ctx context.Context
 		mg  resource.Managed
@@ -638,13 +1095,30 @@ func TestCreate(t *testing.T) {
 		args   args
 		want   want
 	}{
+		"DeadlinePassed": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client:   &fake.MockClient{},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withTerminateAt(time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC))),
+			},
+			want: want{
+				mg:  device(withTerminateAt(time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC))),
+				err: errors.Errorf(errDeadlinePassedFmt, "2021-06-01T12:00:00Z"),
+			},
+		},
 		"CreatedInstance": {
 			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
 				client: &fake.MockClient{
-					MockGetProjectID: projectIDFromCredentials,
-					MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+					GetProjectIDFunc: projectIDFromCredentials,
+					CreateFunc: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
 						d := &packngo.Device{
-							ID: deviceName,
+							ID: deviceID,
 						}
 
 						return d, nil, nil
@@ -661,15 +1135,257 @@ func TestCreate(t *testing.T) {
 			want: want{
 				mg: device(
 					withConditions(xpv1.Creating()),
-					withID(deviceName),
+					withID(deviceID),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ClonedDevice": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client: &fake.MockClient{
+					GetProjectIDFunc: projectIDFromCredentials,
+					GetFunc: func(id string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						if id != "source" {
+							t.Errorf("Get(...): want source, got %s", id)
+						}
+						return &packngo.Device{ID: id, Plan: &packngo.Plan{Slug: "c3.small.x86"}, OS: &packngo.OS{Slug: "ubuntu_20_04"}}, nil, nil
+					},
+					CreateFunc: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						if createRequest.Plan != "c3.small.x86" || createRequest.OS != "ubuntu_20_04" {
+							t.Errorf("Create(...): want cloned plan and operating system, got %s and %s", createRequest.Plan, createRequest.OS)
+						}
+						return &packngo.Device{ID: deviceID}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withCloneFrom("source")),
+			},
+			want: want{
+				mg: device(
+					withCloneFrom("source"),
+					withPlan("c3.small.x86"),
+					withOS("ubuntu_20_04"),
+					withConditions(xpv1.Creating()),
+					withID(deviceID),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ResolvedOperatingSystem": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client: &fake.MockClient{
+					GetProjectIDFunc: projectIDFromCredentials,
+					ListOSFunc: func() ([]packngo.OS, *packngo.Response, error) {
+						return []packngo.OS{
+							{Slug: "ubuntu_20_04", Distro: "ubuntu", Version: "20.04"},
+							{Slug: "ubuntu_22_04", Distro: "ubuntu", Version: "22.04"},
+						}, nil, nil
+					},
+					CreateFunc: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						if createRequest.OS != "ubuntu_22_04" {
+							t.Errorf("Create(...): want resolved operating system ubuntu_22_04, got %s", createRequest.OS)
+						}
+						return &packngo.Device{ID: deviceID}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withOS("ubuntu"), withOSVersionConstraint(">=20.04")),
+			},
+			want: want{
+				mg: device(
+					withOS("ubuntu"),
+					withOSVersionConstraint(">=20.04"),
+					withConditions(xpv1.Creating()),
+					withObservedOS("ubuntu_22_04"),
+					withID(deviceID),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"PinnedOperatingSystem": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client: &fake.MockClient{
+					GetProjectIDFunc: projectIDFromCredentials,
+					CreateFunc: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						if createRequest.OS != "ubuntu_20_04" {
+							t.Errorf("Create(...): want pinned operating system ubuntu_20_04, got %s", createRequest.OS)
+						}
+						return &packngo.Device{ID: deviceID}, nil, nil
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withOS("ubuntu"), withOSVersionConstraint(">=20.04"), withObservedOS("ubuntu_20_04")),
+			},
+			want: want{
+				mg: device(
+					withOS("ubuntu"),
+					withOSVersionConstraint(">=20.04"),
+					withConditions(xpv1.Creating()),
+					withObservedOS("ubuntu_20_04"),
+					withID(deviceID),
+				),
+				creation: managed.ExternalCreation{
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"ClonedDeviceNotCreatedYet": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client:   &fake.MockClient{},
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withCloneFromRef("template")),
+			},
+			want: want{
+				mg:  device(withCloneFromRef("template"), withConditions(xpv1.Creating())),
+				err: errors.New(errCloneSourceNotCreated),
+			},
+		},
+		"FailedToGetClonedDevice": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client: &fake.MockClient{
+					GetFunc: func(id string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withCloneFrom("source")),
+			},
+			want: want{
+				mg:  device(withCloneFrom("source"), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errGetCloneSource),
+			},
+		},
+		"AdoptedDeviceCreatedEarlier": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client: &fake.MockClient{
+					GetProjectIDFunc: projectIDFromCredentials,
+					ListFunc: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+						if diff := cmp.Diff(devicesclient.CreatedForOptions(deviceUID), listOpt); diff != "" {
+							t.Errorf("List(...): -want, +got:\n%s", diff)
+						}
+						return []packngo.Device{
+							{ID: "other", Tags: []string{devicesclient.UIDTag("other")}},
+							{ID: deviceID, Tags: []string{devicesclient.UIDTag(deviceUID)}},
+						}, nil, nil
+					},
+					CreateFunc: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+						t.Errorf("Create(...): unexpected call for a Device created earlier")
+						return nil, nil, errorBoom
+					},
+				},
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUID(deviceUID)),
+			},
+			want: want{
+				mg: device(
+					withUID(deviceUID),
+					withConditions(xpv1.Creating()),
+					withID(deviceID),
 				),
 				creation: managed.ExternalCreation{
 					ConnectionDetails: managed.ConnectionDetails{},
 				},
 			},
 		},
+		"FailedToFindDeviceCreatedEarlier": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetProjectIDFunc: projectIDFromCredentials,
+				ListFunc: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withUID(deviceUID)),
+			},
+			want: want{
+				mg:  device(withUID(deviceUID), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errFindCreated),
+			},
+		},
+		"UnsupportedRequiredFeature": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetProjectIDFunc: projectIDFromCredentials,
+				ProjectListFunc: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Plan, *packngo.Response, error) {
+					return []packngo.Plan{{Slug: "c3.small.x86", Specs: &packngo.Specs{Features: &packngo.Features{Txt: true}}}}, nil, nil
+				},
+				CreateFunc: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+					t.Errorf("Create(...): unexpected call for a Device requiring unsupported features")
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withPlan("c3.small.x86"), withFeatures(map[string]string{"raid": v1alpha2.FeatureRequired, "txt": v1alpha2.FeatureRequired, "tpm": v1alpha2.FeatureRequired})),
+			},
+			want: want{
+				mg:  device(withPlan("c3.small.x86"), withFeatures(map[string]string{"raid": v1alpha2.FeatureRequired, "txt": v1alpha2.FeatureRequired, "tpm": v1alpha2.FeatureRequired}), withConditions(xpv1.Creating())),
+				err: errors.Errorf(errUnsupportedFeaturesFmt, "c3.small.x86", "raid"),
+			},
+		},
+		"FailedToListPlans": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetProjectIDFunc: projectIDFromCredentials,
+				ProjectListFunc: func(projectID string, listOpt *packngo.ListOptions) ([]packngo.Plan, *packngo.Response, error) {
+					return nil, nil, errorBoom
+				},
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(withFeatures(map[string]string{"raid": v1alpha2.FeatureRequired})),
+			},
+			want: want{
+				mg:  device(withFeatures(map[string]string{"raid": v1alpha2.FeatureRequired}), withConditions(xpv1.Creating())),
+				err: errors.Wrap(errorBoom, errListPlans),
+			},
+		},
 		"NotDevice": {
-			client: &external{},
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder()},
 			args: args{
 				ctx: context.Background(),
 				mg:  &strange{},
@@ -680,9 +1396,9 @@ func TestCreate(t *testing.T) {
 			},
 		},
 		"FailedToCreateDevice": {
-			client: &external{client: &fake.MockClient{
-				MockGetProjectID: projectIDFromCredentials,
-				MockCreate: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetProjectIDFunc: projectIDFromCredentials,
+				CreateFunc: func(createRequest *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
 					return nil, nil, errorBoom
 				},
 			}},
@@ -734,11 +1450,11 @@ func TestUpdate(t *testing.T) {
 		want   want
 	}{
 		"NoUpdateNeeded": {
-			client: &external{client: &fake.MockClient{
-				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				UpdateFunc: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
 					return &packngo.Device{}, nil, nil
 				},
-				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 					return &packngo.Device{}, nil, nil
 				},
 			}},
@@ -751,8 +1467,8 @@ func TestUpdate(t *testing.T) {
 			},
 		},
 		"UpdatedInstanceNetworkType": {
-			client: &external{client: &fake.MockClient{
-				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 					d := &packngo.Device{}
 					target := packngo.NetworkTypeHybrid
 					d.Network = mockNetworkTypeConfigs[target].Network
@@ -760,8 +1476,8 @@ func TestUpdate(t *testing.T) {
 
 					return d, nil, nil
 				},
-				MockDeviceToNetworkType: func(deviceID string, networkType string) (*packngo.Device, error) {
-					return nil, nil
+				ConvertDeviceFunc: func(_ *packngo.Device, _ string) error {
+					return nil
 				},
 			}},
 			args: args{
@@ -773,11 +1489,11 @@ func TestUpdate(t *testing.T) {
 			},
 		},
 		"UpdatedInstance": {
-			client: &external{client: &fake.MockClient{
-				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				UpdateFunc: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
 					return &packngo.Device{}, nil, nil
 				},
-				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 					d := &packngo.Device{
 						AlwaysPXE: false,
 					}
@@ -793,8 +1509,27 @@ func TestUpdate(t *testing.T) {
 				mg: device(withConditions()),
 			},
 		},
+		"UpdatedObservedInstance": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(),
+				observed: &packngo.Device{ID: deviceID},
+				client: &fake.MockClient{
+					UpdateFunc: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+						return &packngo.Device{}, nil, nil
+					},
+					GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+						return nil, nil, errorBoom
+					},
+				}},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(withConditions()),
+			},
+		},
 		"NotCloudMemorystoreInstance": {
-			client: &external{},
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder()},
 			args: args{
 				ctx: context.Background(),
 				mg:  &strange{},
@@ -805,11 +1540,11 @@ func TestUpdate(t *testing.T) {
 			},
 		},
 		"FailedToUpdateInstance": {
-			client: &external{client: &fake.MockClient{
-				MockUpdate: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				UpdateFunc: func(deviceID string, createRequest *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
 					return nil, nil, errorBoom
 				},
-				MockGet: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+				GetFunc: func(deviceID string, getOpt *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
 					return &packngo.Device{}, nil, nil
 				},
 			}},
@@ -860,8 +1595,8 @@ func TestDelete(t *testing.T) {
 		want   want
 	}{
 		"DeletedInstance": {
-			client: &external{client: &fake.MockClient{
-				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				DeleteFunc: func(deviceID string, force bool) (*packngo.Response, error) {
 					return nil, nil
 				}},
 			},
@@ -873,8 +1608,41 @@ func TestDelete(t *testing.T) {
 				mg: device(withConditions(xpv1.Deleting())),
 			},
 		},
+		"DeprovisioningInstance": {
+			client: &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				client:   &fake.MockClient{},
+				observed: &packngo.Device{ID: deviceID, State: v1alpha2.StateDeprovisioning},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(withConditions(xpv1.Deleting())),
+			},
+		},
+		"DeletedInstanceGone": {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				DeleteFunc: func(deviceID string, force bool) (*packngo.Response, error) {
+					return nil, &packngo.ErrorResponse{
+						Response: &http.Response{
+							StatusCode: http.StatusGone,
+						},
+					}
+				}},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  device(),
+			},
+			want: want{
+				mg: device(withConditions(xpv1.Deleting())),
+			},
+		},
 		"NotDeviceInstance": {
-			client: &external{},
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder()},
 			args: args{
 				ctx: context.Background(),
 				mg:  &strange{},
@@ -885,8 +1653,8 @@ func TestDelete(t *testing.T) {
 			},
 		},
 		"FailedToDeleteInstance": {
-			client: &external{client: &fake.MockClient{
-				MockDelete: func(deviceID string, force bool) (*packngo.Response, error) {
+			client: &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				DeleteFunc: func(deviceID string, force bool) (*packngo.Response, error) {
 					return nil, errorBoom
 				},
 			}},
@@ -916,3 +1684,344 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestLifecycle(t *testing.T) {
+	srv := packettest.NewMetalServer()
+	defer srv.Close()
+	defer srv.Install()()
+
+	ctx := context.Background()
+	c, err := devicesclient.NewClient(ctx, srv.Credentials())
+	if err != nil {
+		t.Fatalf("NewClient(...): %s", err)
+	}
+	e := &external{
+		kube:     &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+		client:   c,
+		log:      logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+
+	hostname := "lifecycle"
+	d := device(func(d *v1alpha2.Device) {
+		meta.SetExternalName(d, "")
+		d.Spec.ForProvider = v1alpha2.DeviceParameters{
+			Hostname: &hostname,
+			Plan:     "c3.small.x86",
+			Metro:    "sv",
+			OS:       "ubuntu_20_04",
+			Tags:     []string{"crossplane"},
+		}
+	})
+
+	observe := func() managed.ExternalObservation {
+		t.Helper()
+		o, err := e.Observe(ctx, d)
+		if err != nil {
+			t.Fatalf("Observe(...): %s", err)
+		}
+		return o
+	}
+
+	if observe().ResourceExists {
+		t.Fatalf("Observe(...): Device exists before it was created")
+	}
+	if _, err := e.Create(ctx, d); err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+
+	for _, state := range []string{v1alpha2.StateQueued, v1alpha2.StateProvisioning, v1alpha2.StateActive} {
+		o := observe()
+		if !o.ResourceExists || !o.ResourceUpToDate {
+			t.Errorf("Observe(...): want existing, up to date Device, got %+v", o)
+		}
+		if diff := cmp.Diff(state, d.Status.AtProvider.State); diff != "" {
+			t.Errorf("Observe(...): -want state, +got:\n%s", diff)
+		}
+		if state != v1alpha2.StateActive && len(d.Status.AtProvider.RecentEvents) == 0 {
+			t.Errorf("Observe(...): want the recent events of a %s Device", state)
+		}
+	}
+	if diff := cmp.Diff(xpv1.Available(), d.Status.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+	}
+
+	d.Spec.ForProvider.Tags = []string{"crossplane", "updated"}
+	if observe().ResourceUpToDate {
+		t.Errorf("Observe(...): want Device with changed tags not to be up to date")
+	}
+	if _, err := e.Update(ctx, d); err != nil {
+		t.Fatalf("Update(...): %s", err)
+	}
+	if !observe().ResourceUpToDate {
+		t.Errorf("Observe(...): want updated Device to be up to date")
+	}
+	got, _ := srv.Device(meta.GetExternalName(d))
+	if diff := cmp.Diff(d.Spec.ForProvider.Tags, got.Tags); diff != "" {
+		t.Errorf("Update(...): -want tags, +got:\n%s", diff)
+	}
+
+	if err := e.Delete(ctx, d); err != nil {
+		t.Fatalf("Delete(...): %s", err)
+	}
+	if observe().ResourceExists {
+		t.Errorf("Observe(...): Device exists after it was deleted")
+	}
+}
+
+func TestChaos(t *testing.T) {
+	srv := packettest.NewMetalServer()
+	defer srv.Close()
+	defer srv.Install()()
+
+	ctx := context.Background()
+	c, err := devicesclient.NewClient(ctx, srv.Credentials())
+	if err != nil {
+		t.Fatalf("NewClient(...): %s", err)
+	}
+
+	// The API fails, throttles, stalls and forgets, but every fault is
+	// eventually followed by a call that succeeds.
+	faults := packettest.NewFaults().
+		Add("List", packettest.APIError(http.StatusBadGateway), packettest.Partial()).
+		Add("Create", packettest.RateLimited(time.Second), packettest.LostResponse(http.StatusGatewayTimeout)).
+		Add("Get",
+			packettest.APIError(http.StatusInternalServerError),
+			packettest.Slow(10*time.Millisecond),
+			packettest.APIError(http.StatusNotFound),
+			packettest.RateLimited(time.Second),
+			packettest.APIError(http.StatusServiceUnavailable),
+		).
+		Add("ListEvents", packettest.APIError(http.StatusInternalServerError), packettest.Partial()).
+		Add("Update", packettest.APIError(http.StatusInternalServerError), packettest.LostResponse(http.StatusBadGateway))
+
+	e := &external{
+		kube:     &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+		client:   fake.NewFaultyClient(c, faults),
+		log:      logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+
+	hostname := "chaos"
+	d := device(withUID(deviceUID), func(d *v1alpha2.Device) {
+		meta.SetExternalName(d, "")
+		d.Spec.ForProvider = v1alpha2.DeviceParameters{
+			Hostname: &hostname,
+			Plan:     "c3.small.x86",
+			Metro:    "sv",
+			OS:       "ubuntu_20_04",
+			Tags:     []string{"crossplane"},
+		}
+	})
+
+	// converge reconciles the Device like the managed reconciler does,
+	// retrying whatever failed, until it is active and up to date.
+	converge := func() {
+		t.Helper()
+		for i := 0; i < 50; i++ {
+			o, err := e.Observe(ctx, d)
+			switch {
+			case err != nil:
+				continue
+			case !o.ResourceExists:
+				_, err = e.Create(ctx, d)
+			case !o.ResourceUpToDate:
+				_, err = e.Update(ctx, d)
+			case d.Status.AtProvider.State == v1alpha2.StateActive:
+				return
+			}
+		}
+		t.Fatalf("Device did not converge: %+v", d.Status.AtProvider)
+	}
+
+	converge()
+	d.Spec.ForProvider.Tags = []string{"crossplane", "updated"}
+	converge()
+
+	if diff := cmp.Diff(0, faults.Remaining()); diff != "" {
+		t.Errorf("Faults: -want remaining, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, srv.Calls("POST", "/projects/{id}/devices")); diff != "" {
+		t.Errorf("Create(...): -want devices created, +got:\n%s", diff)
+	}
+	got, _ := srv.Device(meta.GetExternalName(d))
+	if diff := cmp.Diff(d.Spec.ForProvider.Tags, devicesclient.UserTags(got.Tags)); diff != "" {
+		t.Errorf("Update(...): -want tags, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(xpv1.Available(), d.Status.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+	}
+}
+
+// benchmarkClient serves the supplied Device from Get and List, without
+// recording calls as the generated fake does.
+type benchmarkClient struct {
+	devicesclient.ClientWithDefaults
+	device *packngo.Device
+}
+
+func (c *benchmarkClient) Get(string, *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+	return c.device, nil, nil
+}
+
+func (c *benchmarkClient) List(string, *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+	return []packngo.Device{*c.device}, nil, nil
+}
+
+func (c *benchmarkClient) GetProjectID(string) string {
+	return "project"
+}
+
+// BenchmarkObserve observes an active, up to date Device, read either from
+// the API or from the Device cache.
+func BenchmarkObserve(b *testing.B) {
+	hostname := "benchmark"
+	p := &packngo.Device{
+		ID:       deviceID,
+		Hostname: hostname,
+		State:    v1alpha2.StateActive,
+		Tags:     []string{"crossplane", devicesclient.UIDTag(deviceUID)},
+		Plan:     &packngo.Plan{Slug: "c3.small.x86"},
+		OS:       &packngo.OS{Slug: "ubuntu_20_04"},
+		Metro:    &packngo.Metro{Code: "sv"},
+		Facility: &packngo.Facility{Code: "sv15"},
+		Network: []*packngo.IPAddressAssignment{
+			{IpAddressCommon: packngo.IpAddressCommon{Address: "198.51.100.1", AddressFamily: 4, Public: true, Management: true, CIDR: 31}},
+		},
+		NetworkPorts: []packngo.Port{
+			{Type: "NetworkBondPort", Name: "bond0", Data: packngo.PortData{Bonded: true}, NetworkType: packngo.NetworkTypeL3},
+		},
+	}
+
+	for name, cache := range map[string]*devicesclient.Cache{"API": nil, "Cache": devicesclient.NewCache(time.Hour)} {
+		b.Run(name, func(b *testing.B) {
+			e := &external{
+				kube:     &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				client:   &benchmarkClient{device: p},
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				cache:    cache,
+				cacheKey: "key",
+			}
+			d := device(withUID(deviceUID), withTags([]string{"crossplane"}))
+			d.Spec.ForProvider.Hostname = &hostname
+			d.Spec.ForProvider.AlwaysPXE = nil
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				o, err := e.Observe(context.Background(), d)
+				if err != nil || !o.ResourceUpToDate {
+					b.Fatalf("Observe(...): want up to date Device, got %+v, %v", o, err)
+				}
+			}
+		})
+	}
+}
+
+// eventRecorder records the events it is asked to record.
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *eventRecorder) WithAnnotations(...string) event.Recorder { return r }
+
+func TestRedaction(t *testing.T) {
+	// The API echoes the userdata and custom data of the requests it rejects.
+	echo := func(method, data string) error {
+		return &packngo.ErrorResponse{
+			Response: &http.Response{StatusCode: http.StatusUnprocessableEntity, Request: &http.Request{Method: method, URL: &url.URL{Path: "/devices"}}},
+			Errors:   []string{"invalid userdata or customdata: " + data},
+		}
+	}
+	api := &fake.MockClient{
+		GetProjectIDFunc: func(string) string { return "project" },
+		ListFunc: func(string, *packngo.ListOptions) ([]packngo.Device, *packngo.Response, error) {
+			return nil, nil, nil
+		},
+		CreateFunc: func(r *packngo.DeviceCreateRequest) (*packngo.Device, *packngo.Response, error) {
+			return nil, nil, echo(http.MethodPost, r.UserData+" "+r.CustomData)
+		},
+		GetFunc: func(string, *packngo.GetOptions) (*packngo.Device, *packngo.Response, error) {
+			return &packngo.Device{ID: deviceID, State: v1alpha2.StateActive, UserData: "#cloud-config\n"}, nil, nil
+		},
+		UpdateFunc: func(_ string, r *packngo.DeviceUpdateRequest) (*packngo.Device, *packngo.Response, error) {
+			return nil, nil, echo(http.MethodPut, *r.UserData)
+		},
+	}
+
+	var logs bytes.Buffer
+	log := logging.NewLogrLogger(zapr.NewLogger(zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.AddSync(&logs), zapcore.Level(-2)))))
+	events := &eventRecorder{}
+	secret := "secret-userdata-marker"
+	e := &external{
+		kube: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{"cloud-init": []byte("#!/bin/sh\necho " + secret)}
+				return nil
+			}),
+			MockUpdate: test.NewMockUpdateFn(nil),
+		},
+		client:   api,
+		log:      log,
+		recorder: clients.NewRedactingRecorder(events),
+	}
+	conn := redact.NewConnecter(managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return e, nil
+	}))
+
+	userdata, customdata := "#cloud-config\npassword: userdata-marker", `{"token":"customdata-marker"}`
+	inline := device(withUID(deviceUID), func(d *v1alpha2.Device) {
+		d.Spec.ForProvider.UserData = &userdata
+		d.Spec.ForProvider.CustomData = &customdata
+	})
+	ref := device(withUID(deviceUID), func(d *v1alpha2.Device) {
+		d.Spec.ForProvider.UserDataRef = &v1alpha2.DataKeySelector{Kind: "Secret", NamespacedName: v1alpha2.NamespacedName{Namespace: namespace, Name: "userdata"}}
+	})
+
+	// reconcile creates or updates the supplied Device, and logs and
+	// records the errors it returns like the managed reconciler does.
+	reconcile := func(d *v1alpha2.Device) {
+		ec, err := conn.Connect(context.Background(), d)
+		if err != nil {
+			t.Fatalf("Connect(...): %s", err)
+		}
+		o, err := ec.Observe(context.Background(), d)
+		if err == nil {
+			switch {
+			case !o.ResourceExists:
+				_, err = ec.Create(context.Background(), d)
+			case !o.ResourceUpToDate:
+				_, err = ec.Update(context.Background(), d)
+			}
+		}
+		if err == nil {
+			t.Fatalf("want the API to reject the Device")
+		}
+		log.Debug("Cannot reconcile Device", "error", err)
+		events.Event(d, event.Warning("CannotReconcile", err))
+		d.SetConditions(xpv1.ReconcileError(err))
+	}
+
+	reconcile(inline)
+	reconcile(ref)
+	meta.SetExternalName(inline, deviceID)
+	reconcile(inline)
+
+	status, _ := json.Marshal([]interface{}{inline.Status, ref.Status})
+	output := logs.String() + string(status)
+	for _, ev := range events.events {
+		output += ev.Message
+	}
+	for _, marker := range []string{"userdata-marker", "customdata-marker", secret} {
+		if strings.Contains(output, marker) {
+			t.Errorf("%q appears in the logs, events or status:\n%s", marker, output)
+		}
+	}
+	if !strings.Contains(output, "invalid userdata or customdata") {
+		t.Errorf("want the API errors in the logs, events or status:\n%s", output)
+	}
+}
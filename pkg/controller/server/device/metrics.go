@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+const errListDevices = "cannot list Devices"
+
+// stateUnknown is the state of Devices that were not observed yet.
+const stateUnknown = "unknown"
+
+var devicesDesc = prometheus.NewDesc(
+	"equinix_metal_devices",
+	"Number of Devices managed by the provider, by state, metro and plan.",
+	[]string{"state", "metro", "plan"}, nil,
+)
+
+// A stateCollector exports the number of managed Devices by state, metro and
+// plan. It counts the Devices in the cache of the controller when scraped, so
+// the counts follow the status written by the Device controller and deleted
+// Devices are never reported.
+type stateCollector struct {
+	reader client.Reader
+}
+
+// registerStateCollector registers a stateCollector reading Devices from the
+// supplied reader. Registering it more than once has no effect.
+func registerStateCollector(reg prometheus.Registerer, r client.Reader) error {
+	err := reg.Register(&stateCollector{reader: r})
+	if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return nil
+	}
+	return err
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- devicesDesc
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	l := &v1alpha2.DeviceList{}
+	if err := c.reader.List(context.Background(), l); err != nil {
+		ch <- prometheus.NewInvalidMetric(devicesDesc, errors.Wrap(err, errListDevices))
+		return
+	}
+	al := &emserverv1beta1.DeviceList{}
+	if err := c.reader.List(context.Background(), al); err != nil {
+		ch <- prometheus.NewInvalidMetric(devicesDesc, errors.Wrap(err, errListDevices))
+		return
+	}
+
+	// Devices of the equinixmetal.crossplane.io group share the spec and
+	// status of the metal.equinix.com Devices, so both are counted alike.
+	type key struct{ state, metro, plan string }
+	counts := map[key]float64{}
+	count := func(spec v1alpha2.DeviceSpec, status v1alpha2.DeviceStatus) {
+		k := key{state: status.AtProvider.State, metro: status.AtProvider.Metro, plan: spec.ForProvider.Plan}
+		if k.state == "" {
+			k.state = stateUnknown
+		}
+		if k.metro == "" {
+			k.metro = spec.ForProvider.Metro
+		}
+		counts[k]++
+	}
+	for _, d := range l.Items {
+		count(d.Spec, d.Status)
+	}
+	for _, d := range al.Items {
+		count(d.Spec, d.Status)
+	}
+	for k, n := range counts {
+		ch <- prometheus.MustNewConstMetric(devicesDesc, prometheus.GaugeValue, n, k.state, k.metro, k.plan)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+func TestStateCollector(t *testing.T) {
+	devices := []v1alpha2.Device{
+		{Spec: v1alpha2.DeviceSpec{ForProvider: v1alpha2.DeviceParameters{Plan: "c3.small.x86", Metro: "sv"}}},
+		{
+			Spec:   v1alpha2.DeviceSpec{ForProvider: v1alpha2.DeviceParameters{Plan: "c3.small.x86"}},
+			Status: v1alpha2.DeviceStatus{AtProvider: v1alpha2.DeviceObservation{State: v1alpha2.StateActive, Metro: "sv"}},
+		},
+		{
+			Spec:   v1alpha2.DeviceSpec{ForProvider: v1alpha2.DeviceParameters{Plan: "c3.small.x86", Metro: "sv"}},
+			Status: v1alpha2.DeviceStatus{AtProvider: v1alpha2.DeviceObservation{State: v1alpha2.StateActive, Metro: "sv"}},
+		},
+		{
+			Spec:   v1alpha2.DeviceSpec{ForProvider: v1alpha2.DeviceParameters{Plan: "m3.large.x86", Metro: "da"}},
+			Status: v1alpha2.DeviceStatus{AtProvider: v1alpha2.DeviceObservation{State: v1alpha2.StateFailed, Metro: "da"}},
+		},
+	}
+	aliases := []emserverv1beta1.Device{
+		{
+			Spec:   v1alpha2.DeviceSpec{ForProvider: v1alpha2.DeviceParameters{Plan: "m3.large.x86", Metro: "da"}},
+			Status: v1alpha2.DeviceStatus{AtProvider: v1alpha2.DeviceObservation{State: v1alpha2.StateActive, Metro: "da"}},
+		},
+		{
+			Spec:   v1alpha2.DeviceSpec{ForProvider: v1alpha2.DeviceParameters{Plan: "c3.small.x86", Metro: "sv"}},
+			Status: v1alpha2.DeviceStatus{AtProvider: v1alpha2.DeviceObservation{State: v1alpha2.StateActive, Metro: "sv"}},
+		},
+	}
+	c := &stateCollector{reader: &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *v1alpha2.DeviceList:
+				l.Items = devices
+			case *emserverv1beta1.DeviceList:
+				l.Items = aliases
+			}
+			return nil
+		},
+	}}
+
+	want := `
+# HELP equinix_metal_devices Number of Devices managed by the provider, by state, metro and plan.
+# TYPE equinix_metal_devices gauge
+equinix_metal_devices{metro="da",plan="m3.large.x86",state="active"} 1
+equinix_metal_devices{metro="da",plan="m3.large.x86",state="failed"} 1
+equinix_metal_devices{metro="sv",plan="c3.small.x86",state="active"} 3
+equinix_metal_devices{metro="sv",plan="c3.small.x86",state="unknown"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
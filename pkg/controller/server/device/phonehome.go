@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"github.com/packethost/packngo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+)
+
+// AnnotationKeyWaitForPhoneHome is the annotation of a Device that, when
+// "true", keeps the Device from becoming available until its device phones
+// home, rather than as soon as the device is active.
+const AnnotationKeyWaitForPhoneHome = "metal.equinix.com/wait-for-phone-home"
+
+// msgWaitingForPhoneHome is the message of the Ready condition of an active
+// Device that waits for its device to phone home.
+const msgWaitingForPhoneHome = "Waiting for the device to phone home"
+
+// waitsForPhoneHome returns true if the supplied Device is not available
+// until its device phones home.
+func waitsForPhoneHome(d *v1alpha2.Device) bool {
+	return d.GetAnnotations()[AnnotationKeyWaitForPhoneHome] == "true"
+}
+
+// reprovisioning returns true if the supplied device is being provisioned,
+// after which it phones home again.
+func reprovisioning(device *packngo.Device) bool {
+	switch device.State {
+	case v1alpha2.StateQueued, v1alpha2.StateProvisioning, v1alpha2.StateReinstalling:
+		return true
+	}
+	return false
+}
+
+// phonedHome returns true if the device of the supplied Device phoned home,
+// recording when in the status of the Device the first time it is observed.
+// The events of the device are listed until then. They are only
+// informational, so failing to list them only delays the Device becoming
+// available.
+func (e *external) phonedHome(d *v1alpha2.Device, device *packngo.Device) bool {
+	if d.Status.AtProvider.PhonedHomeAt != nil {
+		return true
+	}
+	events, _, err := e.client.ListEvents(device.ID, devicesclient.RecentEventsOptions())
+	if err != nil {
+		e.log.Debug("Cannot list Device events", "id", device.ID, "error", err)
+		return false
+	}
+	ev := devicesclient.PhoneHome(events)
+	if ev == nil {
+		return false
+	}
+	t := metav1.Now()
+	if ev.CreatedAt != nil {
+		t = metav1.NewTime(ev.CreatedAt.Time)
+	}
+	d.Status.AtProvider.PhonedHomeAt = &t
+	return true
+}
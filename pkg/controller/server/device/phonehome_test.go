@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package device
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device/fake"
+)
+
+func withWaitForPhoneHome() deviceModifier {
+	return func(i *v1alpha2.Device) {
+		meta.AddAnnotations(i, map[string]string{AnnotationKeyWaitForPhoneHome: "true"})
+	}
+}
+
+func withPhonedHomeAt(t metav1.Time) deviceModifier {
+	return func(i *v1alpha2.Device) { i.Status.AtProvider.PhonedHomeAt = &t }
+}
+
+func TestPhonedHome(t *testing.T) {
+	phoned := metav1.NewTime(time.Date(2021, time.June, 1, 12, 30, 0, 0, time.UTC))
+	errBoom := errors.New("boom")
+
+	type want struct {
+		mg         *v1alpha2.Device
+		phonedHome bool
+	}
+	cases := map[string]struct {
+		mg     *v1alpha2.Device
+		events []packngo.Event
+		err    error
+		want   want
+	}{
+		"PhonedHome": {
+			mg: device(withWaitForPhoneHome()),
+			events: []packngo.Event{
+				{Type: "provisioning.110", Interpolated: "Device phoned home", CreatedAt: &packngo.Timestamp{Time: phoned.Time}},
+				{Type: "provisioning.101", Interpolated: "Provision started"},
+			},
+			want: want{mg: device(withWaitForPhoneHome(), withPhonedHomeAt(phoned)), phonedHome: true},
+		},
+		"NotYet": {
+			mg:     device(withWaitForPhoneHome()),
+			events: []packngo.Event{{Type: "provisioning.109", Interpolated: "Installation finished, rebooting server"}},
+			want:   want{mg: device(withWaitForPhoneHome())},
+		},
+		"AlreadyPhonedHome": {
+			mg:   device(withWaitForPhoneHome(), withPhonedHomeAt(phoned)),
+			err:  errBoom,
+			want: want{mg: device(withWaitForPhoneHome(), withPhonedHomeAt(phoned)), phonedHome: true},
+		},
+		"CannotListEvents": {
+			mg:   device(withWaitForPhoneHome()),
+			err:  errBoom,
+			want: want{mg: device(withWaitForPhoneHome())},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{log: logging.NewNopLogger(), recorder: event.NewNopRecorder(), client: &fake.MockClient{
+				ListEventsFunc: func(deviceID string, listOpt *packngo.ListOptions) ([]packngo.Event, *packngo.Response, error) {
+					return tc.events, nil, tc.err
+				},
+			}}
+			got := e.phonedHome(tc.mg, &packngo.Device{ID: "d", State: v1alpha2.StateActive})
+
+			if diff := cmp.Diff(tc.want.phonedHome, got); diff != "" {
+				t.Errorf("e.phonedHome(): -want, +got:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, tc.mg); diff != "" {
+				t.Errorf("e.phonedHome(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
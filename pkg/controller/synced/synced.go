@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package synced records in the status of managed resources which generation
+// of their spec was last observed to be in sync with their external resource,
+// and when their external resource was last observed. Consumers compare the
+// observed generation with metadata.generation to tell whether the status
+// reflects the latest edit of the spec.
+package synced
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+// A Resource records which generation of its spec its status reflects.
+type Resource interface {
+	GetSyncStatus() *v1beta1.SyncStatus
+}
+
+// NewConnecter returns an ExternalConnecter whose external clients record the
+// sync status of every managed resource they successfully observe.
+func NewConnecter(c managed.ExternalConnecter) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c, now: metav1.Now}
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+	now func() metav1.Time
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.ExternalConnecter.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{ExternalClient: ec, now: c.now}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+	now func() metav1.Time
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	r, ok := mg.(Resource)
+	if err != nil || !ok {
+		return o, err
+	}
+	s := r.GetSyncStatus()
+	now := e.now()
+	s.LastSync = &now
+	if o.ResourceExists && o.ResourceUpToDate {
+		s.ObservedGeneration = mg.GetGeneration()
+	}
+	return o, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synced
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+var errBoom = errors.New("boom")
+
+func TestObserve(t *testing.T) {
+	now := metav1.NewTime(time.Unix(1600000000, 0))
+	before := metav1.NewTime(time.Unix(1500000000, 0))
+
+	type want struct {
+		status v1beta1.SyncStatus
+		err    error
+	}
+
+	cases := map[string]struct {
+		o    managed.ExternalObservation
+		err  error
+		want want
+	}{
+		"UpToDate": {
+			o:    managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{status: v1beta1.SyncStatus{ObservedGeneration: 3, LastSync: &now}},
+		},
+		"NotUpToDate": {
+			o:    managed.ExternalObservation{ResourceExists: true},
+			want: want{status: v1beta1.SyncStatus{ObservedGeneration: 2, LastSync: &now}},
+		},
+		"ObserveFailed": {
+			err:  errBoom,
+			want: want{status: v1beta1.SyncStatus{ObservedGeneration: 2, LastSync: &before}, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &v1alpha1.VirtualNetwork{}
+			v.SetGeneration(3)
+			v.Status.ObservedGeneration = 2
+			v.Status.LastSync = &before
+
+			c := &connecter{
+				ExternalConnecter: managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+					return managed.ExternalClientFns{
+						ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+							return tc.o, tc.err
+						},
+					}, nil
+				}),
+				now: func() metav1.Time { return now },
+			}
+			e, err := c.Connect(context.Background(), v)
+			if err != nil {
+				t.Fatalf("Connect(...): %s", err)
+			}
+			_, err = e.Observe(context.Background(), v)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Observe(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.status, v.Status.SyncStatus); diff != "" {
+				t.Errorf("Observe(...): -want status, +got status:\n%s", diff)
+			}
+		})
+	}
+}
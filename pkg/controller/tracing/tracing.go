@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing records OpenTelemetry spans of the reconciles of managed
+// resources and of the external client calls made during them. Together with
+// the spans of the API requests recorded by the Equinix Metal clients they
+// trace a reconcile from start to finish.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Span attributes of managed resources.
+const (
+	AttributeKind         = attribute.Key("crossplane.kind")
+	AttributeName         = attribute.Key("crossplane.name")
+	AttributeExternalName = attribute.Key("crossplane.external_name")
+)
+
+// NewReconciler returns a Reconciler that records a span of every reconcile
+// of the supplied kind of managed resource.
+func NewReconciler(r reconcile.Reconciler, kind string) reconcile.Reconciler {
+	return &reconciler{Reconciler: r, kind: kind, tracer: otel.Tracer(clients.TracerName)}
+}
+
+// NewConnecter returns an ExternalConnecter whose external clients record a
+// span of every call of the supplied kind of managed resource. The API
+// requests sent during a call are recorded as its children.
+func NewConnecter(c managed.ExternalConnecter, kind string) managed.ExternalConnecter {
+	return &connecter{ExternalConnecter: c, kind: kind, tracer: otel.Tracer(clients.TracerName)}
+}
+
+type reconciler struct {
+	reconcile.Reconciler
+	kind   string
+	tracer trace.Tracer
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := r.tracer.Start(ctx, "Reconcile "+r.kind, trace.WithAttributes(
+		AttributeKind.String(r.kind),
+		AttributeName.String(req.Name),
+	))
+	res, err := r.Reconciler.Reconcile(ctx, req)
+	end(span, err)
+	return res, err
+}
+
+type connecter struct {
+	managed.ExternalConnecter
+	kind   string
+	tracer trace.Tracer
+}
+
+func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	parent := &clients.TraceParent{}
+	ec, err := c.ExternalConnecter.Connect(clients.WithTraceParent(ctx, parent), mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{ExternalClient: ec, kind: c.kind, tracer: c.tracer, parent: parent}, nil
+}
+
+type external struct {
+	managed.ExternalClient
+	kind   string
+	tracer trace.Tracer
+	parent *clients.TraceParent
+}
+
+// start a span of the named call, and make it the parent of the API requests
+// sent until it ends.
+func (e *external) start(ctx context.Context, call string, mg resource.Managed) (context.Context, trace.Span) {
+	ctx, span := e.tracer.Start(ctx, call+" "+e.kind, trace.WithAttributes(
+		AttributeKind.String(e.kind),
+		AttributeName.String(mg.GetName()),
+		AttributeExternalName.String(meta.GetExternalName(mg)),
+	))
+	e.parent.Set(span)
+	return ctx, span
+}
+
+// finish the supplied span of a call that returned the supplied error.
+func (e *external) finish(span trace.Span, err error) {
+	e.parent.Set(nil)
+	end(span, err)
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := e.start(ctx, "Observe", mg)
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	e.finish(span, err)
+	return o, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := e.start(ctx, "Create", mg)
+	c, err := e.ExternalClient.Create(ctx, mg)
+	e.finish(span, err)
+	return c, err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := e.start(ctx, "Update", mg)
+	u, err := e.ExternalClient.Update(ctx, mg)
+	e.finish(span, err)
+	return u, err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, span := e.start(ctx, "Delete", mg)
+	err := e.ExternalClient.Delete(ctx, mg)
+	e.finish(span, err)
+	return err
+}
+
+// end the supplied span, recording the supplied error if any.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetwork
+
+import (
+	"context"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	emportsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/ports/v1alpha1"
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+// assignmentsField indexes the Assignments of both API groups by the
+// VirtualNetworks they assign to ports, keyed by ID and by name.
+const assignmentsField = "spec.forProvider.virtualNetwork"
+
+func idKey(id string) string     { return "id/" + id }
+func nameKey(name string) string { return "name/" + name }
+
+// assignmentKeys returns the assignmentsField index keys of the supplied
+// Assignment parameters.
+func assignmentKeys(p portsv1alpha1.AssignmentParameters) []string {
+	keys := []string{}
+	if p.VirtualNetworkID != "" {
+		keys = append(keys, idKey(p.VirtualNetworkID))
+	}
+	if p.VirtualNetworkIDRef != nil {
+		keys = append(keys, nameKey(p.VirtualNetworkIDRef.Name))
+	}
+	return keys
+}
+
+// assignmentParameters returns the parameters of the supplied Assignment of
+// either API group.
+func assignmentParameters(o client.Object) (portsv1alpha1.AssignmentParameters, bool) {
+	switch a := o.(type) {
+	case *portsv1alpha1.Assignment:
+		return a.Spec.ForProvider, true
+	case *emportsv1alpha1.Assignment:
+		return a.Spec.ForProvider, true
+	}
+	return portsv1alpha1.AssignmentParameters{}, false
+}
+
+// indexAssignments adds the assignmentsField index to the Assignments of both
+// API groups.
+func indexAssignments(ctx context.Context, i client.FieldIndexer) error {
+	keys := func(o client.Object) []string {
+		p, ok := assignmentParameters(o)
+		if !ok {
+			return nil
+		}
+		return assignmentKeys(p)
+	}
+	for _, o := range []client.Object{&portsv1alpha1.Assignment{}, &emportsv1alpha1.Assignment{}} {
+		if err := i.IndexField(ctx, o, assignmentsField, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignedBy returns the names of the Assignments, of either API group, that
+// assign the supplied VirtualNetwork to a port, either by ID or by reference.
+// The names of Assignments of the equinixmetal.crossplane.io API group are
+// qualified by their group kind.
+func assignedBy(ctx context.Context, kube client.Reader, v *v1alpha1.VirtualNetwork) ([]string, error) {
+	keys := []string{nameKey(v.GetName())}
+	if id := meta.GetExternalName(v); id != "" {
+		keys = []string{idKey(id), nameKey(v.GetName())}
+	}
+	names := []string{}
+	seen := map[string]bool{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, k := range keys {
+		l := &portsv1alpha1.AssignmentList{}
+		if err := kube.List(ctx, l, client.MatchingFields{assignmentsField: k}); err != nil {
+			return nil, err
+		}
+		for _, a := range l.Items {
+			add(a.GetName())
+		}
+	}
+	for _, k := range keys {
+		l := &emportsv1alpha1.AssignmentList{}
+		if err := kube.List(ctx, l, client.MatchingFields{assignmentsField: k}); err != nil {
+			return nil, err
+		}
+		for _, a := range l.Items {
+			add(emportsv1alpha1.AssignmentGroupKind + "/" + a.GetName())
+		}
+	}
+	return names, nil
+}
+
+// enqueueAssigned returns an event handler that enqueues the VirtualNetworks,
+// listed by the supplied function, that an Assignment of either API group
+// assigns to a port. A VirtualNetwork whose deletion waits for its
+// Assignments is thus deleted as soon as the last of them is gone.
+func enqueueAssigned(kube client.Reader, newList func() client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(assigned(kube, newList))
+}
+
+// assigned returns a function that maps an Assignment to the requests of the
+// VirtualNetworks it assigns, by reference or by ID.
+func assigned(kube client.Reader, newList func() client.ObjectList) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		p, ok := assignmentParameters(o)
+		if !ok {
+			return nil
+		}
+		reqs := []reconcile.Request{}
+		if p.VirtualNetworkIDRef != nil {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: p.VirtualNetworkIDRef.Name}})
+		}
+		if p.VirtualNetworkID == "" {
+			return reqs
+		}
+		l := newList()
+		if err := kube.List(context.Background(), l); err != nil {
+			return reqs
+		}
+		items, err := kmeta.ExtractList(l)
+		if err != nil {
+			return reqs
+		}
+		for _, i := range items {
+			if v, ok := i.(client.Object); ok && meta.GetExternalName(v) == p.VirtualNetworkID {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: v.GetName()}})
+			}
+		}
+		return reqs
+	}
+}
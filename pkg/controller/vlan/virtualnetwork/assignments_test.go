@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetwork
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+func TestAssigned(t *testing.T) {
+	other := v1alpha1.VirtualNetwork{}
+	other.SetName("other")
+	meta.SetExternalName(&other, "0c4e2a6b-1d3f-4a5c-8e7b-9f1a2b3c4d5e")
+	kube := &test.MockClient{
+		MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			list.(*v1alpha1.VirtualNetworkList).Items = []v1alpha1.VirtualNetwork{other, *virtualNetwork()}
+			return nil
+		},
+	}
+	newList := func() client.ObjectList { return &v1alpha1.VirtualNetworkList{} }
+	req := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+	}
+
+	cases := map[string]struct {
+		o    client.Object
+		want []reconcile.Request
+	}{
+		"ByID": {
+			o: func() client.Object {
+				a := assignment("by-id", func(p *portsv1alpha1.AssignmentParameters) { p.VirtualNetworkID = vlanID })
+				return &a
+			}(),
+			want: []reconcile.Request{req(vlanName)},
+		},
+		"ByReference": {
+			o: func() client.Object {
+				a := assignment("by-ref", func(p *portsv1alpha1.AssignmentParameters) {
+					p.VirtualNetworkIDRef = &xpv1.Reference{Name: vlanName}
+				})
+				return &a
+			}(),
+			want: []reconcile.Request{req(vlanName)},
+		},
+		"Alias": {
+			o: func() client.Object {
+				a := aliasAssignment("alias", func(p *portsv1alpha1.AssignmentParameters) { p.VirtualNetworkID = vlanID })
+				return &a
+			}(),
+			want: []reconcile.Request{req(vlanName)},
+		},
+		"Unassigned": {
+			o: func() client.Object {
+				a := assignment("unassigned", func(p *portsv1alpha1.AssignmentParameters) {})
+				return &a
+			}(),
+			want: []reconcile.Request{},
+		},
+		"NotAnAssignment": {
+			o: &v1alpha1.VirtualNetwork{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := assigned(kube, newList)(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("assigned(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
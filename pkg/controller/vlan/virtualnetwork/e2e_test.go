@@ -0,0 +1,86 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetwork
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+	vlanclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+// TestE2E creates and deletes a real VirtualNetwork.
+func TestE2E(t *testing.T) {
+	creds := packettest.E2E(t)
+	ctx := context.Background()
+	c, err := vlanclient.NewClient(ctx, creds)
+	if err != nil {
+		t.Fatalf("NewClient(...): %s", err)
+	}
+	e := &external{
+		kube:     &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+		client:   c,
+		log:      logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+
+	description := "crossplane-e2e"
+	v := &v1alpha1.VirtualNetwork{}
+	v.SetName("crossplane-e2e")
+	v.Spec.ForProvider = v1alpha1.VirtualNetworkParameters{
+		Metro:       packettest.E2EMetro(),
+		Description: &description,
+	}
+
+	if _, err := e.Create(ctx, v); err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+	defer func() {
+		if err := e.Delete(ctx, v); err != nil {
+			t.Errorf("Delete(...): %s", err)
+		}
+	}()
+
+	o, err := e.Observe(ctx, v)
+	if err != nil {
+		t.Fatalf("Observe(...): %s", err)
+	}
+	if !o.ResourceExists || !o.ResourceUpToDate {
+		t.Errorf("Observe(...): want created VirtualNetwork to exist and be up to date, got %+v", o)
+	}
+	if v.Spec.ForProvider.VXLAN == 0 {
+		t.Errorf("Observe(...): want VXLAN to be late initialized")
+	}
+
+	if err := e.Delete(ctx, v); err != nil {
+		t.Fatalf("Delete(...): %s", err)
+	}
+	packettest.Eventually(t, 5*time.Minute, 10*time.Second, func() (bool, error) {
+		o, err := e.Observe(ctx, v)
+		return !o.ResourceExists, err
+	})
+}
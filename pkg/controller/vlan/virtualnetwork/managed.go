@@ -18,17 +18,26 @@ package virtualnetwork
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	emportsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/ports/v1alpha1"
+	emvlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/vlan/v1alpha1"
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
 	packetv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
 	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
 	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	packetclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
 	vlanclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/alias"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/options"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/controller/reconciler"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -49,32 +58,77 @@ const (
 	errGetVirtualNetwork       = "cannot get VirtualNetwork"
 	errCreateVirtualNetwork    = "cannot create VirtualNetwork"
 	errDeleteVirtualNetwork    = "cannot delete VirtualNetwork"
+	errListAssignments         = "cannot list Assignments"
+	errIndexAssignments        = "cannot index Assignments by VirtualNetwork"
+	errWatchAssignments        = "cannot watch Assignments"
+	errInUseFmt                = "VirtualNetwork is still assigned to ports by Assignments %s: delete them first"
+	errAllocateVXLAN           = "cannot allocate VXLAN ID from VLANPool"
+	errReleaseVXLAN            = "cannot release VXLAN ID to VLANPool"
 )
 
-// SetupVirtualNetwork adds a controller that reconciles VirtualNetworks
-func SetupVirtualNetwork(mgr ctrl.Manager, l logging.Logger) error {
+// SetupVirtualNetwork adds controllers that reconcile the VirtualNetworks of
+// the vlan.metal.equinix.com API group, and of the
+// vlan.equinixmetal.crossplane.io API group that aliases it.
+func SetupVirtualNetwork(mgr ctrl.Manager, o options.Options) error {
 	name := managed.ControllerName(v1alpha1.VirtualNetworkGroupKind)
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.VirtualNetworkGroupVersionKind),
-		managed.WithExternalConnecter(&connecter{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
-		}),
-		managed.WithConnectionPublishers(),
-		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-	)
+	if err := indexAssignments(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return errors.Wrap(err, errIndexAssignments)
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	recorder := clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
+
+	c := &connecter{
+		kube:     mgr.GetClient(),
+		reader:   mgr.GetAPIReader(),
+		usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:      o.Logger.WithValues("controller", name),
+		recorder: recorder,
+	}
+	err := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha1.VirtualNetwork{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &portsv1alpha1.Assignment{}}, enqueueAssigned(mgr.GetClient(), func() client.ObjectList { return &v1alpha1.VirtualNetworkList{} })).
+		Watches(&source.Kind{Type: &emportsv1alpha1.Assignment{}}, enqueueAssigned(mgr.GetClient(), func() client.ObjectList { return &v1alpha1.VirtualNetworkList{} })).
+		WithOptions(o.ControllerOptions()).
+		Complete(reconciler.NewManaged(mgr, o, name, v1alpha1.VirtualNetworkGroupVersionKind, c, recorder))
+	if err != nil {
+		return err
+	}
+
+	name = managed.ControllerName(emvlanv1alpha1.VirtualNetworkGroupKind)
+	recorder = clients.NewRedactingRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))
+	ac := &connecter{
+		kube:     alias.NewClient(mgr.GetClient(), aliasKind),
+		reader:   mgr.GetAPIReader(),
+		usage:    resource.NewProviderConfigUsageTracker(mgr.GetClient(), &packetv1beta1.ProviderConfigUsage{}),
+		log:      o.Logger.WithValues("controller", name),
+		recorder: recorder,
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&emvlanv1alpha1.VirtualNetwork{}).
+		Watches(&source.Kind{Type: &portsv1alpha1.Assignment{}}, enqueueAssigned(mgr.GetClient(), func() client.ObjectList { return &emvlanv1alpha1.VirtualNetworkList{} })).
+		Watches(&source.Kind{Type: &emportsv1alpha1.Assignment{}}, enqueueAssigned(mgr.GetClient(), func() client.ObjectList { return &emvlanv1alpha1.VirtualNetworkList{} })).
+		WithOptions(o.ControllerOptions()).
+		Complete(reconciler.NewManaged(mgr, o, name, emvlanv1alpha1.VirtualNetworkGroupVersionKind, alias.NewConnecter(ac, aliasKind), recorder))
+}
+
+// aliasKind is the VirtualNetwork of the vlan.equinixmetal.crossplane.io API
+// group, which aliases the VirtualNetwork of the vlan.metal.equinix.com API
+// group.
+var aliasKind = alias.Kind{
+	GroupVersionKind: emvlanv1alpha1.VirtualNetworkGroupVersionKind,
+	New:              func() alias.Object { return &emvlanv1alpha1.VirtualNetwork{} },
+	NewHub:           func() alias.Hub { return &v1alpha1.VirtualNetwork{} },
 }
 
 type connecter struct {
 	kube        client.Client
+	reader      client.Reader
 	usage       resource.Tracker
+	log         logging.Logger
+	recorder    event.Recorder
 	newClientFn func(ctx context.Context, config *clients.Credentials) (vlanclient.ClientWithDefaults, error)
 }
 
@@ -95,14 +149,18 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, errors.Wrap(err, errGetProviderConfigSecret)
 	}
-	client, err := newClientFn(ctx, cfg)
+	client, err := newClientFn(clients.WithController(ctx, managed.ControllerName(v1alpha1.VirtualNetworkGroupKind)), cfg)
 
-	return &external{kube: c.kube, client: client}, errors.Wrap(err, errNewClient)
+	log := c.log.WithValues("name", mg.GetName(), "uid", mg.GetUID())
+	return &external{kube: c.kube, reader: c.reader, client: client, log: log, recorder: c.recorder}, errors.Wrap(err, errNewClient)
 }
 
 type external struct {
-	kube   client.Client
-	client vlanclient.ClientWithDefaults
+	kube     client.Client
+	reader   client.Reader
+	client   vlanclient.ClientWithDefaults
+	log      logging.Logger
+	recorder event.Recorder
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -112,26 +170,37 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	// Observe virtual network
-	device, _, err := e.client.Get(meta.GetExternalName(v), nil)
+	device, _, err := e.client.Get(meta.GetExternalName(v), vlanclient.ObserveOptions())
 	if packetclient.IsNotFound(err) {
+		// A VirtualNetwork deleted before it was created is never asked to
+		// delete it, so it releases its VXLAN ID here.
+		if meta.WasDeleted(v) {
+			return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(release(ctx, e.kube, v), errReleaseVXLAN)
+		}
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
 	if err != nil {
+		recordRequestID(v, err)
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetVirtualNetwork)
 	}
 
 	current := v.Spec.ForProvider.DeepCopy()
 	vlanclient.LateInitialize(&v.Spec.ForProvider, device)
-	if !cmp.Equal(current, &v.Spec.ForProvider) {
+	// Empty and nil fields are written alike, so late initializing one as
+	// the other changes nothing worth writing.
+	if !cmp.Equal(current, &v.Spec.ForProvider, cmpopts.EquateEmpty()) {
 		if err := e.kube.Update(ctx, v); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errManagedUpdateFailed)
 		}
 	}
 
+	// The ID of the last failed request outlives successful observations.
+	lastRequestID := v.Status.AtProvider.LastRequestID
 	v.Status.AtProvider, err = vlanclient.GenerateObservation(device)
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGenObservation)
 	}
+	v.Status.AtProvider.LastRequestID = lastRequestID
 
 	v.Status.SetConditions(xpv1.Available())
 
@@ -151,12 +220,26 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	v.Status.SetConditions(xpv1.Creating())
 
+	if v.Spec.ForProvider.VXLAN == 0 && v.Spec.ForProvider.VXLANPoolRef != nil {
+		id, err := allocate(ctx, e.kube, e.reader, v)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errAllocateVXLAN)
+		}
+		v.Spec.ForProvider.VXLAN = id
+		if err := e.kube.Update(ctx, v); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errManagedUpdateFailed)
+		}
+	}
+
 	create := vlanclient.CreateFromVirtualNetwork(v, e.client.GetProjectID(packetclient.CredentialProjectID))
 	vlan, _, err := e.client.Create(create)
 	if err != nil {
+		packetclient.RecordAPIError(e.recorder, v, errCreateVirtualNetwork, err)
+		recordRequestID(v, err)
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
 	}
 
+	e.log.Debug("Created VirtualNetwork", "id", vlan.ID, "vxlan", vlan.VXLAN)
 	v.Status.AtProvider.ID = vlan.ID
 	meta.SetExternalName(v, vlan.ID)
 	if err := e.kube.Update(ctx, v); err != nil {
@@ -178,6 +261,30 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 	v.SetConditions(xpv1.Deleting())
 
-	_, err := e.client.Delete(meta.GetExternalName(v))
-	return errors.Wrap(resource.Ignore(packetclient.IsNotFound, err), errDeleteVirtualNetwork)
+	// A VirtualNetwork that is assigned to ports cannot be deleted, so its
+	// deletion waits until the Assignments using it are gone.
+	users, err := assignedBy(ctx, e.kube, v)
+	if err != nil {
+		return errors.Wrap(err, errListAssignments)
+	}
+	if len(users) > 0 {
+		return errors.Errorf(errInUseFmt, strings.Join(users, ", "))
+	}
+
+	_, err = e.client.Delete(meta.GetExternalName(v))
+	err = resource.Ignore(packetclient.IsNotFound, err)
+	packetclient.RecordAPIError(e.recorder, v, errDeleteVirtualNetwork, err)
+	recordRequestID(v, err)
+	if err != nil {
+		return errors.Wrap(err, errDeleteVirtualNetwork)
+	}
+	return errors.Wrap(release(ctx, e.kube, v), errReleaseVXLAN)
+}
+
+// recordRequestID records the ID of the failed Equinix Metal API request
+// wrapped by err, if any, in the status of the supplied VirtualNetwork.
+func recordRequestID(v *v1alpha1.VirtualNetwork, err error) {
+	if id := packetclient.RequestID(err); id != "" {
+		v.Status.AtProvider.LastRequestID = id
+	}
 }
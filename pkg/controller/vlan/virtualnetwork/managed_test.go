@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetwork
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	emportsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/ports/v1alpha1"
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+	vlanclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan/fake"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+const (
+	vlanName = "my-cool-vlan"
+	vlanID   = "5d3c6a1e-8b2f-4e7a-9c1d-3f6b0a2e4c8d"
+)
+
+var errBoom = errors.New("boom")
+
+func virtualNetwork() *v1alpha1.VirtualNetwork {
+	v := &v1alpha1.VirtualNetwork{}
+	v.SetName(vlanName)
+	meta.SetExternalName(v, vlanID)
+	return v
+}
+
+func assignment(name string, fn func(p *portsv1alpha1.AssignmentParameters)) portsv1alpha1.Assignment {
+	a := portsv1alpha1.Assignment{}
+	a.SetName(name)
+	fn(&a.Spec.ForProvider)
+	return a
+}
+
+func aliasAssignment(name string, fn func(p *portsv1alpha1.AssignmentParameters)) emportsv1alpha1.Assignment {
+	a := emportsv1alpha1.Assignment{}
+	a.SetName(name)
+	fn(&a.Spec.ForProvider)
+	return a
+}
+
+// indexed returns true if Assignments with the supplied parameters are found
+// by the supplied key of the assignmentsField index.
+func indexed(p portsv1alpha1.AssignmentParameters, key string) bool {
+	for _, k := range assignmentKeys(p) {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDelete(t *testing.T) {
+	type want struct {
+		err     error
+		deleted bool
+	}
+
+	cases := map[string]struct {
+		assignments []portsv1alpha1.Assignment
+		aliases     []emportsv1alpha1.Assignment
+		listErr     error
+		want        want
+	}{
+		"NotAssigned": {
+			assignments: []portsv1alpha1.Assignment{
+				assignment("other", func(p *portsv1alpha1.AssignmentParameters) { p.VirtualNetworkID = "0c4e2a6b-1d3f-4a5c-8e7b-9f1a2b3c4d5e" }),
+			},
+			want: want{deleted: true},
+		},
+		"AssignedByIDAndReference": {
+			assignments: []portsv1alpha1.Assignment{
+				assignment("by-id", func(p *portsv1alpha1.AssignmentParameters) { p.VirtualNetworkID = vlanID }),
+				assignment("by-ref", func(p *portsv1alpha1.AssignmentParameters) {
+					p.VirtualNetworkIDRef = &xpv1.Reference{Name: vlanName}
+				}),
+			},
+			want: want{err: errors.Errorf(errInUseFmt, "by-id, by-ref")},
+		},
+		"AssignedByAlias": {
+			aliases: []emportsv1alpha1.Assignment{
+				aliasAssignment("alias", func(p *portsv1alpha1.AssignmentParameters) { p.VirtualNetworkID = vlanID }),
+			},
+			want: want{err: errors.Errorf(errInUseFmt, emportsv1alpha1.AssignmentGroupKind+"/alias")},
+		},
+		"ListFailed": {
+			listErr: errBoom,
+			want:    want{err: errors.Wrap(errBoom, errListAssignments)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			e := &external{
+				log:      logging.NewNopLogger(),
+				recorder: event.NewNopRecorder(),
+				kube: &test.MockClient{
+					MockList: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+						lo := &client.ListOptions{}
+						lo.ApplyOptions(opts)
+						key, _ := lo.FieldSelector.RequiresExactMatch(assignmentsField)
+						switch l := list.(type) {
+						case *portsv1alpha1.AssignmentList:
+							for _, a := range tc.assignments {
+								if indexed(a.Spec.ForProvider, key) {
+									l.Items = append(l.Items, a)
+								}
+							}
+						case *emportsv1alpha1.AssignmentList:
+							for _, a := range tc.aliases {
+								if indexed(a.Spec.ForProvider, key) {
+									l.Items = append(l.Items, a)
+								}
+							}
+						}
+						return tc.listErr
+					},
+				},
+				client: &fake.MockClient{
+					DeleteFunc: func(virtualNetworkID string) (*packngo.Response, error) {
+						deleted = true
+						return nil, nil
+					},
+				},
+			}
+
+			err := e.Delete(context.Background(), virtualNetwork())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("Delete(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("Delete(...): -want deleted, +got deleted:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChaos(t *testing.T) {
+	srv := packettest.NewMetalServer()
+	defer srv.Close()
+	defer srv.Install()()
+
+	ctx := context.Background()
+	c, err := vlanclient.NewClient(ctx, srv.Credentials())
+	if err != nil {
+		t.Fatalf("NewClient(...): %s", err)
+	}
+
+	// Faults that lose a VirtualNetwork that was created are left out; it
+	// cannot be found again by anything but its ID.
+	faults := packettest.NewFaults().
+		Add("Create", packettest.APIError(http.StatusInternalServerError), packettest.RateLimited(time.Second), packettest.Slow(10*time.Millisecond)).
+		Add("Get",
+			packettest.RateLimited(time.Second),
+			packettest.APIError(http.StatusBadGateway),
+			packettest.Partial(),
+			packettest.Slow(10*time.Millisecond),
+		).
+		Add("Delete", packettest.APIError(http.StatusServiceUnavailable), packettest.LostResponse(http.StatusGatewayTimeout))
+
+	e := &external{
+		kube: &test.MockClient{
+			MockUpdate: test.NewMockUpdateFn(nil),
+			MockList:   test.NewMockListFn(nil),
+		},
+		client:   fake.NewFaultyClient(c, faults),
+		log:      logging.NewNopLogger(),
+		recorder: event.NewNopRecorder(),
+	}
+
+	v := virtualNetwork()
+	meta.SetExternalName(v, "")
+	v.Spec.ForProvider.Metro = "sv"
+
+	// converge reconciles the VirtualNetwork like the managed reconciler
+	// does, retrying whatever failed, until it exists or is deleted.
+	converge := func(deleted bool) {
+		t.Helper()
+		for i := 0; i < 50; i++ {
+			o, err := e.Observe(ctx, v)
+			switch {
+			case err != nil:
+				continue
+			case deleted && o.ResourceExists:
+				_ = e.Delete(ctx, v)
+			case deleted:
+				return
+			case !o.ResourceExists:
+				_, _ = e.Create(ctx, v)
+			case v.Status.AtProvider.ID != "":
+				return
+			}
+		}
+		t.Fatalf("VirtualNetwork did not converge: %+v", v.Status.AtProvider)
+	}
+
+	converge(false)
+	if diff := cmp.Diff(xpv1.Available(), v.Status.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+		t.Errorf("Observe(...): -want condition, +got:\n%s", diff)
+	}
+	converge(true)
+
+	if diff := cmp.Diff(0, faults.Remaining()); diff != "" {
+		t.Errorf("Faults: -want remaining, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, srv.Calls("POST", "/projects/{id}/virtual-networks")); diff != "" {
+		t.Errorf("Create(...): -want VirtualNetworks created, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetwork
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	emvlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/vlan/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+const (
+	errGetPool          = "cannot get VLANPool"
+	errListVLANs        = "cannot list VirtualNetworks"
+	errUpdatePool       = "cannot update VLANPool status"
+	errPoolMetroFmt     = "VLANPool %s allocates VXLAN IDs in metro %s, not %s"
+	errPoolExhaustedFmt = "VLANPool %s has no free VXLAN ID between %d and %d"
+)
+
+// allocate returns the VXLAN ID the VLANPool referenced by the supplied
+// VirtualNetwork allocated to it, allocating the lowest free ID of the pool if
+// it allocated none yet. The allocation is recorded in the status of the
+// pool, whose resource version makes concurrent allocations of the same ID
+// fail rather than collide. IDs allocated to VirtualNetworks that no longer
+// exist are reclaimed first; the supplied reader must not be a cache, lest it
+// miss a VirtualNetwork created moments ago.
+func allocate(ctx context.Context, kube client.Client, reader client.Reader, v *v1alpha1.VirtualNetwork) (int, error) {
+	p := &emvlanv1alpha1.VLANPool{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: v.Spec.ForProvider.VXLANPoolRef.Name}, p); err != nil {
+		return 0, errors.Wrap(err, errGetPool)
+	}
+	if p.Spec.Metro != "" && !strings.EqualFold(p.Spec.Metro, v.Spec.ForProvider.Metro) {
+		return 0, errors.Errorf(errPoolMetroFmt, p.GetName(), p.Spec.Metro, v.Spec.ForProvider.Metro)
+	}
+
+	for _, a := range p.Status.Allocations {
+		if a.UID == v.GetUID() {
+			return a.VXLAN, nil
+		}
+	}
+	allocs, err := reclaim(ctx, reader, p.Status.Allocations)
+	if err != nil {
+		return 0, errors.Wrap(err, errListVLANs)
+	}
+
+	used := map[int]bool{}
+	for _, a := range allocs {
+		used[a.VXLAN] = true
+	}
+	id := 0
+	for i := p.Spec.Start; i <= p.Spec.End; i++ {
+		if !used[i] {
+			id = i
+			break
+		}
+	}
+	if id == 0 {
+		return 0, errors.Errorf(errPoolExhaustedFmt, p.GetName(), p.Spec.Start, p.Spec.End)
+	}
+
+	p.Status.Allocations = append(allocs, emvlanv1alpha1.VXLANAllocation{VXLAN: id, Name: v.GetName(), UID: v.GetUID()})
+	sort.Slice(p.Status.Allocations, func(i, j int) bool {
+		return p.Status.Allocations[i].VXLAN < p.Status.Allocations[j].VXLAN
+	})
+	p.Status.Allocated = len(p.Status.Allocations)
+	return id, errors.Wrap(kube.Status().Update(ctx, p), errUpdatePool)
+}
+
+// reclaim returns the supplied allocations that belong to a VirtualNetwork of
+// either API group that still exists. A VirtualNetwork deleted with the Orphan
+// deletion policy is never asked to release its VXLAN ID, so its allocation
+// outlives it until reclaimed here.
+func reclaim(ctx context.Context, reader client.Reader, allocs []emvlanv1alpha1.VXLANAllocation) ([]emvlanv1alpha1.VXLANAllocation, error) {
+	if len(allocs) == 0 {
+		return allocs, nil
+	}
+	l := &v1alpha1.VirtualNetworkList{}
+	if err := reader.List(ctx, l); err != nil {
+		return nil, err
+	}
+	al := &emvlanv1alpha1.VirtualNetworkList{}
+	if err := reader.List(ctx, al); err != nil {
+		return nil, err
+	}
+	exists := map[types.UID]bool{}
+	for _, v := range l.Items {
+		exists[v.GetUID()] = true
+	}
+	for _, v := range al.Items {
+		exists[v.GetUID()] = true
+	}
+
+	kept := make([]emvlanv1alpha1.VXLANAllocation, 0, len(allocs))
+	for _, a := range allocs {
+		if exists[a.UID] {
+			kept = append(kept, a)
+		}
+	}
+	return kept, nil
+}
+
+// release removes the allocation of the supplied VirtualNetwork from the
+// VLANPool it references, if any. A missing pool has nothing to release.
+func release(ctx context.Context, kube client.Client, v *v1alpha1.VirtualNetwork) error {
+	if v.Spec.ForProvider.VXLANPoolRef == nil {
+		return nil
+	}
+	p := &emvlanv1alpha1.VLANPool{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: v.Spec.ForProvider.VXLANPoolRef.Name}, p); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetPool)
+	}
+
+	kept := make([]emvlanv1alpha1.VXLANAllocation, 0, len(p.Status.Allocations))
+	for _, a := range p.Status.Allocations {
+		if a.UID != v.GetUID() {
+			kept = append(kept, a)
+		}
+	}
+	if len(kept) == len(p.Status.Allocations) {
+		return nil
+	}
+	p.Status.Allocations = kept
+	p.Status.Allocated = len(kept)
+	return errors.Wrap(kube.Status().Update(ctx, p), errUpdatePool)
+}
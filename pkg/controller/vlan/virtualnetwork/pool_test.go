@@ -0,0 +1,214 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virtualnetwork
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	emvlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/vlan/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+const (
+	poolName = "my-cool-pool"
+	vlanUID  = types.UID("a1b2c3d4")
+)
+
+func pooledVirtualNetwork() *v1alpha1.VirtualNetwork {
+	v := virtualNetwork()
+	v.SetUID(vlanUID)
+	v.Spec.ForProvider.Metro = "da"
+	v.Spec.ForProvider.VXLANPoolRef = &xpv1.Reference{Name: poolName}
+	return v
+}
+
+func pool(metro string, allocs ...emvlanv1alpha1.VXLANAllocation) emvlanv1alpha1.VLANPool {
+	p := emvlanv1alpha1.VLANPool{Spec: emvlanv1alpha1.VLANPoolSpec{Start: 100, End: 102, Metro: metro}}
+	p.SetName(poolName)
+	p.Status.Allocations = allocs
+	p.Status.Allocated = len(allocs)
+	return p
+}
+
+func TestAllocate(t *testing.T) {
+	other := func(id int) emvlanv1alpha1.VXLANAllocation {
+		return emvlanv1alpha1.VXLANAllocation{VXLAN: id, Name: "other", UID: "other"}
+	}
+	mine := func(id int) emvlanv1alpha1.VXLANAllocation {
+		return emvlanv1alpha1.VXLANAllocation{VXLAN: id, Name: vlanName, UID: vlanUID}
+	}
+
+	type want struct {
+		id          int
+		err         error
+		allocations []emvlanv1alpha1.VXLANAllocation
+	}
+
+	cases := map[string]struct {
+		pool      emvlanv1alpha1.VLANPool
+		orphaned  bool
+		getErr    error
+		listErr   error
+		updateErr error
+		want      want
+	}{
+		"LowestFreeID": {
+			pool: pool("", other(100), other(102)),
+			want: want{id: 101, allocations: []emvlanv1alpha1.VXLANAllocation{other(100), mine(101), other(102)}},
+		},
+		"AlreadyAllocated": {
+			pool: pool("DA", other(100), mine(102)),
+			want: want{id: 102},
+		},
+		"Exhausted": {
+			pool: pool("", other(100), other(101), other(102)),
+			want: want{err: errors.Errorf(errPoolExhaustedFmt, poolName, 100, 102)},
+		},
+		"ReclaimOrphaned": {
+			pool:     pool("", other(100), other(101), other(102)),
+			orphaned: true,
+			want:     want{id: 100, allocations: []emvlanv1alpha1.VXLANAllocation{mine(100)}},
+		},
+		"ListFailed": {
+			pool:    pool("", other(100)),
+			listErr: errBoom,
+			want:    want{err: errors.Wrap(errBoom, errListVLANs)},
+		},
+		"OtherMetro": {
+			pool: pool("sv"),
+			want: want{err: errors.Errorf(errPoolMetroFmt, poolName, "sv", "da")},
+		},
+		"GetFailed": {
+			getErr: errBoom,
+			want:   want{err: errors.Wrap(errBoom, errGetPool)},
+		},
+		"UpdateConflict": {
+			pool:      pool(""),
+			updateErr: errBoom,
+			want:      want{id: 100, err: errors.Wrap(errBoom, errUpdatePool), allocations: []emvlanv1alpha1.VXLANAllocation{mine(100)}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated []emvlanv1alpha1.VXLANAllocation
+			kube := &test.MockClient{
+				MockGet: test.NewMockGetFn(tc.getErr, func(obj client.Object) error {
+					tc.pool.DeepCopyInto(obj.(*emvlanv1alpha1.VLANPool))
+					return nil
+				}),
+				MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					updated = obj.(*emvlanv1alpha1.VLANPool).Status.Allocations
+					return tc.updateErr
+				},
+			}
+			reader := &test.MockClient{
+				MockList: test.NewMockListFn(tc.listErr, func(obj client.ObjectList) error {
+					if l, ok := obj.(*v1alpha1.VirtualNetworkList); ok && !tc.orphaned {
+						o := v1alpha1.VirtualNetwork{}
+						o.SetUID("other")
+						l.Items = []v1alpha1.VirtualNetwork{o}
+					}
+					return nil
+				}),
+			}
+
+			id, err := allocate(context.Background(), kube, reader, pooledVirtualNetwork())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("allocate(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.id, id); diff != "" {
+				t.Errorf("allocate(...): -want id, +got id:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.allocations, updated); diff != "" {
+				t.Errorf("allocate(...): -want allocations, +got allocations:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	kept := emvlanv1alpha1.VXLANAllocation{VXLAN: 100, Name: "other", UID: "other"}
+	notFound := kerrors.NewNotFound(schema.GroupResource{Resource: "vlanpools"}, poolName)
+
+	type want struct {
+		err         error
+		updated     bool
+		allocations []emvlanv1alpha1.VXLANAllocation
+	}
+
+	cases := map[string]struct {
+		pool   emvlanv1alpha1.VLANPool
+		getErr error
+		want   want
+	}{
+		"Allocated": {
+			pool: pool("", kept, emvlanv1alpha1.VXLANAllocation{VXLAN: 101, Name: vlanName, UID: vlanUID}),
+			want: want{updated: true, allocations: []emvlanv1alpha1.VXLANAllocation{kept}},
+		},
+		"NotAllocated": {
+			pool: pool("", kept),
+		},
+		"PoolNotFound": {
+			getErr: notFound,
+		},
+		"GetFailed": {
+			getErr: errBoom,
+			want:   want{err: errors.Wrap(errBoom, errGetPool)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			var allocations []emvlanv1alpha1.VXLANAllocation
+			kube := &test.MockClient{
+				MockGet: test.NewMockGetFn(tc.getErr, func(obj client.Object) error {
+					tc.pool.DeepCopyInto(obj.(*emvlanv1alpha1.VLANPool))
+					return nil
+				}),
+				MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					allocations = obj.(*emvlanv1alpha1.VLANPool).Status.Allocations
+					return nil
+				},
+			}
+
+			err := release(context.Background(), kube, pooledVirtualNetwork())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("release(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("release(...): -want updated, +got updated:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.allocations, allocations); diff != "" {
+				t.Errorf("release(...): -want allocations, +got allocations:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features defines the feature gates of the Equinix Metal provider.
+// Alpha features are disabled by default and enabled per installation with
+// the --enable-alpha-features flag.
+package features
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const errUnknownFeatureFmt = "unknown feature %q"
+
+// A Flag enables a feature.
+type Flag string
+
+// Alpha feature flags.
+const (
+	// EnableAlphaManagementPolicies enables the
+	// metal.equinix.com/management-policies annotation.
+	EnableAlphaManagementPolicies Flag = "ManagementPolicies"
+)
+
+// Alpha returns all alpha feature flags.
+func Alpha() []Flag {
+	return []Flag{
+		EnableAlphaManagementPolicies,
+	}
+}
+
+// Flags is a set of enabled features. The zero value has all features
+// disabled and is safe to use, as is a nil *Flags.
+type Flags struct {
+	m       sync.RWMutex
+	enabled map[Flag]bool
+}
+
+// Enable the supplied feature.
+func (f *Flags) Enable(flag Flag) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.enabled == nil {
+		f.enabled = map[Flag]bool{}
+	}
+	f.enabled[flag] = true
+}
+
+// Enabled returns true if the supplied feature is enabled.
+func (f *Flags) Enabled(flag Flag) bool {
+	if f == nil {
+		return false
+	}
+	f.m.RLock()
+	defer f.m.RUnlock()
+	return f.enabled[flag]
+}
+
+// Parse returns Flags with the named features enabled. It returns an error
+// if a name is not a known feature.
+func Parse(names ...string) (*Flags, error) {
+	known := map[Flag]bool{}
+	for _, flag := range Alpha() {
+		known[flag] = true
+	}
+	f := &Flags{}
+	for _, n := range names {
+		if !known[Flag(n)] {
+			return nil, errors.Errorf(errUnknownFeatureFmt, n)
+		}
+		f.Enable(Flag(n))
+	}
+	return f, nil
+}
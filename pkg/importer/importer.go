@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer generates managed resources for existing Equinix Metal
+// resources, so that they can be brought under management by the provider.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	devicesclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/device"
+	vlanclient "github.com/packethost/crossplane-provider-equinix-metal/pkg/clients/vlan"
+)
+
+const (
+	errListDevices         = "cannot list Devices"
+	errListVirtualNetworks = "cannot list VirtualNetworks"
+	errMarshal             = "cannot marshal managed resource"
+)
+
+// maxNameLength is the longest name generated for a managed resource.
+const maxNameLength = 63
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Options configure the managed resources generated for existing Equinix
+// Metal resources.
+type Options struct {
+	// ProviderConfig is the name of the ProviderConfig the managed resources
+	// reference.
+	ProviderConfig string
+
+	// DeletionPolicy of the managed resources. Orphan leaves the imported
+	// resources in place when the managed resources are deleted.
+	DeletionPolicy xpv1.DeletionPolicy
+}
+
+// An Importer generates managed resources for the existing resources of an
+// Equinix Metal project.
+// Managed resources generated by one Importer have unique names.
+type Importer struct {
+	options Options
+	names   map[string]bool
+}
+
+// New returns an Importer generating managed resources with the supplied
+// options.
+func New(o Options) *Importer {
+	return &Importer{options: o, names: map[string]bool{}}
+}
+
+// Reserve prevents the managed resources generated by the Importer from
+// taking the supplied names, e.g. those of existing managed resources.
+func (i *Importer) Reserve(names ...string) {
+	for _, n := range names {
+		i.names[n] = true
+	}
+}
+
+// Project returns managed resources for the Devices and VirtualNetworks of
+// the supplied project, listed with the supplied client.
+func (i *Importer) Project(c *clients.Client, projectID string) ([]resource.Managed, error) {
+	devices, _, err := c.Client.Devices.List(projectID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errListDevices)
+	}
+	vlans, err := vlanclient.ListAll(c.Client.ProjectVirtualNetworks, projectID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errListVirtualNetworks)
+	}
+
+	mgs := make([]resource.Managed, 0, len(devices)+len(vlans))
+	for _, d := range devices {
+		mgs = append(mgs, i.Device(d))
+	}
+	for _, v := range vlans {
+		mgs = append(mgs, i.VirtualNetwork(v))
+	}
+	return mgs, nil
+}
+
+// Device returns a Device managed resource for the supplied device.
+func (i *Importer) Device(d packngo.Device) *v1alpha2.Device {
+	mg := &v1alpha2.Device{}
+	mg.SetGroupVersionKind(v1alpha2.DeviceGroupVersionKind)
+	i.setMeta(mg, d.Hostname, "device", d.ID)
+
+	p := &mg.Spec.ForProvider
+	p.Hostname = stringPtr(d.Hostname)
+	p.Description = d.Description
+	p.BillingCycle = stringPtr(d.BillingCycle)
+	p.Locked = &d.Locked
+	p.AlwaysPXE = &d.AlwaysPXE
+	p.IPXEScriptURL = stringPtr(d.IPXEScriptURL)
+	p.Tags = devicesclient.UserTags(d.Tags)
+	if d.Plan != nil {
+		p.Plan = d.Plan.Slug
+	}
+	if d.OS != nil {
+		p.OS = d.OS.Slug
+	}
+	if d.Metro != nil {
+		p.Metro = d.Metro.Code
+	}
+	if d.Facility != nil && p.Metro == "" {
+		p.Facility = d.Facility.Code
+	}
+	if d.NetworkPorts != nil {
+		nt := d.GetNetworkType()
+		p.NetworkType = &nt
+	}
+	return mg
+}
+
+// VirtualNetwork returns a VirtualNetwork managed resource for the supplied
+// virtual network.
+func (i *Importer) VirtualNetwork(v packngo.VirtualNetwork) *v1alpha1.VirtualNetwork {
+	mg := &v1alpha1.VirtualNetwork{}
+	mg.SetGroupVersionKind(v1alpha1.VirtualNetworkGroupVersionKind)
+	location := v.MetroCode
+	if location == "" {
+		location = v.FacilityCode
+	}
+	i.setMeta(mg, fmt.Sprintf("vlan-%s-%d", location, v.VXLAN), "vlan", v.ID)
+
+	p := &mg.Spec.ForProvider
+	p.VXLAN = v.VXLAN
+	p.Description = stringPtr(v.Description)
+	if v.MetroCode != "" {
+		p.Metro = v.MetroCode
+	} else {
+		p.Facility = v.FacilityCode
+	}
+	return mg
+}
+
+// setMeta names the managed resource after the supplied name, or the prefix
+// and ID if the name is not valid or already taken, and sets its external name
+// and the configured options.
+func (i *Importer) setMeta(mg resource.Managed, name, prefix, id string) {
+	n := Name(name)
+	if n == "" || i.names[n] {
+		n = Name(prefix + "-" + strings.SplitN(id, "-", 2)[0])
+	}
+	if i.names[n] {
+		n = Name(prefix + "-" + id)
+	}
+	i.names[n] = true
+
+	mg.SetName(n)
+	meta.SetExternalName(mg, id)
+	if i.options.ProviderConfig != "" {
+		mg.SetProviderConfigReference(&xpv1.Reference{Name: i.options.ProviderConfig})
+	}
+	if i.options.DeletionPolicy != "" {
+		mg.SetDeletionPolicy(i.options.DeletionPolicy)
+	}
+}
+
+// Name returns the supplied name converted to a valid Kubernetes object name,
+// or an empty string if nothing of it remains.
+func Name(name string) string {
+	n := invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(n) > maxNameLength {
+		n = n[:maxNameLength]
+	}
+	return strings.Trim(n, "-")
+}
+
+// WriteYAML writes the supplied managed resources to w as a stream of YAML
+// documents, omitting their status and any empty metadata.
+func WriteYAML(w io.Writer, mgs []resource.Managed) error {
+	for _, mg := range mgs {
+		j, err := json.Marshal(mg)
+		if err != nil {
+			return errors.Wrap(err, errMarshal)
+		}
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(j, &m); err != nil {
+			return errors.Wrap(err, errMarshal)
+		}
+		delete(m, "status")
+		if md, ok := m["metadata"].(map[string]interface{}); ok {
+			delete(md, "creationTimestamp")
+		}
+		y, err := yaml.Marshal(m)
+		if err != nil {
+			return errors.Wrap(err, errMarshal)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+	packettest "github.com/packethost/crossplane-provider-equinix-metal/pkg/test"
+)
+
+func TestProject(t *testing.T) {
+	srv := packettest.NewMetalServer()
+	defer srv.Close()
+	defer srv.Install()()
+
+	srv.AddDevice(packngo.Device{
+		ID:           "6f1d9c3e-0000-4000-8000-000000000001",
+		Hostname:     "Web_01",
+		BillingCycle: "hourly",
+		Plan:         &packngo.Plan{Slug: "c3.small.x86"},
+		OS:           &packngo.OS{Slug: "ubuntu_20_04"},
+		Metro:        &packngo.Metro{Code: "sv"},
+		Facility:     &packngo.Facility{Code: "sv15"},
+	})
+
+	c, err := clients.NewClient(context.Background(), srv.Credentials())
+	if err != nil {
+		t.Fatalf("NewClient(...): %s", err)
+	}
+	mgs, err := New(Options{ProviderConfig: "example", DeletionPolicy: xpv1.DeletionOrphan}).Project(c, packettest.MetalProjectID)
+	if err != nil {
+		t.Fatalf("Project(...): %s", err)
+	}
+
+	b := &bytes.Buffer{}
+	if err := WriteYAML(b, mgs); err != nil {
+		t.Fatalf("WriteYAML(...): %s", err)
+	}
+	want := strings.TrimLeft(`
+---
+apiVersion: server.metal.equinix.com/v1alpha2
+kind: Device
+metadata:
+  annotations:
+    crossplane.io/external-name: 6f1d9c3e-0000-4000-8000-000000000001
+  name: web-01
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    alwaysPXE: false
+    billingCycle: hourly
+    hostname: Web_01
+    locked: false
+    metro: sv
+    operatingSystem: ubuntu_20_04
+    plan: c3.small.x86
+  providerConfigRef:
+    name: example
+`, "\n")
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteYAML(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestName(t *testing.T) {
+	cases := map[string]string{
+		"web-01":                "web-01",
+		"Web_01.example":        "web-01-example",
+		"---":                   "",
+		strings.Repeat("a", 70): strings.Repeat("a", maxNameLength),
+	}
+	for in, want := range cases {
+		if got := Name(in); got != want {
+			t.Errorf("Name(%q): want %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestTerraformState(t *testing.T) {
+	f, err := os.Open("testdata/terraform.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	mgs, skipped, err := New(Options{}).TerraformState(f)
+	if err != nil {
+		t.Fatalf("TerraformState(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{"module.network.equinix_metal_reserved_ip_block.public"}, skipped); diff != "" {
+		t.Errorf("TerraformState(...): -want skipped, +got:\n%s", diff)
+	}
+
+	b := &bytes.Buffer{}
+	if err := WriteYAML(b, mgs); err != nil {
+		t.Fatalf("WriteYAML(...): %s", err)
+	}
+	want := strings.TrimLeft(`
+---
+apiVersion: server.metal.equinix.com/v1alpha2
+kind: Device
+metadata:
+  annotations:
+    crossplane.io/external-name: 6f1d9c3e-0000-4000-8000-000000000001
+  name: web-0
+spec:
+  forProvider:
+    alwaysPXE: false
+    billingCycle: hourly
+    hostname: web-0
+    locked: false
+    metro: sv
+    networkType: layer3
+    operatingSystem: ubuntu_20_04
+    plan: c3.small.x86
+    tags:
+    - web
+    userdata: |
+      #cloud-config
+---
+apiVersion: vlan.metal.equinix.com/v1alpha1
+kind: VirtualNetwork
+metadata:
+  annotations:
+    crossplane.io/external-name: 6f1d9c3e-0000-4000-8000-000000000002
+  name: vlan-sv-1000
+spec:
+  forProvider:
+    description: private
+    metro: sv
+    vxlan: 1000
+`, "\n")
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("WriteYAML(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errDecodeState        = "cannot decode Terraform state"
+	errDecodeAttributes   = "cannot decode attributes of Terraform resource"
+	errStateVersionFmt    = "unsupported Terraform state version %d, want 4"
+	terraformStateVersion = 4
+	terraformModeManaged  = "managed"
+)
+
+// Resource types of the Terraform providers for Equinix Metal, by the resource
+// they manage. The metal_ and packet_ types are those of the deprecated
+// providers it replaced.
+var (
+	terraformDeviceTypes = map[string]bool{"equinix_metal_device": true, "metal_device": true, "packet_device": true}
+	terraformVLANTypes   = map[string]bool{"equinix_metal_vlan": true, "metal_vlan": true, "packet_vlan": true}
+)
+
+// terraformState is the subset of a version 4 Terraform state read by the
+// Importer.
+type terraformState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module,omitempty"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   interface{}     `json:"index_key,omitempty"`
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+type terraformDevice struct {
+	ID                    string   `json:"id"`
+	Hostname              string   `json:"hostname"`
+	Description           *string  `json:"description"`
+	Plan                  string   `json:"plan"`
+	Metro                 string   `json:"metro"`
+	Facilities            []string `json:"facilities"`
+	OS                    string   `json:"operating_system"`
+	BillingCycle          string   `json:"billing_cycle"`
+	Locked                bool     `json:"locked"`
+	AlwaysPXE             bool     `json:"always_pxe"`
+	IPXEScriptURL         string   `json:"ipxe_script_url"`
+	Tags                  []string `json:"tags"`
+	UserData              string   `json:"user_data"`
+	NetworkType           string   `json:"network_type"`
+	HardwareReservationID string   `json:"hardware_reservation_id"`
+}
+
+type terraformVLAN struct {
+	ID          string `json:"id"`
+	VXLAN       int    `json:"vxlan"`
+	Metro       string `json:"metro"`
+	Facility    string `json:"facility"`
+	Description string `json:"description"`
+}
+
+// TerraformState returns managed resources for the Equinix Metal Devices and
+// VLANs managed by the supplied version 4 Terraform state. It also returns the
+// addresses of the Equinix Metal resources in the state that have no managed
+// resource kind and were skipped.
+func (i *Importer) TerraformState(r io.Reader) ([]resource.Managed, []string, error) {
+	s := &terraformState{}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, nil, errors.Wrap(err, errDecodeState)
+	}
+	if s.Version != terraformStateVersion {
+		return nil, nil, errors.Errorf(errStateVersionFmt, s.Version)
+	}
+
+	mgs := []resource.Managed{}
+	skipped := []string{}
+	for _, res := range s.Resources {
+		if res.Mode != terraformModeManaged {
+			continue
+		}
+		for _, in := range res.Instances {
+			address := terraformAddress(res.Module, res.Type, res.Name, in.IndexKey)
+			switch {
+			case terraformDeviceTypes[res.Type]:
+				d := terraformDevice{}
+				if err := json.Unmarshal(in.Attributes, &d); err != nil {
+					return nil, nil, errors.Wrapf(err, "%s %s", errDecodeAttributes, address)
+				}
+				mgs = append(mgs, i.terraformDevice(d))
+			case terraformVLANTypes[res.Type]:
+				v := terraformVLAN{}
+				if err := json.Unmarshal(in.Attributes, &v); err != nil {
+					return nil, nil, errors.Wrapf(err, "%s %s", errDecodeAttributes, address)
+				}
+				mgs = append(mgs, i.VirtualNetwork(packngo.VirtualNetwork{
+					ID:           v.ID,
+					VXLAN:        v.VXLAN,
+					MetroCode:    v.Metro,
+					FacilityCode: v.Facility,
+					Description:  v.Description,
+				}))
+			case isMetalType(res.Type):
+				skipped = append(skipped, address)
+			}
+		}
+	}
+	return mgs, skipped, nil
+}
+
+func (i *Importer) terraformDevice(d terraformDevice) resource.Managed {
+	dev := packngo.Device{
+		ID:            d.ID,
+		Hostname:      d.Hostname,
+		Description:   d.Description,
+		BillingCycle:  d.BillingCycle,
+		Locked:        d.Locked,
+		AlwaysPXE:     d.AlwaysPXE,
+		IPXEScriptURL: d.IPXEScriptURL,
+		Tags:          d.Tags,
+		Plan:          &packngo.Plan{Slug: d.Plan},
+		OS:            &packngo.OS{Slug: d.OS},
+	}
+	if d.Metro != "" {
+		dev.Metro = &packngo.Metro{Code: d.Metro}
+	}
+	if len(d.Facilities) > 0 {
+		dev.Facility = &packngo.Facility{Code: d.Facilities[0]}
+	}
+	mg := i.Device(dev)
+	p := &mg.Spec.ForProvider
+	p.UserData = stringPtr(d.UserData)
+	p.NetworkType = stringPtr(d.NetworkType)
+	p.HardwareReservationID = stringPtr(d.HardwareReservationID)
+	return mg
+}
+
+// terraformAddress returns the address of a Terraform resource instance, e.g.
+// module.web.equinix_metal_device.server[0].
+func terraformAddress(module, typ, name string, key interface{}) string {
+	a := typ + "." + name
+	if module != "" {
+		a = module + "." + a
+	}
+	switch k := key.(type) {
+	case nil:
+	case string:
+		a += fmt.Sprintf("[%q]", k)
+	default:
+		a += fmt.Sprintf("[%v]", k)
+	}
+	return a
+}
+
+func isMetalType(typ string) bool {
+	for _, prefix := range []string{"equinix_metal_", "metal_", "packet_"} {
+		if strings.HasPrefix(typ, prefix) {
+			return true
+		}
+	}
+	return false
+}
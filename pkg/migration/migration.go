@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration converts the managed resources and ProviderConfigs of the
+// legacy packet.crossplane.io API groups, served by provider-packet, to their
+// metal.equinix.com equivalents, and the managed resources of those to the
+// equinixmetal.crossplane.io API groups that alias them.
+package migration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	emportsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/ports/v1alpha1"
+	emserverv1beta1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/server/v1beta1"
+	emvlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/equinixmetal/vlan/v1alpha1"
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+const (
+	errListLegacyFmt   = "cannot list legacy %s"
+	errGetFmt          = "cannot get %s %s"
+	errCreateFmt       = "cannot create %s %s"
+	errOrphanLegacyFmt = "cannot orphan legacy %s %s"
+	errDeleteLegacyFmt = "cannot delete legacy %s %s"
+)
+
+// A Kind maps a legacy kind to the kind it is migrated to.
+type Kind struct {
+	From schema.GroupVersionKind
+	To   schema.GroupVersionKind
+}
+
+// Kinds are the legacy kinds that are migrated, ProviderConfigs first so that
+// the migrated managed resources reference existing ProviderConfigs.
+var Kinds = []Kind{
+	{
+		From: schema.GroupVersionKind{Group: "packet.crossplane.io", Version: "v1beta1", Kind: "ProviderConfig"},
+		To:   v1beta1.ProviderConfigGroupVersionKind,
+	},
+	{
+		From: schema.GroupVersionKind{Group: "server.packet.crossplane.io", Version: "v1alpha2", Kind: "Device"},
+		To:   serverv1alpha2.DeviceGroupVersionKind,
+	},
+	{
+		From: schema.GroupVersionKind{Group: "vlan.packet.crossplane.io", Version: "v1alpha1", Kind: "VirtualNetwork"},
+		To:   vlanv1alpha1.VirtualNetworkGroupVersionKind,
+	},
+	{
+		From: schema.GroupVersionKind{Group: "ports.packet.crossplane.io", Version: "v1alpha1", Kind: "Assignment"},
+		To:   portsv1alpha1.AssignmentGroupVersionKind,
+	},
+}
+
+// AliasKinds migrate the managed resources of the metal.equinix.com API groups
+// to the equinixmetal.crossplane.io API groups that alias them. Both are
+// reconciled during the deprecation window of the metal.equinix.com API
+// groups. ProviderConfigs are not aliased.
+var AliasKinds = []Kind{
+	{From: serverv1alpha2.DeviceGroupVersionKind, To: emserverv1beta1.DeviceGroupVersionKind},
+	{From: vlanv1alpha1.VirtualNetworkGroupVersionKind, To: emvlanv1alpha1.VirtualNetworkGroupVersionKind},
+	{From: portsv1alpha1.AssignmentGroupVersionKind, To: emportsv1alpha1.AssignmentGroupVersionKind},
+}
+
+// Convert returns the supplied legacy object as an object of the supplied
+// kind. Its name, labels, annotations, including the external name, and spec,
+// including its ProviderConfig and connection secret references, are kept.
+func Convert(legacy *unstructured.Unstructured, to schema.GroupVersionKind) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetGroupVersionKind(to)
+	u.SetName(legacy.GetName())
+	u.SetLabels(legacy.GetLabels())
+	u.SetAnnotations(legacy.GetAnnotations())
+	if spec, ok := legacy.Object["spec"]; ok {
+		u.Object["spec"] = runtime.DeepCopyJSONValue(spec)
+	}
+	return u
+}
+
+// A Migrator migrates legacy resources.
+type Migrator struct {
+	kube client.Client
+	log  logging.Logger
+
+	// DeleteLegacy deletes the legacy resources once migrated. They are
+	// orphaned first, so that the external resources are not deleted.
+	DeleteLegacy bool
+
+	// DryRun logs the resources that would be migrated without migrating
+	// them.
+	DryRun bool
+}
+
+// NewMigrator returns a Migrator using the supplied client.
+func NewMigrator(kube client.Client, log logging.Logger) *Migrator {
+	return &Migrator{kube: kube, log: log}
+}
+
+// Migrate migrates all legacy resources of the supplied kinds. Legacy kinds
+// whose CRDs are not installed are skipped. Resources that were already
+// migrated are left alone, so Migrate may be run repeatedly.
+func (m *Migrator) Migrate(ctx context.Context, kinds []Kind) error {
+	for _, k := range kinds {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(k.From.GroupVersion().WithKind(k.From.Kind + "List"))
+		if err := m.kube.List(ctx, l); err != nil {
+			if meta.IsNoMatchError(err) {
+				m.log.Debug("Legacy kind is not installed", "kind", k.From.String())
+				continue
+			}
+			return errors.Wrapf(err, errListLegacyFmt, k.From.Kind)
+		}
+		for i := range l.Items {
+			if err := m.migrate(ctx, &l.Items[i], k.To); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) migrate(ctx context.Context, legacy *unstructured.Unstructured, to schema.GroupVersionKind) error {
+	log := m.log.WithValues("kind", to.Kind, "name", legacy.GetName())
+	u := Convert(legacy, to)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(to)
+	err := m.kube.Get(ctx, client.ObjectKey{Name: u.GetName()}, existing)
+	switch {
+	case m.DryRun:
+		log.Info("Would migrate legacy resource", "exists", err == nil)
+		return nil
+	case kerrors.IsNotFound(err):
+		if err := m.kube.Create(ctx, u); err != nil {
+			return errors.Wrapf(err, errCreateFmt, to.Kind, u.GetName())
+		}
+		log.Info("Migrated legacy resource")
+	case err != nil:
+		return errors.Wrapf(err, errGetFmt, to.Kind, u.GetName())
+	default:
+		log.Debug("Legacy resource was already migrated")
+	}
+
+	if !m.DeleteLegacy {
+		return nil
+	}
+	return m.deleteLegacy(ctx, legacy)
+}
+
+// deleteLegacy deletes the supplied legacy resource without deleting the
+// external resource it manages, which is now managed by its migrated
+// equivalent.
+func (m *Migrator) deleteLegacy(ctx context.Context, legacy *unstructured.Unstructured) error {
+	kind := legacy.GetKind()
+	if _, ok := legacy.Object["spec"].(map[string]interface{}); ok && kind != "ProviderConfig" {
+		if err := unstructured.SetNestedField(legacy.Object, string(xpv1.DeletionOrphan), "spec", "deletionPolicy"); err != nil {
+			return errors.Wrapf(err, errOrphanLegacyFmt, kind, legacy.GetName())
+		}
+	}
+	// The legacy provider may no longer run to remove its finalizers.
+	legacy.SetFinalizers(nil)
+	if err := m.kube.Update(ctx, legacy); err != nil {
+		return errors.Wrapf(err, errOrphanLegacyFmt, kind, legacy.GetName())
+	}
+	if err := m.kube.Delete(ctx, legacy); client.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, errDeleteLegacyFmt, kind, legacy.GetName())
+	}
+	m.log.Info("Deleted migrated legacy resource", "kind", kind, "name", legacy.GetName())
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/apis"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+)
+
+func legacyDevice() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"forProvider": map[string]interface{}{
+				"hostname":        "web",
+				"plan":            "c3.small.x86",
+				"metro":           "sv",
+				"operatingSystem": "ubuntu_20_04",
+			},
+			"providerConfigRef": map[string]interface{}{"name": "default"},
+			"writeConnectionSecretToRef": map[string]interface{}{
+				"name":      "web",
+				"namespace": "crossplane-system",
+			},
+		},
+		"status": map[string]interface{}{"atProvider": map[string]interface{}{"id": "legacy-id"}},
+	}}
+	u.SetGroupVersionKind(Kinds[1].From)
+	u.SetName("web")
+	u.SetFinalizers([]string{"finalizer.managedresource.crossplane.io"})
+	meta.SetExternalName(u, "6f1d9c3e-0000-4000-8000-000000000001")
+	return u
+}
+
+func TestMigrate(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range Kinds {
+		s.AddKnownTypeWithName(k.From, &unstructured.Unstructured{})
+		s.AddKnownTypeWithName(k.From.GroupVersion().WithKind(k.From.Kind+"List"), &unstructured.UnstructuredList{})
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(legacyDevice()).Build()
+
+	m := NewMigrator(kube, logging.NewNopLogger())
+	m.DeleteLegacy = true
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := m.Migrate(ctx, Kinds); err != nil {
+			t.Fatalf("Migrate(...): %s", err)
+		}
+	}
+
+	d := &v1alpha2.Device{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: "web"}, d); err != nil {
+		t.Fatalf("Get(...): migrated Device: %s", err)
+	}
+	if diff := cmp.Diff("6f1d9c3e-0000-4000-8000-000000000001", meta.GetExternalName(d)); diff != "" {
+		t.Errorf("Migrate(...): -want external name, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("default", d.GetProviderConfigReference().Name); diff != "" {
+		t.Errorf("Migrate(...): -want ProviderConfig, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("web", d.GetWriteConnectionSecretToReference().Name); diff != "" {
+		t.Errorf("Migrate(...): -want connection secret, +got:\n%s", diff)
+	}
+	if d.Status.AtProvider.ID != "" {
+		t.Errorf("Migrate(...): want status not to be migrated, got %+v", d.Status.AtProvider)
+	}
+
+	legacy := &unstructured.Unstructured{}
+	legacy.SetGroupVersionKind(Kinds[1].From)
+	if err := kube.Get(ctx, client.ObjectKey{Name: "web"}, legacy); !kerrors.IsNotFound(err) {
+		t.Errorf("Get(...): want legacy Device to be deleted, got error %v", err)
+	}
+}
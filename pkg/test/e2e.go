@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Environment variables configuring end to end tests.
+const (
+	// AuthTokenEnv is the API key end to end tests use.
+	AuthTokenEnv = "METAL_AUTH_TOKEN"
+
+	// ProjectIDEnv is the project end to end tests create resources in.
+	ProjectIDEnv = "METAL_PROJECT_ID"
+
+	// MetroEnv is the metro end to end tests create resources in.
+	MetroEnv = "METAL_TEST_METRO"
+
+	// PlanEnv is the plan of the Devices end to end tests create.
+	PlanEnv = "METAL_TEST_PLAN"
+)
+
+// Defaults of the end to end test environment.
+const (
+	DefaultE2EMetro = "sv"
+	DefaultE2EPlan  = "c3.small.x86"
+	DefaultE2EOS    = "ubuntu_20_04"
+)
+
+// E2E returns the Credentials of end to end tests, which create real, billed
+// Equinix Metal resources. It skips the test unless METAL_AUTH_TOKEN and
+// METAL_PROJECT_ID are set.
+func E2E(t *testing.T) *clients.Credentials {
+	t.Helper()
+	token, project := os.Getenv(AuthTokenEnv), os.Getenv(ProjectIDEnv)
+	if token == "" || project == "" {
+		t.Skipf("end to end tests require %s and %s", AuthTokenEnv, ProjectIDEnv)
+	}
+	return &clients.Credentials{APIKey: token, ProjectID: project}
+}
+
+// E2EMetro returns the metro end to end tests create resources in.
+func E2EMetro() string {
+	if m := os.Getenv(MetroEnv); m != "" {
+		return m
+	}
+	return DefaultE2EMetro
+}
+
+// E2EPlan returns the plan of the Devices end to end tests create.
+func E2EPlan() string {
+	if p := os.Getenv(PlanEnv); p != "" {
+		return p
+	}
+	return DefaultE2EPlan
+}
+
+// Eventually calls fn every interval until it returns true, failing the test
+// if it returns an error or does not return true within timeout.
+func Eventually(t *testing.T, timeout, interval time.Duration, fn func() (bool, error)) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := fn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within %s", timeout)
+		}
+		time.Sleep(interval)
+	}
+}
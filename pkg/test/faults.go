@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// A Fault is injected into a call of a faulty fake Equinix Metal API client.
+// The zero Fault injects nothing.
+type Fault struct {
+	// Latency delays the call.
+	Latency time.Duration
+
+	// Err fails the call. The call is not made unless Lost is true.
+	Err error
+
+	// Lost makes the call before failing it with Err, as if its response was
+	// lost on its way back.
+	Lost bool
+
+	// Partial drops the nested objects, and all but the first item of lists,
+	// from the response of the call.
+	Partial bool
+}
+
+// APIError returns a Fault failing a call with an Equinix Metal API error of
+// the supplied HTTP status code.
+func APIError(status int) Fault {
+	return Fault{Err: errorResponse(status, http.Header{})}
+}
+
+// LostResponse returns a Fault making a call but failing it with an Equinix
+// Metal API error of the supplied HTTP status code.
+func LostResponse(status int) Fault {
+	return Fault{Err: errorResponse(status, http.Header{}), Lost: true}
+}
+
+// RateLimited returns a Fault failing a call as rate limited, to be retried
+// after the supplied duration.
+func RateLimited(retryAfter time.Duration) Fault {
+	h := http.Header{}
+	h.Set(clients.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())))
+	return Fault{Err: errorResponse(http.StatusTooManyRequests, h)}
+}
+
+// Slow returns a Fault delaying a call by the supplied duration.
+func Slow(latency time.Duration) Fault {
+	return Fault{Latency: latency}
+}
+
+// Partial returns a Fault making a call but returning a partial response.
+func Partial() Fault {
+	return Fault{Partial: true}
+}
+
+func errorResponse(status int, h http.Header) error {
+	return &packngo.ErrorResponse{
+		Response:    &http.Response{StatusCode: status, Header: h, Request: &http.Request{Method: http.MethodGet}},
+		SingleError: http.StatusText(status),
+	}
+}
+
+// Faults are sequences of Faults injected into the successive calls of each
+// method of a faulty fake Equinix Metal API client. Calls past the end of the
+// sequence of their method are not faulted. Faults are safe for concurrent
+// use.
+type Faults struct {
+	mu    sync.Mutex
+	seqs  map[string][]Fault
+	calls map[string]int
+	sleep func(time.Duration)
+}
+
+// NewFaults returns Faults that inject nothing until faults are added.
+func NewFaults() *Faults {
+	return &Faults{seqs: map[string][]Fault{}, calls: map[string]int{}, sleep: time.Sleep}
+}
+
+// Add appends the supplied faults to the sequence of the named method, such as
+// "Get", and returns the Faults.
+func (f *Faults) Add(method string, faults ...Fault) *Faults {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seqs[method] = append(f.seqs[method], faults...)
+	return f
+}
+
+// Next returns the Fault of the next call of the named method, after waiting
+// out its latency.
+func (f *Faults) Next(method string) Fault {
+	f.mu.Lock()
+	f.calls[method]++
+	var ft Fault
+	if seq := f.seqs[method]; len(seq) > 0 {
+		ft, f.seqs[method] = seq[0], seq[1:]
+	}
+	f.mu.Unlock()
+
+	if ft.Latency > 0 {
+		f.sleep(ft.Latency)
+	}
+	return ft
+}
+
+// Calls returns how often the named method was called.
+func (f *Faults) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// Remaining returns how many faults are yet to be injected.
+func (f *Faults) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, seq := range f.seqs {
+		n += len(seq)
+	}
+	return n
+}
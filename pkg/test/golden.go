@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// GoldenEnv rewrites the golden files compared by Golden when set to
+// "update".
+const GoldenEnv = "METAL_TEST_GOLDEN"
+
+// Golden compares the indented JSON encoding of v to the named golden file in
+// the fixtures directory of the calling test, so that changes to payloads sent
+// to the Equinix Metal API show up in review. With METAL_TEST_GOLDEN=update the
+// golden file is written instead.
+func Golden(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("json.MarshalIndent(...): %s", err)
+	}
+	got = append(got, '\n')
+
+	file := filepath.Join("fixtures", name+".golden.json")
+	if os.Getenv(GoldenEnv) == "update" {
+		if err := ioutil.WriteFile(file, got, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(filepath.Clean(file))
+	if err != nil {
+		t.Fatalf("cannot read golden file, write it with %s=update: %s", GoldenEnv, err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("%s: -want, +got:\n%s", file, diff)
+	}
+}
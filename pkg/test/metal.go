@@ -0,0 +1,511 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/packethost/packngo"
+
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// MetalProjectID is the project of the Credentials returned by a MetalServer.
+const MetalProjectID = "11111111-1111-4111-8111-111111111111"
+
+const metalBasePath = "/metal/v1"
+
+// Device states a MetalServer moves newly created Devices through, one per
+// read of the Device.
+var deviceStates = []string{"queued", "provisioning", "active"}
+
+// A MetalServer is a fake Equinix Metal API serving Devices, IP reservations
+// and VirtualNetworks from memory. Devices are created queued and advance to
+// provisioning and then active each time they are read, so that controllers
+// can be run through their whole create, observe, update and delete cycle.
+type MetalServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	apiKey  string
+	nextID  int
+	devices map[string]*packngo.Device
+	events  map[string][]packngo.Event
+	ips     map[string]*packngo.IPAddressReservation
+	vlans   map[string]*packngo.VirtualNetwork
+	calls   map[string]int
+}
+
+// NewMetalServer starts a MetalServer. It must be closed when the test is
+// done.
+func NewMetalServer() *MetalServer {
+	s := &MetalServer{
+		devices: map[string]*packngo.Device{},
+		events:  map[string][]packngo.Event{},
+		ips:     map[string]*packngo.IPAddressReservation{},
+		vlans:   map[string]*packngo.VirtualNetwork{},
+		calls:   map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	// API clients are cached by credentials, so each server gets its own.
+	s.apiKey = "fake-" + s.Server.Listener.Addr().String()
+	return s
+}
+
+// Credentials returns Credentials accepted by the MetalServer.
+func (s *MetalServer) Credentials() *clients.Credentials {
+	return &clients.Credentials{APIKey: s.apiKey, ProjectID: MetalProjectID}
+}
+
+// Install sends the requests of the API clients created afterwards to the
+// MetalServer. The returned function restores the default transport.
+func (s *MetalServer) Install() func() {
+	target, _ := url.Parse(s.URL)
+	clients.SetBaseTransport(redirectTransport{target: target, next: s.Client().Transport})
+	return func() { clients.SetBaseTransport(http.DefaultTransport) }
+}
+
+// Calls returns how often the MetalServer was sent requests with the supplied
+// method and path, e.g. "GET /devices/{id}".
+func (s *MetalServer) Calls(method, p string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method+" "+p]
+}
+
+// Device returns the Device with the supplied ID, if any.
+func (s *MetalServer) Device(id string) (packngo.Device, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.devices[id]
+	if !ok {
+		return packngo.Device{}, false
+	}
+	return *d, true
+}
+
+// VirtualNetwork returns the VirtualNetwork with the supplied ID, if any.
+func (s *MetalServer) VirtualNetwork(id string) (packngo.VirtualNetwork, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.vlans[id]
+	if !ok {
+		return packngo.VirtualNetwork{}, false
+	}
+	return *v, true
+}
+
+// SetDeviceState sets the state of the Device with the supplied ID, for
+// example to fail its provisioning.
+func (s *MetalServer) SetDeviceState(id, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.devices[id]; ok {
+		d.State = state
+	}
+}
+
+// AddDevice adds a Device to the MetalServer as if it was created outside of
+// the provider, returning its ID.
+func (s *MetalServer) AddDevice(d packngo.Device) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d.ID == "" {
+		d.ID = s.newID()
+	}
+	if d.Href == "" {
+		d.Href = path.Join(metalBasePath, "devices", d.ID)
+	}
+	s.devices[d.ID] = &d
+	return d.ID
+}
+
+// redirectTransport sends requests for the Equinix Metal API to a MetalServer.
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+	return t.next.RoundTrip(r)
+}
+
+func (s *MetalServer) newID() string {
+	s.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+}
+
+func (s *MetalServer) serve(w http.ResponseWriter, r *http.Request) { //nolint:gocyclo
+	if r.Header.Get(clients.HeaderAuthToken) != s.apiKey {
+		writeError(w, http.StatusUnauthorized, "Invalid authentication token")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, metalBasePath), "/"), "/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Record calls with the IDs of the path replaced by {id}.
+	key := make([]string, len(parts))
+	for i, p := range parts {
+		key[i] = p
+		if i%2 == 1 {
+			key[i] = "{id}"
+		}
+	}
+	s.calls[r.Method+" /"+strings.Join(key, "/")]++
+
+	switch {
+	case len(parts) == 3 && parts[0] == "projects" && parts[1] != MetalProjectID:
+		writeError(w, http.StatusNotFound, "Not found")
+	case len(parts) == 3 && parts[0] == "projects" && parts[2] == "devices" && r.Method == http.MethodPost:
+		s.createDevice(w, r)
+	case len(parts) == 3 && parts[0] == "projects" && parts[2] == "devices" && r.Method == http.MethodGet:
+		devices := []packngo.Device{}
+		for _, d := range s.devices {
+			devices = append(devices, excludeNested(*d, r))
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"devices": devices})
+	case len(parts) == 2 && parts[0] == "devices":
+		s.device(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "devices" && parts[2] == "events" && r.Method == http.MethodGet:
+		s.deviceEvents(w, parts[1])
+	case len(parts) == 3 && parts[0] == "projects" && parts[2] == "ips":
+		s.projectIPs(w, r)
+	case len(parts) == 2 && parts[0] == "ips":
+		s.ip(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "projects" && parts[2] == "virtual-networks":
+		s.projectVirtualNetworks(w, r)
+	case len(parts) == 2 && parts[0] == "virtual-networks":
+		s.virtualNetwork(w, r, parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *MetalServer) createDevice(w http.ResponseWriter, r *http.Request) {
+	req := &packngo.DeviceCreateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if req.Plan == "" || req.OS == "" || (req.Metro == "" && len(nonEmpty(req.Facility)) == 0) {
+		writeError(w, http.StatusUnprocessableEntity, "plan, operating_system and metro or facility are required")
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	id := s.newID()
+	d := &packngo.Device{
+		ID:            id,
+		Href:          path.Join(metalBasePath, "devices", id),
+		Hostname:      req.Hostname,
+		State:         deviceStates[0],
+		BillingCycle:  req.BillingCycle,
+		Locked:        false,
+		Tags:          req.Tags,
+		UserData:      req.UserData,
+		AlwaysPXE:     req.AlwaysPXE,
+		IPXEScriptURL: req.IPXEScriptURL,
+		Created:       now,
+		Updated:       now,
+		Plan:          &packngo.Plan{Slug: req.Plan},
+		OS:            &packngo.OS{Slug: req.OS},
+		Project:       &packngo.Project{ID: MetalProjectID},
+		Network: []*packngo.IPAddressAssignment{{
+			IpAddressCommon: packngo.IpAddressCommon{
+				ID: s.newID(), Address: fmt.Sprintf("198.51.100.%d", s.nextID%254+1),
+				AddressFamily: 4, Public: true, Management: true, CIDR: 31,
+			},
+		}},
+		NetworkPorts: []packngo.Port{
+			{ID: s.newID(), Type: "NetworkBondPort", Name: "bond0", Data: packngo.PortData{Bonded: true}, NetworkType: packngo.NetworkTypeL3},
+			{ID: s.newID(), Type: "NetworkPort", Name: "eth0", Data: packngo.PortData{Bonded: true}, Bond: &packngo.BondData{Name: "bond0"}},
+			{ID: s.newID(), Type: "NetworkPort", Name: "eth1", Data: packngo.PortData{Bonded: true}, Bond: &packngo.BondData{Name: "bond0"}},
+		},
+	}
+	if req.Description != "" {
+		d.Description = &req.Description
+	}
+	if req.Metro != "" {
+		d.Metro = &packngo.Metro{Code: req.Metro}
+	}
+	if f := nonEmpty(req.Facility); len(f) > 0 {
+		d.Facility = &packngo.Facility{Code: f[0]}
+	}
+	s.devices[id] = d
+	s.addDeviceEvent(id, "instance.created", "Device created")
+	writeJSON(w, http.StatusCreated, d)
+}
+
+func (s *MetalServer) device(w http.ResponseWriter, r *http.Request, id string) {
+	d, ok := s.devices[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, excludeNested(*d, r))
+		for i := 0; i < len(deviceStates)-1; i++ {
+			if d.State == deviceStates[i] {
+				d.State = deviceStates[i+1]
+				s.addDeviceEvent(id, "instance."+d.State, "Device is "+d.State)
+				break
+			}
+		}
+	case http.MethodPut:
+		req := &packngo.DeviceUpdateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		applyDeviceUpdate(d, req)
+		d.Updated = time.Now().UTC().Format(time.RFC3339)
+		writeJSON(w, http.StatusOK, d)
+	case http.MethodDelete:
+		if d.Locked {
+			writeError(w, http.StatusUnprocessableEntity, "Cannot delete a locked device")
+			return
+		}
+		delete(s.devices, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// excludeNested returns the supplied device with the nested objects named by
+// the exclude query parameter of the supplied request reduced to their href,
+// as the API returns them. Only the plan is reduced.
+func excludeNested(d packngo.Device, r *http.Request) packngo.Device {
+	for _, e := range strings.Split(r.URL.Query().Get("exclude"), ",") {
+		if e == "plan" && d.Plan != nil {
+			d.Plan = &packngo.Plan{Href: path.Join(metalBasePath, "plans", d.Plan.ID)}
+		}
+	}
+	return d
+}
+
+func (s *MetalServer) deviceEvents(w http.ResponseWriter, id string) {
+	if _, ok := s.devices[id]; !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	// Events are listed newest first.
+	events := []packngo.Event{}
+	for i := len(s.events[id]) - 1; i >= 0; i-- {
+		events = append(events, s.events[id][i])
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"events": events})
+}
+
+func (s *MetalServer) addDeviceEvent(id, typ, body string) {
+	s.events[id] = append(s.events[id], packngo.Event{
+		ID:           s.newID(),
+		Type:         typ,
+		Interpolated: body,
+		CreatedAt:    &packngo.Timestamp{Time: time.Now().UTC()},
+	})
+}
+
+func applyDeviceUpdate(d *packngo.Device, req *packngo.DeviceUpdateRequest) {
+	if req.Hostname != nil {
+		d.Hostname = *req.Hostname
+	}
+	if req.Description != nil {
+		d.Description = req.Description
+	}
+	if req.UserData != nil {
+		d.UserData = *req.UserData
+	}
+	if req.Locked != nil {
+		d.Locked = *req.Locked
+	}
+	if req.Tags != nil {
+		d.Tags = *req.Tags
+	}
+	if req.AlwaysPXE != nil {
+		d.AlwaysPXE = *req.AlwaysPXE
+	}
+	if req.IPXEScriptURL != nil {
+		d.IPXEScriptURL = *req.IPXEScriptURL
+	}
+}
+
+func (s *MetalServer) projectIPs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		ips := []packngo.IPAddressReservation{}
+		for _, ip := range s.ips {
+			ips = append(ips, *ip)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ip_addresses": ips})
+	case http.MethodPost:
+		req := &packngo.IPReservationRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if req.Quantity <= 0 || req.Quantity&(req.Quantity-1) != 0 {
+			writeError(w, http.StatusUnprocessableEntity, "quantity must be a power of two")
+			return
+		}
+		cidr := 32
+		for q := req.Quantity; q > 1; q /= 2 {
+			cidr--
+		}
+		id := s.newID()
+		ip := &packngo.IPAddressReservation{
+			IpAddressCommon: packngo.IpAddressCommon{
+				ID:            id,
+				Href:          path.Join(metalBasePath, "ips", id),
+				Address:       fmt.Sprintf("203.0.113.%d", (s.nextID*8)%256),
+				Network:       fmt.Sprintf("203.0.113.%d", (s.nextID*8)%256),
+				AddressFamily: 4,
+				Public:        req.Type == "public_ipv4",
+				CIDR:          cidr,
+				Project:       packngo.Href{Href: path.Join(metalBasePath, "projects", MetalProjectID)},
+				Tags:          req.Tags,
+				Created:       time.Now().UTC().Format(time.RFC3339),
+			},
+		}
+		if req.Description != "" {
+			ip.Description = &req.Description
+		}
+		if req.Metro != nil {
+			ip.Metro = &packngo.Metro{Code: *req.Metro}
+		}
+		if req.Facility != nil {
+			ip.Facility = &packngo.Facility{Code: *req.Facility}
+		}
+		s.ips[id] = ip
+		writeJSON(w, http.StatusCreated, ip)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *MetalServer) ip(w http.ResponseWriter, r *http.Request, id string) {
+	ip, ok := s.ips[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, ip)
+	case http.MethodDelete:
+		delete(s.ips, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *MetalServer) projectVirtualNetworks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		vlans := []packngo.VirtualNetwork{}
+		for _, v := range s.vlans {
+			vlans = append(vlans, *v)
+		}
+		writeJSON(w, http.StatusOK, packngo.VirtualNetworkListResponse{VirtualNetworks: vlans})
+	case http.MethodPost:
+		req := &packngo.VirtualNetworkCreateRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if (req.Metro == "") == (req.Facility == "") {
+			writeError(w, http.StatusUnprocessableEntity, "exactly one of metro or facility is required")
+			return
+		}
+		vxlan := req.VXLAN
+		if vxlan == 0 {
+			vxlan = 1000 + len(s.vlans)
+		}
+		for _, v := range s.vlans {
+			if v.VXLAN == vxlan && v.MetroCode == req.Metro && v.FacilityCode == req.Facility {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Virtual network %d already exists", vxlan))
+				return
+			}
+		}
+		id := s.newID()
+		v := &packngo.VirtualNetwork{
+			ID:           id,
+			Href:         path.Join(metalBasePath, "virtual-networks", id),
+			Description:  req.Description,
+			VXLAN:        vxlan,
+			FacilityCode: req.Facility,
+			MetroCode:    req.Metro,
+			CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+		}
+		s.vlans[id] = v
+		writeJSON(w, http.StatusCreated, v)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *MetalServer) virtualNetwork(w http.ResponseWriter, r *http.Request, id string) {
+	v, ok := s.vlans[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, v)
+	case http.MethodDelete:
+		delete(s.vlans, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func nonEmpty(in []string) []string {
+	out := []string{}
+	for _, s := range in {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string][]string{"errors": {msg}})
+}
@@ -19,4 +19,12 @@ package version
 var (
 	// Version is defined at build using git tags
 	Version string = "development"
+
+	// Commit is defined at build using the git commit hash
+	Commit string = "unknown"
 )
+
+// UserAgent returns the User-Agent sent with every Equinix Metal API request.
+func UserAgent() string {
+	return "crossplane-provider-equinix-metal/" + Version + " (" + Commit + ")"
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/packethost/packngo"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// A CapacityPolicy determines how Devices are admitted whose plan has no
+// capacity in their metro or facility.
+type CapacityPolicy string
+
+// Capacity policies.
+const (
+	// CapacityPolicyOff admits Devices without checking capacity.
+	CapacityPolicyOff CapacityPolicy = "Off"
+
+	// CapacityPolicyWarn admits Devices without capacity with a warning.
+	CapacityPolicyWarn CapacityPolicy = "Warn"
+
+	// CapacityPolicyDeny rejects Devices without capacity.
+	CapacityPolicyDeny CapacityPolicy = "Deny"
+)
+
+// CapacityPolicies are the supported capacity policies.
+func CapacityPolicies() []string {
+	return []string{string(CapacityPolicyOff), string(CapacityPolicyWarn), string(CapacityPolicyDeny)}
+}
+
+const (
+	// facilityAny is the facility of Devices deployed in any facility with
+	// capacity for their plan.
+	facilityAny = "any"
+
+	errNoCapacityFmt    = "no capacity for plan %q in %s %q: the Device would not be created until there is"
+	errCheckCapacityFmt = "cannot check capacity for plan %q in %s %q: %s"
+)
+
+// A capacityClient checks the capacity of plans in metros and facilities.
+type capacityClient interface {
+	Check(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+	CheckMetros(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+}
+
+// A capacityValidator checks at admission that there is capacity for the plan
+// of a new Device in its metro or facility, so that Devices whose creation is
+// bound to fail are reported when they are applied rather than after minutes
+// of retries. Capacity that cannot be checked admits the Device with a
+// warning, so that the webhook does not depend on the Equinix Metal API being
+// available.
+type capacityValidator struct {
+	kube          client.Client
+	policy        CapacityPolicy
+	credentialsFn func(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig) (*clients.Credentials, error)
+	newClientFn   func(ctx context.Context, config *clients.Credentials) (capacityClient, error)
+}
+
+func (v *capacityValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create || len(req.Object.Raw) == 0 {
+		return admission.Allowed("")
+	}
+	d := &serverv1alpha2.Device{}
+	if err := json.Unmarshal(req.Object.Raw, d); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// A Device that adopts an existing device, e.g. one that was imported,
+	// never creates one.
+	if adopts(d) {
+		return admission.Allowed("")
+	}
+	p := d.Spec.ForProvider
+	server, kind, location, ok := capacityServer(p)
+	if !ok {
+		return admission.Allowed("")
+	}
+	warn := func(err error) admission.Response {
+		return admission.Allowed("").WithWarnings(fmt.Sprintf(errCheckCapacityFmt, p.Plan, kind, location, err))
+	}
+
+	name := defaultProviderConfig
+	if ref := d.GetProviderConfigReference(); ref != nil {
+		name = ref.Name
+	}
+	pc := &v1beta1.ProviderConfig{}
+	if err := v.kube.Get(ctx, types.NamespacedName{Name: name}, pc); err != nil {
+		return warn(err)
+	}
+	creds, err := v.credentialsFn(ctx, v.kube, pc)
+	if err != nil {
+		return warn(err)
+	}
+	c, err := v.newClientFn(ctx, creds)
+	if err != nil {
+		return warn(err)
+	}
+	check := c.Check
+	if server.Metro != "" {
+		check = c.CheckMetros
+	}
+	out, _, err := check(&packngo.CapacityInput{Servers: []packngo.ServerInfo{server}})
+	if err != nil {
+		return warn(err)
+	}
+	if available(out) {
+		return admission.Allowed("")
+	}
+
+	msg := fmt.Sprintf(errNoCapacityFmt, p.Plan, kind, location)
+	if v.policy == CapacityPolicyDeny {
+		return admission.Denied(msg)
+	}
+	return admission.Allowed("").WithWarnings(msg)
+}
+
+// capacityServer returns the capacity check of the supplied parameters, the
+// kind of their location and the location, or false if they cannot be
+// checked. Cloned Devices may take their plan and location from the device
+// they clone, Devices in any facility go wherever there is capacity, and
+// Devices on a hardware reservation use the capacity it reserved.
+func capacityServer(p serverv1alpha2.DeviceParameters) (packngo.ServerInfo, string, string, bool) {
+	if p.Clones() || p.HardwareReservationID != nil || p.Plan == "" {
+		return packngo.ServerInfo{}, "", "", false
+	}
+	switch {
+	case p.Metro != "":
+		return packngo.ServerInfo{Metro: p.Metro, Plan: p.Plan, Quantity: 1}, "metro", p.Metro, true
+	case p.Facility != "" && p.Facility != facilityAny:
+		return packngo.ServerInfo{Facility: p.Facility, Plan: p.Plan, Quantity: 1}, "facility", p.Facility, true
+	}
+	return packngo.ServerInfo{}, "", "", false
+}
+
+// available returns false if the supplied capacity check reports that any of
+// its servers is unavailable.
+func available(in *packngo.CapacityInput) bool {
+	if in == nil {
+		return true
+	}
+	for _, s := range in.Servers {
+		if !s.Available {
+			return false
+		}
+	}
+	return true
+}
+
+// newCapacityClient returns the capacity service of an Equinix Metal client
+// configured with the supplied credentials.
+func newCapacityClient(ctx context.Context, config *clients.Credentials) (capacityClient, error) {
+	c, err := clients.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.CapacityService, nil
+}
+
+// capacityPath returns the path the capacity webhook of the supplied kind is
+// served at.
+func capacityPath(gvk schema.GroupVersionKind) string {
+	return "/capacity-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+}
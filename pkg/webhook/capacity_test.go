@@ -0,0 +1,201 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/packethost/packngo"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+type fakeCapacity struct {
+	check func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+}
+
+func (f fakeCapacity) Check(in *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+	return f.check(in)
+}
+
+func (f fakeCapacity) CheckMetros(in *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+	return f.check(in)
+}
+
+func capacity(available bool) func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+	return func(in *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+		out := &packngo.CapacityInput{}
+		for _, s := range in.Servers {
+			s.Available = available
+			out.Servers = append(out.Servers, s)
+		}
+		return out, nil, nil
+	}
+}
+
+func TestCapacityValidator(t *testing.T) {
+	errBoom := errors.New("boom")
+	metro := serverv1alpha2.DeviceParameters{Plan: "c3.small.x86", Metro: "da"}
+
+	type want struct {
+		allowed  bool
+		warnings []string
+		checked  *packngo.ServerInfo
+	}
+	cases := map[string]struct {
+		operation    admissionv1.Operation
+		externalName string
+		params       serverv1alpha2.DeviceParameters
+		policy       CapacityPolicy
+		check        func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error)
+		want         want
+	}{
+		"Available": {
+			operation: admissionv1.Create,
+			params:    metro,
+			policy:    CapacityPolicyDeny,
+			check:     capacity(true),
+			want:      want{allowed: true, checked: &packngo.ServerInfo{Metro: "da", Plan: "c3.small.x86", Quantity: 1}},
+		},
+		"UnavailableDenied": {
+			operation: admissionv1.Create,
+			params:    metro,
+			policy:    CapacityPolicyDeny,
+			check:     capacity(false),
+			want:      want{allowed: false, checked: &packngo.ServerInfo{Metro: "da", Plan: "c3.small.x86", Quantity: 1}},
+		},
+		"UnavailableWarned": {
+			operation: admissionv1.Create,
+			params:    serverv1alpha2.DeviceParameters{Plan: "c3.small.x86", Facility: "da11"},
+			policy:    CapacityPolicyWarn,
+			check:     capacity(false),
+			want: want{
+				allowed:  true,
+				warnings: []string{`no capacity for plan "c3.small.x86" in facility "da11": the Device would not be created until there is`},
+				checked:  &packngo.ServerInfo{Facility: "da11", Plan: "c3.small.x86", Quantity: 1},
+			},
+		},
+		"CheckFailed": {
+			operation: admissionv1.Create,
+			params:    metro,
+			policy:    CapacityPolicyDeny,
+			check: func(*packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+				return nil, nil, errBoom
+			},
+			want: want{
+				allowed:  true,
+				warnings: []string{`cannot check capacity for plan "c3.small.x86" in metro "da": boom`},
+				checked:  &packngo.ServerInfo{Metro: "da", Plan: "c3.small.x86", Quantity: 1},
+			},
+		},
+		"Update": {
+			operation: admissionv1.Update,
+			params:    metro,
+			policy:    CapacityPolicyDeny,
+			check:     capacity(false),
+			want:      want{allowed: true},
+		},
+		"Adopted": {
+			operation:    admissionv1.Create,
+			externalName: "9d1c4c4e-0000-4000-8000-000000000000",
+			params:       metro,
+			policy:       CapacityPolicyDeny,
+			check:        capacity(false),
+			want:         want{allowed: true},
+		},
+		"ExternalNameIsName": {
+			operation:    admissionv1.Create,
+			externalName: "example",
+			params:       metro,
+			policy:       CapacityPolicyDeny,
+			check:        capacity(false),
+			want:         want{allowed: false, checked: &packngo.ServerInfo{Metro: "da", Plan: "c3.small.x86", Quantity: 1}},
+		},
+		"AnyFacility": {
+			operation: admissionv1.Create,
+			params:    serverv1alpha2.DeviceParameters{Plan: "c3.small.x86", Facility: facilityAny},
+			policy:    CapacityPolicyDeny,
+			check:     capacity(false),
+			want:      want{allowed: true},
+		},
+		"Clone": {
+			operation: admissionv1.Create,
+			params:    serverv1alpha2.DeviceParameters{Metro: "da", CloneFrom: func() *string { s := "1234"; return &s }()},
+			policy:    CapacityPolicyDeny,
+			check:     capacity(false),
+			want:      want{allowed: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &serverv1alpha2.Device{}
+			d.SetName("example")
+			if tc.externalName != "" {
+				meta.SetExternalName(d, tc.externalName)
+			}
+			d.Spec.ForProvider = tc.params
+			raw, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var checked *packngo.ServerInfo
+			v := &capacityValidator{
+				kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				policy: tc.policy,
+				credentialsFn: func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig) (*clients.Credentials, error) {
+					return &clients.Credentials{}, nil
+				},
+				newClientFn: func(_ context.Context, _ *clients.Credentials) (capacityClient, error) {
+					return fakeCapacity{check: func(in *packngo.CapacityInput) (*packngo.CapacityInput, *packngo.Response, error) {
+						checked = &in.Servers[0]
+						return tc.check(in)
+					}}, nil
+				},
+			}
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			}}
+			got := v.Handle(context.Background(), req)
+
+			if diff := cmp.Diff(tc.want.allowed, got.Allowed); diff != "" {
+				t.Errorf("Handle(...): -want allowed, +got allowed:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.warnings, got.Warnings); diff != "" {
+				t.Errorf("Handle(...): -want warnings, +got warnings:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.checked, checked); diff != "" {
+				t.Errorf("Handle(...): -want checked, +got checked:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+)
+
+const (
+	errReadCertificate = "cannot read webhook certificate"
+)
+
+// A CertWatcher serves the certificate and key of the webhook server, and
+// polls their files for rotation. Changes to the files are not notified for
+// every kind of volume certificates are mounted from, for example when the
+// mounted Secret is replaced, so polling catches every rotation. A rotated
+// certificate is reloaded in place, and served to new connections.
+type CertWatcher struct {
+	certPath string
+	keyPath  string
+	interval time.Duration
+	log      logging.Logger
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	last []byte
+}
+
+// NewCertWatcher returns a CertWatcher serving the named certificate and key
+// files of the supplied directory, and polling them every interval. A zero
+// interval serves the certificate and key read at creation until exit.
+func NewCertWatcher(dir, certName, keyName string, interval time.Duration, l logging.Logger) (*CertWatcher, error) {
+	w := &CertWatcher{
+		certPath: filepath.Join(dir, certName),
+		keyPath:  filepath.Join(dir, keyName),
+		interval: interval,
+		log:      l,
+	}
+	if _, err := w.reload(); err != nil {
+		return nil, errors.Wrap(err, errReadCertificate)
+	}
+	return w, nil
+}
+
+// GetCertificate returns the current certificate. It is the GetCertificate
+// function of the TLS config of the webhook server.
+func (w *CertWatcher) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.cert, nil
+}
+
+// NeedLeaderElection returns false; every replica of the provider serves
+// webhooks.
+func (w *CertWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start polls the certificate and key until the supplied context is done,
+// reloading them once they were replaced by a different, valid certificate
+// and key. A certificate whose key is yet to be replaced is not considered
+// rotated, and the previous certificate is served until it is.
+func (w *CertWatcher) Start(ctx context.Context) error {
+	if w.interval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+		rotated, err := w.reload()
+		if err != nil {
+			w.log.Debug(errReadCertificate, "error", err)
+			continue
+		}
+		if rotated {
+			w.log.Info("Webhook certificate was rotated, serving it", "cert", w.certPath)
+		}
+	}
+}
+
+// reload reads the certificate and key if they changed since they were last
+// read, returning true once a different certificate and key were loaded.
+func (w *CertWatcher) reload() (bool, error) {
+	fp, err := w.fingerprint()
+	if err != nil {
+		return false, err
+	}
+	w.mu.RLock()
+	unchanged := bytes.Equal(fp, w.last)
+	w.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	cert, err := tls.LoadX509KeyPair(w.certPath, w.keyPath)
+	if err != nil {
+		return false, err
+	}
+	w.mu.Lock()
+	w.cert, w.last = &cert, fp
+	w.mu.Unlock()
+	return true, nil
+}
+
+// fingerprint returns a digest of the certificate and key.
+func (w *CertWatcher) fingerprint() ([]byte, error) {
+	h := sha256.New()
+	for _, p := range []string{w.certPath, w.keyPath} {
+		b, err := ioutil.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return nil, err
+		}
+		_, _ = h.Write(b)
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// writeCertificate writes a new self-signed certificate and its key to the
+// supplied directory.
+func writeCertificate(t *testing.T, dir string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "provider-equinix-metal"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serving returns the leaf of the certificate served by the supplied
+// CertWatcher.
+func serving(t *testing.T, w *CertWatcher) []byte {
+	t.Helper()
+	c, err := w.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.Certificate[0]
+}
+
+// eventually reports whether the supplied condition holds within a second.
+func eventually(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCertWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := NewCertWatcher(dir, "tls.crt", "tls.key", 10*time.Millisecond, logging.NewNopLogger()); err == nil {
+		t.Errorf("NewCertWatcher(...): want an error without a certificate")
+	}
+
+	writeCertificate(t, dir)
+	w, err := NewCertWatcher(dir, "tls.crt", "tls.key", 10*time.Millisecond, logging.NewNopLogger())
+	if err != nil {
+		t.Fatalf("NewCertWatcher(...): %s", err)
+	}
+	first := serving(t, w)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Start(ctx) }()
+
+	// A certificate without its matching key is not a rotation.
+	old, err := ioutil.ReadFile(filepath.Join(dir, "tls.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeCertificate(t, dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.key"), old, 0600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if !bytes.Equal(first, serving(t, w)) {
+		t.Errorf("GetCertificate(...): want the previous certificate for a mismatched certificate and key")
+	}
+
+	writeCertificate(t, dir)
+	if !eventually(func() bool { return !bytes.Equal(first, serving(t, w)) }) {
+		t.Errorf("GetCertificate(...): want the rotated certificate")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start(...): %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Start(...): did not return once its context was done")
+	}
+}
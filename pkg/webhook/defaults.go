@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+)
+
+const (
+	// DefaultBillingCycle is the billing cycle of Devices that do not specify
+	// one.
+	DefaultBillingCycle = "hourly"
+
+	// defaultProviderConfig is used by managed resources without a
+	// providerConfigRef.
+	defaultProviderConfig = "default"
+
+	errGetProviderConfig = "cannot get ProviderConfig"
+)
+
+// A defaulter fills in the omitted fields of a managed resource at admission,
+// using the defaults of the ProviderConfig the resource references.
+type defaulter struct {
+	kube      client.Client
+	newMg     func() resource.Managed
+	defaultFn func(mg resource.Managed, pc v1beta1.ProviderConfigSpec)
+}
+
+func (d *defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if len(req.Object.Raw) == 0 {
+		return admission.Allowed("")
+	}
+	mg := d.newMg()
+	if err := json.Unmarshal(req.Object.Raw, mg); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	name := defaultProviderConfig
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		name = ref.Name
+	}
+	pc := &v1beta1.ProviderConfig{}
+	if err := d.kube.Get(ctx, types.NamespacedName{Name: name}, pc); resource.IgnoreNotFound(err) != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errGetProviderConfig))
+	}
+
+	d.defaultFn(mg, pc.Spec)
+	raw, err := json.Marshal(mg)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
+
+func defaultDevice(mg resource.Managed, pc v1beta1.ProviderConfigSpec) {
+	p := &mg.(*serverv1alpha2.Device).Spec.ForProvider
+	if p.BillingCycle == nil {
+		cycle := DefaultBillingCycle
+		p.BillingCycle = &cycle
+	}
+	// A cloned Device takes its operating system and metro from the device
+	// it clones, and a version constraint applies to the distribution the
+	// Device names.
+	if p.OS == "" && !p.Clones() && p.OSVersionConstraint == nil {
+		p.OS = pc.OperatingSystem
+	}
+	if p.Metro == "" && p.Facility == "" && !p.Clones() {
+		p.Metro = pc.Metro
+	}
+	// A Device without tags that adopts or clones a device takes the tags of
+	// that device once it is observed, which merging would prevent. The
+	// defaults are merged into them when the Device is next admitted.
+	if p.Tags == nil && (adopts(mg) || p.Clones()) {
+		return
+	}
+	p.Tags = mergeTags(p.Tags, pc.DefaultTags)
+}
+
+// adopts returns true if the supplied managed resource names an existing
+// external resource, i.e. its external name is set to other than its name.
+func adopts(mg resource.Managed) bool {
+	name := meta.GetExternalName(mg)
+	return name != "" && name != mg.GetName()
+}
+
+// mergeTags returns the supplied tags followed by the default tags they lack.
+func mergeTags(tags, defaults []string) []string {
+	has := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		has[t] = true
+	}
+	for _, t := range defaults {
+		if !has[t] {
+			tags = append(tags, t)
+			has[t] = true
+		}
+	}
+	return tags
+}
+
+func defaultVirtualNetwork(mg resource.Managed, pc v1beta1.ProviderConfigSpec) {
+	p := &mg.(*vlanv1alpha1.VirtualNetwork).Spec.ForProvider
+	if p.Metro == "" && p.Facility == "" {
+		p.Metro = pc.Metro
+	}
+}
+
+// mutatePath returns the path controller-runtime would serve the defaulting
+// webhook of the supplied kind at.
+func mutatePath(gvk schema.GroupVersionKind) string {
+	return "/mutate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+
+	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	"github.com/packethost/crossplane-provider-equinix-metal/apis/v1beta1"
+)
+
+func TestDefaultDeviceTags(t *testing.T) {
+	cases := map[string]struct {
+		tags     []string
+		defaults []string
+		want     []string
+	}{
+		"NoDefaults": {
+			tags: []string{"ci"},
+			want: []string{"ci"},
+		},
+		"NoTags": {
+			defaults: []string{"cost-center:platform"},
+			want:     []string{"cost-center:platform"},
+		},
+		"Merged": {
+			tags:     []string{"ci", "cost-center:platform"},
+			defaults: []string{"cost-center:platform", "env:dev", "env:dev"},
+			want:     []string{"ci", "cost-center:platform", "env:dev"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &serverv1alpha2.Device{}
+			d.Spec.ForProvider.Tags = tc.tags
+			defaultDevice(d, v1beta1.ProviderConfigSpec{DefaultTags: tc.defaults})
+			if diff := cmp.Diff(tc.want, d.Spec.ForProvider.Tags); diff != "" {
+				t.Errorf("defaultDevice(...): -want tags, +got tags:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultDeviceTagsOfExistingDevices(t *testing.T) {
+	cloneFrom := "2f5c8a1e-0000-4000-8000-000000000000"
+
+	cases := map[string]struct {
+		externalName string
+		cloneFrom    *string
+		tags         []string
+		want         []string
+	}{
+		"Adopted": {
+			externalName: "9d1c4c4e-0000-4000-8000-000000000000",
+		},
+		"AdoptedWithTags": {
+			externalName: "9d1c4c4e-0000-4000-8000-000000000000",
+			tags:         []string{"ci"},
+			want:         []string{"ci", "cost-center:platform"},
+		},
+		"Cloned": {
+			cloneFrom: &cloneFrom,
+		},
+		"NotYetCreated": {
+			externalName: "example",
+			want:         []string{"cost-center:platform"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := &serverv1alpha2.Device{}
+			d.SetName("example")
+			if tc.externalName != "" {
+				meta.SetExternalName(d, tc.externalName)
+			}
+			d.Spec.ForProvider.CloneFrom = tc.cloneFrom
+			d.Spec.ForProvider.Tags = tc.tags
+			defaultDevice(d, v1beta1.ProviderConfigSpec{DefaultTags: []string{"cost-center:platform"}})
+			if diff := cmp.Diff(tc.want, d.Spec.ForProvider.Tags); diff != "" {
+				t.Errorf("defaultDevice(...): -want tags, +got tags:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	errListen = "cannot listen for webhook requests"
+)
+
+// A Server serves the webhooks registered with it over TLS, with the
+// certificate of a CertWatcher. Unlike the webhook server of the manager it
+// serves rotated certificates whose files were replaced without notice,
+// without restarting.
+type Server struct {
+	*webhook.Server
+
+	certs *CertWatcher
+}
+
+// NewServer returns a Server listening on the supplied port.
+func NewServer(port int, certs *CertWatcher) *Server {
+	return &Server{
+		Server: &webhook.Server{Port: port, WebhookMux: http.NewServeMux()},
+		certs:  certs,
+	}
+}
+
+// Start serves the registered webhooks until the supplied context is done.
+func (s *Server) Start(ctx context.Context) error {
+	cfg := &tls.Config{
+		NextProtos:     []string{"h2"},
+		GetCertificate: s.certs.GetCertificate,
+	}
+	l, err := tls.Listen("tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)), cfg)
+	if err != nil {
+		return errors.Wrap(err, errListen)
+	}
+
+	srv := &http.Server{Handler: s.WebhookMux}
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+		close(done)
+	}()
+	if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	<-done
+	return nil
+}
+
+// A serverManager is a manager whose webhook server is a Server, so that the
+// webhooks registered by builders are served by it rather than by the webhook
+// server of the manager.
+type serverManager struct {
+	ctrl.Manager
+
+	srv *Server
+}
+
+// GetWebhookServer returns the webhook server of the Server.
+func (m *serverManager) GetWebhookServer() *webhook.Server {
+	return m.srv.Server
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// served returns the leaf of the certificate the server at the supplied
+// address serves.
+func served(addr string) ([]byte, error) {
+	// The certificate is self-signed, and only compared.
+	c, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close() //nolint:errcheck
+	return c.ConnectionState().PeerCertificates[0].Raw, nil
+}
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeCertificate(t, dir)
+
+	w, err := NewCertWatcher(dir, "tls.crt", "tls.key", 10*time.Millisecond, logging.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	s := NewServer(port, w)
+	s.Host = "127.0.0.1"
+	s.Register("/ok", http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) { rw.WriteHeader(http.StatusOK) }))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = w.Start(ctx) }()
+	done := make(chan error, 1)
+	go func() { done <- s.Start(ctx) }()
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+	var first []byte
+	if !eventually(func() bool { first, err = served(addr); return err == nil }) {
+		t.Fatalf("Start(...): not serving: %v", err)
+	}
+
+	// The rotated certificate is served by the same server.
+	writeCertificate(t, dir)
+	if !eventually(func() bool { c, err := served(addr); return err == nil && !bytes.Equal(first, c) }) {
+		t.Errorf("Start(...): want the rotated certificate served")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start(...): %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Start(...): did not return once its context was done")
+	}
+}
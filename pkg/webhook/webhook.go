@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package webhook registers the admission and conversion webhooks of the
+// Equinix Metal provider.
+package webhook
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	portsv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/ports/v1alpha1"
+	serverv1alpha2 "github.com/packethost/crossplane-provider-equinix-metal/apis/server/v1alpha2"
+	vlanv1alpha1 "github.com/packethost/crossplane-provider-equinix-metal/apis/vlan/v1alpha1"
+	"github.com/packethost/crossplane-provider-equinix-metal/pkg/clients"
+)
+
+// Options configure the optional webhooks.
+type Options struct {
+	// DeviceCapacity is how new Devices whose plan has no capacity in their
+	// metro or facility are admitted. Their capacity is not checked when it
+	// is empty or Off.
+	DeviceCapacity CapacityPolicy
+
+	// Port the webhook server listens on.
+	Port int
+
+	// Certs serve the certificate of the webhook server.
+	Certs *CertWatcher
+}
+
+// Setup registers the webhooks of all Equinix Metal kinds with the webhook
+// server of the supplied manager. Kinds implementing webhook.Validator are
+// validated at admission, e.g. at
+// /validate-server-metal-equinix-com-v1alpha2-device, and Devices and
+// VirtualNetworks are defaulted at /mutate-<group>-<version>-<kind>. The
+// conversion webhook is served at /convert once any kind has more than one
+// API version; CRDs opt in by setting spec.conversion.strategy to Webhook.
+// The capacity of new Devices is checked at /capacity-<group>-<version>-device
+// unless disabled by the supplied options. The webhooks are served on the
+// supplied port with the certificate of the supplied CertWatcher, rather than
+// by the webhook server of the manager.
+func Setup(mgr ctrl.Manager, o Options) error {
+	s := NewServer(o.Port, o.Certs)
+	mgr = &serverManager{Manager: mgr, srv: s}
+	for _, obj := range []runtime.Object{
+		&serverv1alpha2.Device{},
+		&vlanv1alpha1.VirtualNetwork{},
+		&portsv1alpha1.Assignment{},
+	} {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(obj).Complete(); err != nil {
+			return err
+		}
+	}
+
+	srv := mgr.GetWebhookServer()
+	srv.Register(mutatePath(serverv1alpha2.DeviceGroupVersionKind), &admission.Webhook{Handler: &defaulter{
+		kube:      mgr.GetClient(),
+		newMg:     func() resource.Managed { return &serverv1alpha2.Device{} },
+		defaultFn: defaultDevice,
+	}})
+	srv.Register(mutatePath(vlanv1alpha1.VirtualNetworkGroupVersionKind), &admission.Webhook{Handler: &defaulter{
+		kube:      mgr.GetClient(),
+		newMg:     func() resource.Managed { return &vlanv1alpha1.VirtualNetwork{} },
+		defaultFn: defaultVirtualNetwork,
+	}})
+	if o.DeviceCapacity != "" && o.DeviceCapacity != CapacityPolicyOff {
+		srv.Register(capacityPath(serverv1alpha2.DeviceGroupVersionKind), &admission.Webhook{Handler: &capacityValidator{
+			kube:          mgr.GetClient(),
+			policy:        o.DeviceCapacity,
+			credentialsFn: clients.ProviderConfigCredentials,
+			newClientFn:   newCapacityClient,
+		}})
+	}
+	if err := mgr.Add(o.Certs); err != nil {
+		return err
+	}
+	return mgr.Add(s)
+}